@@ -13,16 +13,18 @@ import (
 )
 
 const (
-	Pack    = "pack"
-	S2I     = "s2i"
-	Default = Pack
+	Pack       = "pack"
+	S2I        = "s2i"
+	Dockerfile = "dockerfile"
+	Host       = "host"
+	Default    = Pack
 )
 
 // Known builder names with a pretty-printed string representation
 type Known []string
 
 func All() Known {
-	return Known([]string{Pack, S2I})
+	return Known([]string{Pack, S2I, Dockerfile, Host})
 }
 
 func (k Known) String() string {
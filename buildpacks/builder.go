@@ -121,6 +121,10 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 	if opts.Env, err = fn.Interpolate(f.Build.BuildEnvs); err != nil {
 		return err
 	}
+	// Paths listed in .funcignore are excluded from the application source.
+	if opts.ProjectDescriptor.Build.Exclude, err = f.IgnorePatterns(); err != nil {
+		return err
+	}
 	if runtime.GOOS == "linux" {
 		opts.ContainerConfig.Network = "host"
 	}
@@ -2,6 +2,9 @@ package buildpacks
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	pack "github.com/buildpacks/pack/pkg/client"
@@ -123,6 +126,30 @@ func Test_BuildEnvs(t *testing.T) {
 	}
 }
 
+// Test_BuildExcludes ensures that the paths listed in the function's
+// .funcignore are excluded from the application source given to pack.
+func Test_BuildExcludes(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, fn.IgnoreFile), []byte("# deps\nnode_modules/\n\n*.log\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		f = fn.Function{Root: root, Runtime: "node"}
+		i = &mockImpl{}
+		b = NewBuilder(WithImpl(i))
+	)
+	i.BuildFn = func(ctx context.Context, opts pack.BuildOptions) error {
+		expected := []string{"node_modules/", "*.log"}
+		if !reflect.DeepEqual(opts.ProjectDescriptor.Build.Exclude, expected) {
+			t.Fatalf("expected excludes %v, got %v", expected, opts.ProjectDescriptor.Build.Exclude)
+		}
+		return nil
+	}
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func Test_BuildErrors(t *testing.T) {
 	testCases := []struct {
 		name, runtime, expectedErr string
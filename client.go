@@ -2,16 +2,16 @@ package function
 
 import (
 	"context"
-	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
-	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
 	"time"
+
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 )
 
 const (
@@ -41,11 +41,16 @@ type Client struct {
 	verbose           bool              // print verbose logs
 	builder           Builder           // Builds a runnable image source
 	pusher            Pusher            // Pushes function image to a remote
+	archiver          Archiver          // Saves and loads function images
+	sbomGenerator     SBOMGenerator     // Generates SBOMs of built images
 	deployer          Deployer          // Deploys or Updates a function
 	runner            Runner            // Runs the function locally
 	remover           Remover           // Removes remote services
 	lister            Lister            // Lists remote services
 	describer         Describer         // Describes function instances
+	logger            Logger            // Retrieves function instance logs
+	rollbacker        Rollbacker        // Rolls back deployed functions
+	trafficSplitter   TrafficSplitter   // Splits traffic among revisions
 	dnsProvider       DNSProvider       // Provider of DNS services
 	registry          string            // default registry for OCI image tags
 	progressListener  ProgressListener  // progress listener
@@ -54,6 +59,8 @@ type Client struct {
 	instances         *Instances        // Function Instances management
 	transport         http.RoundTripper // Customizable internal transport
 	pipelinesProvider PipelinesProvider // CI/CD pipelines management
+	profile           string            // Name of the active function profile
+	platform          string            // Target platform of builds
 }
 
 // ErrNotBuilt indicates the function has not yet been built.
@@ -65,6 +72,13 @@ var ErrNameRequired = errors.New("name required")
 // ErrRegistryRequired indicates the operation requires a registry to complete.
 var ErrRegistryRequired = errors.New("registry required")
 
+// ErrRenderNotSupported indicates the deployer is unable to render manifests.
+var ErrRenderNotSupported = errors.New("deployer does not support rendering manifests")
+
+// ErrRevisionNotFound indicates the requested revision of a deployed function
+// does not exist, or there is no previous revision to which to roll back.
+var ErrRevisionNotFound = errors.New("revision not found")
+
 // Builder of function source to runnable image.
 type Builder interface {
 	// Build a function project with source located at path.
@@ -78,6 +92,29 @@ type Pusher interface {
 	Push(ctx context.Context, f Function) (string, error)
 }
 
+// Archiver of function images to and from OCI image layouts and archives,
+// such that they may be delivered without a registry.
+type Archiver interface {
+	// Save the built image of the function to the given output, for example
+	// "oci-layout:<dir>" or "oci-archive:<file.tar>".
+	// Returns Image Digest - SHA256 hash of the image saved
+	Save(ctx context.Context, f Function, output string) (string, error)
+
+	// Load the image saved to the given OCI image layout or archive as the
+	// function's built image, into its image layout (see ImageLayout) from
+	// which it is pushed.
+	// Returns Image Digest - SHA256 hash of the image loaded
+	Load(ctx context.Context, f Function, from string) (string, error)
+}
+
+// SBOMGenerator of the software bill of materials (SBOM) of the image built
+// for a function.
+type SBOMGenerator interface {
+	// Generate the SBOM of the function's built image in the format of its
+	// build.sbom, written to its SBOM directory (see SBOMDir and SBOMFile).
+	Generate(ctx context.Context, f Function) error
+}
+
 // Deployer of function source to running status.
 type Deployer interface {
 	// Deploy a function of given name, using given backing image.
@@ -90,6 +127,26 @@ type DeploymentResult struct {
 	Namespace string
 }
 
+// Renderer is implemented by deployers which can render the manifests with
+// which they would deploy a function without applying them.
+type Renderer interface {
+	// Render the manifests of the given function.
+	Render(context.Context, Function) (Manifests, error)
+}
+
+// Manifests with which a function would be deployed.
+type Manifests struct {
+	// Objects which would be created or updated.
+	Objects []k8sruntime.Object
+
+	// Secrets, ConfigMaps and PersistentVolumeClaims referenced by the
+	// function, which are expected to exist at the time of deployment and are
+	// therefore not rendered.
+	Secrets                []string
+	ConfigMaps             []string
+	PersistentVolumeClaims []string
+}
+
 // Status of the function from the DeploymentResult
 type Status int
 
@@ -151,6 +208,90 @@ type Describer interface {
 	Describe(ctx context.Context, name string) (Instance, error)
 }
 
+// Logger of function instances.
+type Logger interface {
+	// Logs of the function's instances in the environment named by the
+	// options' Target, each line of which is passed to emit.  Unless following,
+	// returns once the logs available at the time of the call have been
+	// emitted.  When following, returns when the context is canceled.
+	// Implementations may call emit from multiple goroutines.
+	Logs(ctx context.Context, f Function, opts LogOptions, emit func(LogEntry)) error
+}
+
+// LogOptions for retrieving function logs.
+type LogOptions struct {
+	// Target environment of the instances: EnvironmentLocal or
+	// EnvironmentRemote.
+	Target string
+	// Since limits the logs to those written at or after the given time.
+	// The zero value includes all available logs.
+	Since time.Time
+	// Follow the logs of current and subsequently started instances until
+	// canceled.
+	Follow bool
+	// Revision of a deployed function whose instances' logs are retrieved.
+	// Defaults to the current (latest ready) revision.
+	Revision string
+	// Container of each instance whose logs are retrieved.  Defaults to the
+	// function's own container.
+	Container string
+}
+
+// LogEntry is a single line of output from a function instance.
+type LogEntry struct {
+	Time      time.Time `json:"time"`
+	Instance  string    `json:"instance"`
+	Container string    `json:"container,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// Rollbacker of deployed functions to their previous revisions.
+type Rollbacker interface {
+	// Revisions of the named deployed function, newest first.
+	Revisions(ctx context.Context, name string) ([]Revision, error)
+	// Rollback the named function by routing all of its traffic to the
+	// given revision, which is returned.
+	Rollback(ctx context.Context, name, revision string) (Revision, error)
+}
+
+// Revision of a deployed function.
+type Revision struct {
+	// Name of the revision.
+	Name string `json:"name" yaml:"name"`
+	// Image of the revision, including its digest when known.
+	Image string `json:"image" yaml:"image"`
+	// Created is the time at which the revision was deployed.
+	Created time.Time `json:"created" yaml:"created"`
+	// GitCommit from which the revision was deployed, when known.
+	GitCommit string `json:"gitCommit,omitempty" yaml:"gitCommit,omitempty"`
+	// Traffic is the percentage of the function's traffic routed to the
+	// revision.
+	Traffic int64 `json:"traffic" yaml:"traffic"`
+}
+
+// TrafficSplitter of deployed functions' traffic among their revisions.
+type TrafficSplitter interface {
+	// Split the named function's traffic among its revisions as given.
+	// Returns once the new split is in effect.
+	Split(ctx context.Context, name string, targets []TrafficTarget) error
+}
+
+// TrafficTarget is a share of a deployed function's traffic which is routed
+// to one of its revisions.
+type TrafficTarget struct {
+	// Revision to which traffic is routed.
+	Revision string `json:"revision,omitempty" yaml:"revision,omitempty"`
+	// Latest indicates traffic is routed to whichever revision is the latest,
+	// in which case Revision is the latest at the time of describing.
+	Latest bool `json:"latest,omitempty" yaml:"latest,omitempty"`
+	// Tag of the target, at whose URL its revision is reachable directly.
+	Tag string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	// Percent of the function's traffic routed to the revision.
+	Percent int64 `json:"percent" yaml:"percent"`
+	// URL of a tagged target.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
 // Instance data about the runtime state of a function in a given environment.
 //
 // A function instance is a logical running function space, which share
@@ -170,6 +311,13 @@ type Instance struct {
 	Image         string         `json:"image" yaml:"image"`
 	Namespace     string         `json:"namespace" yaml:"namespace"`
 	Subscriptions []Subscription `json:"subscriptions" yaml:"subscriptions"`
+	// Traffic split of a deployed function among its revisions.
+	Traffic []TrafficTarget `json:"traffic,omitempty" yaml:"traffic,omitempty"`
+	// Timeouts of requests to a deployed function, where set.
+	Timeouts *TimeoutsOptions `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
+	// Profile is the name of the function profile in effect when describing,
+	// if any.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
 }
 
 // Subscriptions currently active to event sources
@@ -181,8 +329,12 @@ type Subscription struct {
 
 // DNSProvider exposes DNS services necessary for serving the function.
 type DNSProvider interface {
-	// Provide the given name by routing requests to address.
-	Provide(Function) error
+	// Provide the domains of the deployed function by routing requests for
+	// them to it, removing those of its domains which are no longer listed.
+	Provide(context.Context, Function) error
+
+	// Remove the domains of the named function.
+	Remove(context.Context, string) error
 }
 
 // PipelinesProvider manages lifecyle of CI/CD pipelines used by a function
@@ -197,11 +349,16 @@ func New(options ...Option) *Client {
 	c := &Client{
 		builder:           &noopBuilder{output: os.Stdout},
 		pusher:            &noopPusher{output: os.Stdout},
+		archiver:          &noopArchiver{},
+		sbomGenerator:     &noopSBOMGenerator{},
 		deployer:          &noopDeployer{output: os.Stdout},
 		runner:            &noopRunner{output: os.Stdout},
 		remover:           &noopRemover{output: os.Stdout},
 		lister:            &noopLister{output: os.Stdout},
 		describer:         &noopDescriber{output: os.Stdout},
+		logger:            &noopLogger{},
+		rollbacker:        &noopRollbacker{},
+		trafficSplitter:   &noopTrafficSplitter{},
 		dnsProvider:       &noopDNSProvider{output: os.Stdout},
 		progressListener:  &NoopProgressListener{},
 		pipelinesProvider: &noopPipelinesProvider{},
@@ -262,6 +419,21 @@ func WithPusher(d Pusher) Option {
 	}
 }
 
+// WithArchiver provides the concrete implementation of an archiver.
+func WithArchiver(a Archiver) Option {
+	return func(c *Client) {
+		c.archiver = a
+	}
+}
+
+// WithSBOMGenerator provides the concrete implementation of an SBOM
+// generator.
+func WithSBOMGenerator(g SBOMGenerator) Option {
+	return func(c *Client) {
+		c.sbomGenerator = g
+	}
+}
+
 // WithDeployer provides the concrete implementation of a deployer.
 func WithDeployer(d Deployer) Option {
 	return func(c *Client) {
@@ -297,6 +469,28 @@ func WithDescriber(describer Describer) Option {
 	}
 }
 
+// WithLogger provides a concrete implementation of a function logger.
+func WithLogger(logger Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithRollbacker provides a concrete implementation of a function rollbacker.
+func WithRollbacker(rollbacker Rollbacker) Option {
+	return func(c *Client) {
+		c.rollbacker = rollbacker
+	}
+}
+
+// WithTrafficSplitter provides a concrete implementation of a splitter of
+// function traffic.
+func WithTrafficSplitter(s TrafficSplitter) Option {
+	return func(c *Client) {
+		c.trafficSplitter = s
+	}
+}
+
 // WithProgressListener provides a concrete implementation of a listener to
 // be notified of progress updates.
 func WithProgressListener(p ProgressListener) Option {
@@ -356,6 +550,24 @@ func WithPipelinesProvider(pp PipelinesProvider) Option {
 	}
 }
 
+// WithProfile sets the name of the function profile whose overrides are
+// applied to functions prior to their being built, deployed or described.
+// The empty string (the default) uses the function's base configuration.
+func WithProfile(name string) Option {
+	return func(c *Client) {
+		c.profile = name
+	}
+}
+
+// WithPlatform sets the target platform (for example "linux/amd64") of
+// builds.  It is recorded in the build fingerprint such that a change of
+// platform marks the function as requiring a rebuild.
+func WithPlatform(platform string) Option {
+	return func(c *Client) {
+		c.platform = platform
+	}
+}
+
 // ACCESSORS
 // ---------
 
@@ -374,6 +586,11 @@ func (c *Client) Instances() *Instances {
 	return c.instances
 }
 
+// Profile accessor returns the name of the active function profile, if any.
+func (c *Client) Profile() string {
+	return c.profile
+}
+
 // Repository accessor returns the default registry for use when building
 // Functions which do not specify Registry or Image name explicitly.
 func (c *Client) Registry() string {
@@ -451,12 +668,6 @@ func (c *Client) New(ctx context.Context, cfg Function) (err error) {
 		return
 	}
 
-	// Create an external route to the function
-	c.progressListener.Increment("Creating route to function")
-	if err = c.Route(f.Root); err != nil {
-		return
-	}
-
 	c.progressListener.Complete("Done")
 
 	// TODO: use the knative client during deployment such that the actual final
@@ -541,11 +752,11 @@ func (c *Client) Create(cfg Function) (err error) {
 // a container image in the cache of the the configured builder, thus this info
 // is placed in a .func (non-source controlled) local metadata directory, which
 // is not stritly required to exist, so it is created if needed.
-func updateBuildStamp(f Function) (err error) {
+func updateBuildStamp(f Function, platform string) (err error) {
 	if err = ensureRuntimeDir(f); err != nil {
 		return err
 	}
-	hash, err := fingerprint(f)
+	hash, err := fingerprint(f, platform)
 	if err != nil {
 		return err
 	}
@@ -597,36 +808,60 @@ func (c *Client) Build(ctx context.Context, path string) (err error) {
 		f.Registry = c.registry
 	}
 
-	// If no image name has been yet defined (not yet built/deployed), calculate.
-	// Image name is stored on the function for later use by deploy, etc.
-	// TODO: write this to .func/build instead, and populate f.Image on deploy
-	// such that local builds do not dirty the work tree.
-	if f.Image == "" {
-		if f.Image, err = f.ImageName(); err != nil {
+	// The active profile may override values such as the registry.  The
+	// profiled function is only used in-memory.
+	pf, err := f.ApplyProfile(c.profile)
+	if err != nil {
+		return
+	}
+
+	// If no image name has been explicitly defined, calculate.
+	if pf.Image == "" {
+		if pf.Image, err = pf.ImageName(); err != nil {
 			return
 		}
 	}
 
-	if err = c.builder.Build(ctx, f); err != nil {
+	// Daemonless builders write the image to an OCI layout in .func.  That
+	// of a prior build is removed such that it is not pushed in place of the
+	// image built now by a builder which uses a daemon.
+	if err = os.RemoveAll(f.ImageLayout()); err != nil {
+		return
+	}
+
+	// The SBOM of a prior build no longer describes the image.
+	if err = os.RemoveAll(f.SBOMDir()); err != nil {
 		return
 	}
 
-	// Write (save) - Serialize the function to disk
-	// Will now contain populated image tag.
-	if err = f.Write(); err != nil {
+	if err = c.builder.Build(ctx, pf); err != nil {
+		return
+	}
+
+	// Generate the software bill of materials of the image, if enabled.
+	if pf.Build.SBOM != "" {
+		if err = c.sbomGenerator.Generate(ctx, pf); err != nil {
+			return fmt.Errorf("cannot generate the SBOM of the function image: %w", err)
+		}
+	}
+
+	// Record the built image for later use by push, deploy, etc.  This is
+	// local state in .func such that building does not modify func.yaml.
+	f.Built = BuiltImage{Image: pf.Image}
+	if err = f.writeBuiltImage(); err != nil {
 		return
 	}
 
 	// Tag the function as having been built
-	if err = updateBuildStamp(f); err != nil {
+	if err = updateBuildStamp(f, c.platform); err != nil {
 		return
 	}
 
 	// TODO: create a status structure and return it here for optional
 	// use by the cli for user echo (rather than rely on verbose mode here)
-	message := fmt.Sprintf("🙌 Function image built: %v", f.Image)
+	message := fmt.Sprintf("🙌 Function image built: %v", pf.Image)
 	if runtime.GOOS == "windows" {
-		message = fmt.Sprintf("Function image built: %v", f.Image)
+		message = fmt.Sprintf("Function image built: %v", pf.Image)
 	}
 	c.progressListener.Increment(message)
 	return
@@ -660,7 +895,8 @@ func (c *Client) printBuildActivity(ctx context.Context) {
 }
 
 // Deploy the function at path. Errors if the function has not been
-// initialized with an image tag.
+// built or initialized with an image tag.  The deployed image is recorded
+// in the function's func.yaml.
 func (c *Client) Deploy(ctx context.Context, path string) (err error) {
 	go func() {
 		<-ctx.Done()
@@ -684,23 +920,94 @@ func (c *Client) Deploy(ctx context.Context, path string) (err error) {
 		return ErrNameRequired
 	}
 
+	// Overlay the active profile (if any) onto the function being deployed,
+	// using the image of the most recent build unless explicitly provided,
+	// and recording the commit from which it is deployed.
+	pf, err := f.ApplyProfile(c.profile)
+	if err != nil {
+		return
+	}
+	pf = pf.withBuiltImage().withGitCommit()
+
+	// The function is validated as deployed, such as that its domains are
+	// supported by its deployer, before any change is made to the cluster.
+	if err = pf.Validate(); err != nil {
+		return
+	}
+
 	// Deploy a new or Update the previously-deployed function
 	c.progressListener.Increment("⬆️  Deploying function to the cluster")
-	result, err := c.deployer.Deploy(ctx, f)
+	result, err := c.deployer.Deploy(ctx, pf)
 
+	// Functions deployed outside of a cluster, such as to local containers,
+	// have no namespace.
+	where := ""
+	if result.Namespace != "" {
+		where = fmt.Sprintf(" in namespace %q", result.Namespace)
+	}
 	if result.Status == Deployed {
-		c.progressListener.Increment(fmt.Sprintf("✅ Function deployed in namespace %q and exposed at URL: \n   %v", result.Namespace, result.URL))
+		c.progressListener.Increment(fmt.Sprintf("✅ Function deployed%v and exposed at URL: \n   %v", where, result.URL))
 	} else if result.Status == Updated {
-		c.progressListener.Increment(fmt.Sprintf("✅ Function updated in namespace %q and exposed at URL: \n   %v", result.Namespace, result.URL))
+		c.progressListener.Increment(fmt.Sprintf("✅ Function updated%v and exposed at URL: \n   %v", where, result.URL))
+	}
+	if err != nil {
+		return
+	}
+
+	// Route the function's custom domains to it when deployed to a cluster,
+	// in the namespace to which it was deployed.
+	if result.Namespace != "" {
+		if len(pf.Deploy.Domains) > 0 {
+			c.progressListener.Increment("🌐 Mapping custom domains to the function")
+		}
+		pf.Deploy.Namespace = result.Namespace
+		if err = c.dnsProvider.Provide(ctx, pf); err != nil {
+			return
+		}
 	}
 
-	return err
+	// Record the deployed image (and registry from which it was derived)
+	if f.Registry == "" {
+		f.Registry = c.registry
+	}
+	f.Deploy.Image = pf.ImageWithDigest()
+	return f.Write()
+}
+
+// Render the manifests with which the function would be deployed, without
+// deploying it.  The function need not have been built, in which case the
+// image into which it would be built is used.  Requires a deployer which
+// implements Renderer.
+func (c *Client) Render(ctx context.Context, f Function) (m Manifests, err error) {
+	renderer, ok := c.deployer.(Renderer)
+	if !ok {
+		return m, ErrRenderNotSupported
+	}
+	if f.Name == "" {
+		return m, ErrNameRequired
+	}
+
+	// Overlay the active profile as for a deploy
+	pf, err := f.ApplyProfile(c.profile)
+	if err != nil {
+		return
+	}
+	pf = pf.withBuiltImage().withGitCommit()
+	if !pf.HasImage() {
+		if pf.Registry == "" {
+			pf.Registry = c.registry
+		}
+		if pf.Image, err = pf.ImageName(); err != nil {
+			return
+		}
+	}
+	return renderer.Render(ctx, pf)
 }
 
 // RunPipeline runs a Pipeline to build and deploy the function.
-// Returned function contains applicable registry and deployed image name.
+// Returned function contains applicable registry and deployed image name
+// (see DeploySpec.Image).
 func (c *Client) RunPipeline(ctx context.Context, f Function) (Function, error) {
-	var err error
 	go func() {
 		<-ctx.Done()
 		c.progressListener.Stopping()
@@ -711,23 +1018,35 @@ func (c *Client) RunPipeline(ctx context.Context, f Function) (Function, error)
 		f.Registry = c.registry
 	}
 
-	// If no image name has been yet defined (not yet built/deployed), calculate.
-	// Image name is stored on the function for later use by deploy, etc.
-	if f.Image == "" {
-		if f.Image, err = f.ImageName(); err != nil {
+	// Overlay the active profile (if any) onto the function being deployed,
+	// which is built for the client's platform.
+	pf, err := f.ApplyProfile(c.profile)
+	if err != nil {
+		return f, err
+	}
+	pf.Build.Platform = c.platform
+
+	// If no image name has been explicitly defined, calculate.
+	if pf.Image == "" {
+		if pf.Image, err = pf.ImageName(); err != nil {
 			return f, err
 		}
 	}
 
 	// Build and deploy function using Pipeline
-	if err := c.pipelinesProvider.Run(ctx, f); err != nil {
+	if err := c.pipelinesProvider.Run(ctx, pf); err != nil {
 		return f, fmt.Errorf("failed to run pipeline: %w", err)
 	}
 
+	// Record the deployed image
+	f.Deploy.Image = pf.Image
 	return f, nil
 }
 
-func (c *Client) Route(path string) (err error) {
+// Route requests for the custom domains of the function at path to it.
+// Domains are routed as part of a deploy; this routes those of a function
+// which is already deployed.
+func (c *Client) Route(ctx context.Context, path string) (err error) {
 	// Ensure that the allocated final address is enabled with the
 	// configured DNS provider.
 	// NOTE:
@@ -739,12 +1058,33 @@ func (c *Client) Route(path string) (err error) {
 	if err != nil {
 		return
 	}
-	return c.dnsProvider.Provide(f)
+	return c.dnsProvider.Provide(ctx, f)
+}
+
+// RunOption configures a single run of a function.
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	port string
+}
+
+// RunWithPort runs the function on the given host port rather than one chosen
+// by the runner.  Used, for example, to restart a function on the port of its
+// previous instance.
+func RunWithPort(port string) RunOption {
+	return func(o *runOptions) {
+		o.port = port
+	}
 }
 
 // Run the function whose code resides at root.
 // On start, the chosen port is sent to the provided started channel
-func (c *Client) Run(ctx context.Context, root string) (job *Job, err error) {
+func (c *Client) Run(ctx context.Context, root string, options ...RunOption) (job *Job, err error) {
+	var opts runOptions
+	for _, o := range options {
+		o(&opts)
+	}
+
 	go func() {
 		<-ctx.Done()
 		c.progressListener.Stopping()
@@ -764,7 +1104,8 @@ func (c *Client) Run(ctx context.Context, root string) (job *Job, err error) {
 
 	// Run the function, which returns a Job for use interacting (at arms length)
 	// with that running task (which is likely inside a container process).
-	if job, err = c.runner.Run(ctx, f); err != nil {
+	f.Run.Port = opts.port
+	if job, err = c.runner.Run(ctx, f.withBuiltImage()); err != nil {
 		return
 	}
 
@@ -796,7 +1137,173 @@ func (c *Client) Describe(ctx context.Context, name, root string) (d Instance, e
 	if f.Name == "" {
 		return d, fmt.Errorf("unable to describe without a name. %v", ErrNameRequired)
 	}
-	return c.describer.Describe(ctx, f.Name)
+	// Describe the function as deployed with the active profile, if any.
+	if f, err = f.ApplyProfile(c.profile); err != nil {
+		return d, err
+	}
+	if d, err = c.describer.Describe(ctx, f.Name); err != nil {
+		return
+	}
+	d.Profile = c.profile
+	return
+}
+
+// Logs of the function defined at root, each line of which is passed to
+// emit.  The options' Target may be EnvironmentLocal or EnvironmentRemote.
+// If not provided, a locally running instance is preferred, with the logs of
+// the deployed function retrieved if there is no locally running instance.
+func (c *Client) Logs(ctx context.Context, root string, opts LogOptions, emit func(LogEntry)) error {
+	f, err := NewFunction(root)
+	if err != nil {
+		return err
+	}
+	if !f.Initialized() {
+		return fmt.Errorf("function not initialized: %v", root)
+	}
+	if f.Name == "" {
+		return fmt.Errorf("unable to retrieve logs without a name. %v", ErrNameRequired)
+	}
+	switch opts.Target {
+	case "":
+		opts.Target = EnvironmentRemote
+		if len(jobPorts(f)) > 0 {
+			opts.Target = EnvironmentLocal
+		}
+	case EnvironmentLocal:
+		if len(jobPorts(f)) == 0 {
+			return ErrNotRunning
+		}
+	case EnvironmentRemote:
+	default:
+		return ErrEnvironmentNotFound
+	}
+	if opts.Target == EnvironmentLocal && opts.Revision != "" {
+		return errors.New("revisions apply only to deployed functions")
+	}
+	return c.logger.Logs(ctx, f, opts, emit)
+}
+
+// Revisions of the deployed function defined at root, newest first.
+func (c *Client) Revisions(ctx context.Context, root string) ([]Revision, error) {
+	f, err := NewFunction(root)
+	if err != nil {
+		return nil, err
+	}
+	if !f.Initialized() {
+		return nil, fmt.Errorf("function not initialized: %v", root)
+	}
+	if f.Name == "" {
+		return nil, fmt.Errorf("unable to list revisions without a name. %v", ErrNameRequired)
+	}
+	return c.rollbacker.Revisions(ctx, f.Name)
+}
+
+// Rollback the deployed function defined at root by routing all of its
+// traffic to the named revision.  If no revision is provided, the revision
+// prior to the one currently serving is used.  The function's deployed image
+// is updated to that of the revision.
+func (c *Client) Rollback(ctx context.Context, root, revision string) (r Revision, err error) {
+	f, err := NewFunction(root)
+	if err != nil {
+		return
+	}
+	if !f.Initialized() {
+		return r, fmt.Errorf("function not initialized: %v", root)
+	}
+	if f.Name == "" {
+		return r, fmt.Errorf("unable to roll back without a name. %v", ErrNameRequired)
+	}
+	if revision == "" {
+		var revisions []Revision
+		if revisions, err = c.rollbacker.Revisions(ctx, f.Name); err != nil {
+			return
+		}
+		if revision, err = previousRevision(revisions); err != nil {
+			return
+		}
+	}
+	if r, err = c.rollbacker.Rollback(ctx, f.Name, revision); err != nil {
+		return
+	}
+	f.Deploy.Image = r.Image
+	return r, f.Write()
+}
+
+// previousRevision returns the name of the revision deployed immediately
+// prior to the newest revision currently receiving traffic.
+func previousRevision(revisions []Revision) (string, error) {
+	for i, r := range revisions {
+		if r.Traffic == 0 {
+			continue
+		}
+		if i+1 < len(revisions) {
+			return revisions[i+1].Name, nil
+		}
+		break
+	}
+	return "", fmt.Errorf("%w: no revision prior to the current revision", ErrRevisionNotFound)
+}
+
+// Traffic split of the deployed function defined at root among its
+// revisions.
+func (c *Client) Traffic(ctx context.Context, root string) ([]TrafficTarget, error) {
+	f, err := NewFunction(root)
+	if err != nil {
+		return nil, err
+	}
+	if !f.Initialized() {
+		return nil, fmt.Errorf("function not initialized: %v", root)
+	}
+	if f.Name == "" {
+		return nil, fmt.Errorf("unable to describe traffic without a name. %v", ErrNameRequired)
+	}
+	i, err := c.describer.Describe(ctx, f.Name)
+	if err != nil {
+		return nil, err
+	}
+	return i.Traffic, nil
+}
+
+// SetTraffic of the deployed function defined at root to the given
+// percentages, keyed by tag or revision name, which must total 100.  Tagged
+// targets not given retain their tag but receive no traffic.  The resultant
+// split is returned.
+func (c *Client) SetTraffic(ctx context.Context, root string, percents map[string]int64) ([]TrafficTarget, error) {
+	current, err := c.Traffic(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := setTraffic(current, percents)
+	if err != nil {
+		return nil, err
+	}
+	return targets, c.split(ctx, root, targets)
+}
+
+// Promote the traffic target of the deployed function defined at root,
+// identified by tag or revision name, by increasing its share of traffic by
+// step percent.  The shares of the other targets are decreased in proportion.
+// A step of zero promotes the target to receive all traffic.  The resultant
+// split is returned.
+func (c *Client) Promote(ctx context.Context, root, target string, step int64) ([]TrafficTarget, error) {
+	current, err := c.Traffic(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := promoteTraffic(current, target, step)
+	if err != nil {
+		return nil, err
+	}
+	return targets, c.split(ctx, root, targets)
+}
+
+// split the traffic of the function defined at root.
+func (c *Client) split(ctx context.Context, root string, targets []TrafficTarget) error {
+	f, err := NewFunction(root)
+	if err != nil {
+		return err
+	}
+	return c.trafficSplitter.Split(ctx, f.Name, targets)
 }
 
 // List currently deployed functions.
@@ -844,6 +1351,9 @@ func (c *Client) Remove(ctx context.Context, cfg Function, deleteAll bool) error
 	}
 
 	errService := <-errChan
+	if errService == nil {
+		errService = c.dnsProvider.Remove(ctx, functionName)
+	}
 
 	if errService != nil && errResources != nil {
 		return fmt.Errorf("%s\n%s", errService, errResources)
@@ -893,6 +1403,7 @@ func (c *Client) Push(ctx context.Context, path string) (err error) {
 	if !f.HasImage() {
 		return ErrNotBuilt
 	}
+	f = f.withBuiltImage()
 
 	imageDigest, err := c.pusher.Push(ctx, f)
 	if err != nil {
@@ -900,8 +1411,71 @@ func (c *Client) Push(ctx context.Context, path string) (err error) {
 	}
 
 	// Record the Image Digest pushed.
-	f.ImageDigest = imageDigest
-	return f.Write()
+	f.Built = BuiltImage{Image: f.Image, Digest: imageDigest}
+	return f.writeBuiltImage()
+}
+
+// Save the built image of the function at path to the given output, an OCI
+// image layout ("oci-layout:<dir>") or archive ("oci-archive:<file.tar>"),
+// such that it may be delivered without a registry.
+func (c *Client) Save(ctx context.Context, path, output string) (err error) {
+	f, err := NewFunction(path)
+	if err != nil {
+		return
+	}
+
+	if !f.HasImage() {
+		return ErrNotBuilt
+	}
+	f = f.withBuiltImage()
+
+	imageDigest, err := c.archiver.Save(ctx, f, output)
+	if err != nil {
+		return
+	}
+	c.progressListener.Increment(fmt.Sprintf("Function image saved to %v (%v)", output, imageDigest))
+	return
+}
+
+// Load the image saved to the given OCI image layout or archive as the built
+// image of the function at path, such that it is pushed with Push.  The image
+// is named as if built now, and its digest is unchanged when pushed.
+func (c *Client) Load(ctx context.Context, path, from string) (err error) {
+	f, err := NewFunction(path)
+	if err != nil {
+		return
+	}
+
+	// Default function registry to the client's global registry
+	if f.Registry == "" {
+		f.Registry = c.registry
+	}
+
+	// The image is that which would be built now.
+	pf, err := f.ApplyProfile(c.profile)
+	if err != nil {
+		return
+	}
+	if pf.Image == "" {
+		if pf.Image, err = pf.ImageName(); err != nil {
+			return
+		}
+	}
+
+	if _, err = c.archiver.Load(ctx, pf, from); err != nil {
+		return
+	}
+
+	// The SBOM of a prior build does not describe the image loaded.
+	if err = os.RemoveAll(f.SBOMDir()); err != nil {
+		return
+	}
+
+	// Record the loaded image as that built, for use by push.  The function
+	// is not stamped as built, as its source may differ from that of the
+	// image.
+	f.Built = BuiltImage{Image: pf.Image}
+	return f.writeBuiltImage()
 }
 
 // Built returns true if the given path contains a function which has been
@@ -912,32 +1486,39 @@ func (c *Client) Built(path string) bool {
 		return false
 	}
 
-	// Missing a build image always means !Built (but does not satisfy staleness
-	// checks).
-	// NOTE: This will be updated in the future such that a build does not
-	// automatically update the function's serialized, source-controlled state,
-	// because merely building does not indicate the function has changed, but
-	// rather that field should be populated on deploy.  I.e. the Image name
-	// and image stamp should reside as transient data in .func until such time
-	// as the given image has been deployed.
-	// An example of how this bug manifests is that every rebuild of a function
-	// registers the func.yaml as being dirty for source-control purposes, when
-	// this should only happen on deploy.
-	if !f.HasImage() {
+	// Missing a built image always means !Built (but does not satisfy
+	// staleness checks).
+	if f.Built.Image == "" {
+		return false
+	}
+
+	// The image built must be that which would be built now.  This differs
+	// when the explicit image, registry or active profile has since changed.
+	if f.Registry == "" {
+		f.Registry = c.registry
+	}
+	pf, err := f.ApplyProfile(c.profile)
+	if err != nil {
+		return false
+	}
+	if pf.Image == "" {
+		if pf.Image, err = pf.ImageName(); err != nil {
+			return false
+		}
+	}
+	if pf.Image != f.Built.Image {
 		return false
 	}
 
 	buildstampPath := filepath.Join(path, RunDataDir, buildstamp)
 
 	// If there is no build stamp, it is also not built.
-	// This case should be redundant with the above check for an image, but is
-	// temporarily necessary (see the long-winded caviat note above).
 	if _, err := os.Stat(buildstampPath); err != nil {
 		return false
 	}
 
 	// Calculate the function's Filesystem hash and see if it has changed.
-	hash, err := fingerprint(f)
+	hash, err := fingerprint(f, c.platform)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error calculating function's fingerprint: %v\n", err)
 		return false
@@ -959,24 +1540,6 @@ func (c *Client) Built(path string) bool {
 	return true
 }
 
-// fingerprint returns a hash of the filenames and modification timestamps of
-// the files within a function's root.
-func fingerprint(f Function) (string, error) {
-	h := sha256.New()
-	err := filepath.Walk(f.Root, func(path string, info fs.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		// Always ignore .func, .git (TODO: .funcignore)
-		if info.IsDir() && (info.Name() == RunDataDir || info.Name() == ".git") {
-			return filepath.SkipDir
-		}
-		fmt.Fprintf(h, "%v:%v:", path, info.ModTime().UnixNano())
-		return nil
-	})
-	return fmt.Sprintf("%x", h.Sum(nil)), err
-}
-
 // DEFAULTS
 // ---------
 
@@ -1002,6 +1565,18 @@ type noopPusher struct{ output io.Writer }
 
 func (n *noopPusher) Push(ctx context.Context, f Function) (string, error) { return "", nil }
 
+// Archiver
+type noopArchiver struct{}
+
+func (n *noopArchiver) Save(context.Context, Function, string) (string, error) { return "", nil }
+
+func (n *noopArchiver) Load(context.Context, Function, string) (string, error) { return "", nil }
+
+// SBOMGenerator
+type noopSBOMGenerator struct{}
+
+func (n *noopSBOMGenerator) Generate(context.Context, Function) error { return nil }
+
 // Deployer
 type noopDeployer struct{ output io.Writer }
 
@@ -1009,6 +1584,10 @@ func (n *noopDeployer) Deploy(ctx context.Context, _ Function) (DeploymentResult
 	return DeploymentResult{}, nil
 }
 
+func (n *noopDeployer) Render(ctx context.Context, _ Function) (Manifests, error) {
+	return Manifests{}, nil
+}
+
 // Runner
 type noopRunner struct{ output io.Writer }
 
@@ -1033,6 +1612,29 @@ func (n *noopDescriber) Describe(context.Context, string) (Instance, error) {
 	return Instance{}, nil
 }
 
+// Logger
+type noopLogger struct{}
+
+func (n *noopLogger) Logs(context.Context, Function, LogOptions, func(LogEntry)) error {
+	return nil
+}
+
+// Rollbacker
+type noopRollbacker struct{}
+
+func (n *noopRollbacker) Revisions(context.Context, string) ([]Revision, error) {
+	return []Revision{}, nil
+}
+
+func (n *noopRollbacker) Rollback(context.Context, string, string) (Revision, error) {
+	return Revision{}, ErrRevisionNotFound
+}
+
+// TrafficSplitter
+type noopTrafficSplitter struct{}
+
+func (n *noopTrafficSplitter) Split(context.Context, string, []TrafficTarget) error { return nil }
+
 // PipelinesProvider
 type noopPipelinesProvider struct{}
 
@@ -1042,7 +1644,8 @@ func (n *noopPipelinesProvider) Remove(ctx context.Context, _ Function) error {
 // DNSProvider
 type noopDNSProvider struct{ output io.Writer }
 
-func (n *noopDNSProvider) Provide(_ Function) error { return nil }
+func (n *noopDNSProvider) Provide(_ context.Context, _ Function) error { return nil }
+func (n *noopDNSProvider) Remove(_ context.Context, _ string) error    { return nil }
 
 // ProgressListener
 type NoopProgressListener struct{}
@@ -364,8 +364,8 @@ func TestClient_New_ImageNamePopulated(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if f.Image != imageTag {
-		t.Fatalf("expected image '%v' got '%v'", imageTag, f.Image)
+	if f.Built.Image != imageTag {
+		t.Fatalf("expected image '%v' got '%v'", imageTag, f.Built.Image)
 	}
 }
 
@@ -393,8 +393,8 @@ func TestClient_New_ImageRegistryDefaults(t *testing.T) {
 
 	// Expected image is [DefaultRegistry]/[namespace]/[servicename]:latest
 	expected := fn.DefaultRegistry + "/alice/" + f.Name + ":latest"
-	if f.Image != expected {
-		t.Fatalf("expected image '%v' got '%v'", expected, f.Image)
+	if f.Built.Image != expected {
+		t.Fatalf("expected image '%v' got '%v'", expected, f.Built.Image)
 	}
 }
 
@@ -657,16 +657,16 @@ func TestClient_Update(t *testing.T) {
 	}
 }
 
-// TestClient_Deploy_RegistryUpdate ensures that deploying a Function updates
-// its image member on initial deploy, and on subsequent deploys only
-// if reset to it zero value.
+// TestClient_Deploy_RegistryUpdate ensures that the image built and deployed
+// is derived from the function's current registry, as the image of a build is
+// not persisted to func.yaml, unless the image member is explicitly set.
 func TestClient_Deploy_RegistryUpdate(t *testing.T) {
 	root, rm := Mktemp(t)
 	defer rm()
 	client := fn.New(fn.WithRegistry("example.com/alice"))
 
 	// New runs build and deploy, thus the initial instantiation should result in
-	// the member being populated from the client's registry and function name.
+	// the built image being derived from the client's registry and function name.
 	if err := client.New(context.Background(), fn.Function{Runtime: "go", Name: "f", Root: root}); err != nil {
 		t.Fatal(err)
 	}
@@ -674,13 +674,15 @@ func TestClient_Deploy_RegistryUpdate(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if f.Image != "example.com/alice/f:latest" {
-		t.Error("image name was not initially set")
+	if f.Built.Image != "example.com/alice/f:latest" {
+		t.Errorf("built image name was not initially set, got '%v'", f.Built.Image)
+	}
+	if f.Image != "" {
+		t.Errorf("expected image member to remain unset, but got '%v'", f.Image)
 	}
 
-	// Updating the registry and performing a subsequent update should not result
-	// in the image member being updated to the new value: registry is only used
-	// when calculating a nonexistent value
+	// Updating the registry and performing a subsequent update results in the
+	// image being derived from the new value.
 	f.Registry = "example.com/bob"
 	if err := f.Write(); err != nil {
 		t.Fatal(err)
@@ -691,18 +693,17 @@ func TestClient_Deploy_RegistryUpdate(t *testing.T) {
 	if err := client.Deploy(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
-	expected := "example.com/alice/f:latest"
+	expected := "example.com/bob/f:latest"
 	f, err = fn.NewFunction(root) // reload and check
 	if err != nil {
 		t.Fatal(err)
 	}
-	if f.Image != expected { // NOT changed to bob
-		t.Errorf("expected image name to stay '%v' and not be updated, but got '%v'", expected, f.Image)
+	if f.Built.Image != expected || f.Deploy.Image != expected {
+		t.Errorf("expected image '%v' to be built and deployed, but got '%v' and '%v'", expected, f.Built.Image, f.Deploy.Image)
 	}
 
-	// Reset the value of .Image to default "" and ensure this triggers recalc.
-	f.Image = ""
-	f.Registry = "example.com/bob"
+	// An explicit value of .Image takes precedence over the registry.
+	f.Image = "example.com/charlie/f:latest"
 	if err := f.Write(); err != nil {
 		t.Fatal(err)
 	}
@@ -712,14 +713,13 @@ func TestClient_Deploy_RegistryUpdate(t *testing.T) {
 	if err := client.Deploy(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
-	expected = "example.com/bob/f:latest"
+	expected = "example.com/charlie/f:latest"
 	f, err = fn.NewFunction(root)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if f.Image != expected { // DOES change to bob
-		t.Errorf("expected image name to stay '%v' and not be updated, but got '%v'", expected, f.Image)
-
+	if f.Built.Image != expected || f.Deploy.Image != expected {
+		t.Errorf("expected image '%v' to be built and deployed, but got '%v' and '%v'", expected, f.Built.Image, f.Deploy.Image)
 	}
 }
 
@@ -949,7 +949,7 @@ func TestClient_List_OutsideRoot(t *testing.T) {
 	}
 }
 
-// TestClient_Deploy_Image ensures that initially the function's image
+// TestClient_Deploy_Image ensures that initially the function's deployed image
 // member has no value (not initially deployed); the value is populated
 // upon deployment with a value derived from the function's name and currently
 // effective client registry; that the value of f.Image will take precedence
@@ -990,8 +990,8 @@ func TestClient_Deploy_Image(t *testing.T) {
 		t.Fatal(err)
 	}
 	expected := "example.com/alice/myfunc:latest"
-	if f.Image != expected {
-		t.Fatalf("expected image '%v', got '%v'", expected, f.Image)
+	if f.Deploy.Image != expected {
+		t.Fatalf("expected image '%v', got '%v'", expected, f.Deploy.Image)
 	}
 	expected = "example.com/alice"
 	if f.Registry != "example.com/alice" {
@@ -1009,9 +1009,12 @@ func TestClient_Deploy_Image(t *testing.T) {
 	if err = client.Deploy(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
+	if f, err = fn.NewFunction(root); err != nil {
+		t.Fatal(err)
+	}
 	expected = "registry2.example.com/bob/myfunc:latest"
-	if f.Image != expected {
-		t.Fatalf("expected image '%v', got '%v'", expected, f.Image)
+	if f.Deploy.Image != expected {
+		t.Fatalf("expected image '%v', got '%v'", expected, f.Deploy.Image)
 	}
 	expected = "example.com/alice"
 	if f.Registry != "example.com/alice" {
@@ -1026,8 +1029,8 @@ func TestClient_Deploy_Image(t *testing.T) {
 	}
 }
 
-// TestClient_Pipelines_Deploy_Image ensures that initially the function's image
-// member has no value (not initially deployed); the value is populated
+// TestClient_Pipelines_Deploy_Image ensures that initially the function's
+// deployed image member has no value (not initially deployed); the value is populated
 // upon pipeline run execution with a value derived from the function's name and currently
 // effective client registry; that the value of f.Image will take precedence
 // over .Registry, which is used to calculate a default value for image.
@@ -1068,8 +1071,8 @@ func TestClient_Pipelines_Deploy_Image(t *testing.T) {
 		t.Fatal(err)
 	}
 	expected := "example.com/alice/myfunc:latest"
-	if f.Image != expected {
-		t.Fatalf("expected image '%v', got '%v'", expected, f.Image)
+	if f.Deploy.Image != expected {
+		t.Fatalf("expected image '%v', got '%v'", expected, f.Deploy.Image)
 	}
 	expected = "example.com/alice"
 	if f.Registry != expected {
@@ -1086,8 +1089,8 @@ func TestClient_Pipelines_Deploy_Image(t *testing.T) {
 		t.Fatal(err)
 	}
 	expected = "registry2.example.com/bob/myfunc:latest"
-	if f.Image != expected {
-		t.Fatalf("expected image '%v', got '%v'", expected, f.Image)
+	if f.Deploy.Image != expected {
+		t.Fatalf("expected image '%v', got '%v'", expected, f.Deploy.Image)
 	}
 	expected = "example.com/alice"
 	if f.Registry != expected {
@@ -1102,6 +1105,145 @@ func TestClient_Pipelines_Deploy_Image(t *testing.T) {
 	}
 }
 
+// TestClient_Deploy_Profile ensures that the client overlays the active
+// profile onto the function handed to the deployer, without the profile's
+// values being written to the function's base configuration.
+func TestClient_Deploy_Profile(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	deployer := mock.NewDeployer()
+	deployer.DeployFn = func(_ context.Context, f fn.Function) (fn.DeploymentResult, error) {
+		if f.Deploy.Namespace != "staging" {
+			t.Fatalf("expected profile namespace 'staging', got '%v'", f.Deploy.Namespace)
+		}
+		if len(f.Run.Envs) != 1 || *f.Run.Envs[0].Value != "debug" {
+			t.Fatalf("expected profile envs to be applied, got %v", f.Run.Envs)
+		}
+		return fn.DeploymentResult{}, nil
+	}
+	client := fn.New(
+		fn.WithBuilder(mock.NewBuilder()),
+		fn.WithDeployer(deployer),
+		fn.WithRegistry(TestRegistry),
+		fn.WithProfile("staging"))
+
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, value := "LOG_LEVEL", "debug"
+	f.Deploy.Namespace = "dev"
+	f.Profiles = map[string]fn.Profile{
+		"staging": {
+			Namespace: "staging",
+			Envs:      []fn.Env{{Name: &name, Value: &value}},
+		},
+	}
+	if err = f.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Deploy(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if !deployer.DeployInvoked {
+		t.Fatal("deployer was not invoked")
+	}
+
+	// The base configuration should remain unaltered
+	if f, err = fn.NewFunction(root); err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.Namespace != "dev" || len(f.Run.Envs) != 0 {
+		t.Fatalf("profile values were persisted to the base function: %+v", f.Deploy)
+	}
+
+	// An unknown profile is an error
+	client = fn.New(fn.WithProfile("prod"))
+	if err = client.Deploy(context.Background(), root); !errors.Is(err, fn.ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound, got %v", err)
+	}
+}
+
+// TestClient_Deploy_Domains ensures that the domains of a function deployed
+// to a cluster are provided in the namespace to which it was deployed, and
+// that those of a function deployed locally are not.
+func TestClient_Deploy_Domains(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	deployer := mock.NewDeployerWithResult(fn.DeploymentResult{Status: fn.Deployed, Namespace: "prod"})
+	dnsProvider := mock.NewDNSProvider()
+	dnsProvider.ProvideFn = func(f fn.Function) error {
+		if f.Deploy.Namespace != "prod" {
+			t.Fatalf("expected domains to be provided in namespace 'prod', got '%v'", f.Deploy.Namespace)
+		}
+		if len(f.Deploy.Domains) != 1 || f.Deploy.Domains[0].Name != "api.example.com" {
+			t.Fatalf("unexpected domains %v", f.Deploy.Domains)
+		}
+		return nil
+	}
+	client := fn.New(
+		fn.WithBuilder(mock.NewBuilder()),
+		fn.WithDeployer(deployer),
+		fn.WithDNSProvider(dnsProvider),
+		fn.WithRegistry(TestRegistry))
+
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Deploy.Domains = []fn.Domain{{Name: "api.example.com"}}
+	if err = f.Write(); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Deploy(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if !dnsProvider.ProvideInvoked {
+		t.Fatal("DNS provider was not invoked")
+	}
+
+	// Functions deployed outside of a cluster have no domains.
+	dnsProvider.ProvideInvoked = false
+	deployer = mock.NewDeployerWithResult(fn.DeploymentResult{Status: fn.Deployed, URL: "http://localhost:8080/"})
+	client = fn.New(fn.WithDeployer(deployer), fn.WithDNSProvider(dnsProvider))
+	if err = client.Deploy(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if dnsProvider.ProvideInvoked {
+		t.Fatal("DNS provider was invoked for a function deployed outside of a cluster")
+	}
+
+	// The domains of a removed function are removed.
+	dnsProvider.RemoveFn = func(name string) error {
+		if name != f.Name {
+			t.Fatalf("expected the domains of '%v' to be removed, got '%v'", f.Name, name)
+		}
+		return nil
+	}
+	client = fn.New(fn.WithRemover(mock.NewRemover()), fn.WithDNSProvider(dnsProvider))
+	if err = client.Remove(context.Background(), fn.Function{Root: root}, false); err != nil {
+		t.Fatal(err)
+	}
+	if !dnsProvider.RemoveInvoked {
+		t.Fatal("DNS provider was not invoked to remove the domains")
+	}
+}
+
 // TestClient_Deploy_UnbuiltErrors ensures that a call to deploy a function
 // which was not fully created (ie. was only initialized, not actually built
 // or deployed) yields the expected error.
@@ -1520,6 +1662,212 @@ func TestClient_Instances(t *testing.T) {
 	}
 }
 
+// TestClient_Logs ensures that logs are retrieved from the locally running
+// instance when there is one, and from the deployed function otherwise.
+func TestClient_Logs(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	var target string
+	logger := mock.NewLogger()
+	logger.LogsFn = func(f fn.Function, opts fn.LogOptions, emit func(fn.LogEntry)) error {
+		target = opts.Target
+		if !opts.Follow || opts.Container != "queue-proxy" {
+			t.Errorf("expected options to be passed through, got %+v", opts)
+		}
+		emit(fn.LogEntry{Instance: f.Name, Message: "hello"})
+		return nil
+	}
+	client := fn.New(fn.WithRegistry(TestRegistry), fn.WithRunner(mock.NewRunner()), fn.WithLogger(logger))
+	if err := client.New(context.Background(), fn.Function{Root: root, Runtime: TestRuntime}); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []fn.LogEntry
+	opts := fn.LogOptions{Follow: true, Container: "queue-proxy"}
+	emit := func(e fn.LogEntry) { entries = append(entries, e) }
+
+	// Not running locally, so the deployed function's logs
+	if err := client.Logs(context.Background(), root, opts, emit); err != nil {
+		t.Fatal(err)
+	}
+	if target != fn.EnvironmentRemote {
+		t.Fatalf("expected remote logs, got %q", target)
+	}
+	if len(entries) != 1 || entries[0].Message != "hello" {
+		t.Fatalf("expected the emitted entry, got %v", entries)
+	}
+
+	// Explicitly local logs require a running instance
+	opts.Target = fn.EnvironmentLocal
+	if err := client.Logs(context.Background(), root, opts, emit); !errors.Is(err, fn.ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning, got %v", err)
+	}
+
+	// Running locally, the local instance is preferred
+	job, err := client.Run(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer job.Stop()
+	opts.Target = ""
+	if err := client.Logs(context.Background(), root, opts, emit); err != nil {
+		t.Fatal(err)
+	}
+	if target != fn.EnvironmentLocal {
+		t.Fatalf("expected local logs, got %q", target)
+	}
+}
+
+// TestClient_Rollback ensures that a function is rolled back to the named
+// revision, or to that prior to the current revision by default, and that the
+// image of the revision is recorded as the function's deployed image.
+func TestClient_Rollback(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	revisions := []fn.Revision{
+		{Name: "myfunc-00003", Image: "example.com/alice/myfunc@sha256:3"},
+		{Name: "myfunc-00002", Image: "example.com/alice/myfunc@sha256:2", Traffic: 100},
+		{Name: "myfunc-00001", Image: "example.com/alice/myfunc@sha256:1"},
+	}
+	rollbacker := mock.NewRollbacker()
+	rollbacker.RevisionsFn = func(name string) ([]fn.Revision, error) {
+		if name != "myfunc" {
+			t.Errorf("expected revisions of 'myfunc', got '%v'", name)
+		}
+		return revisions, nil
+	}
+	rollbacker.RollbackFn = func(_, revision string) (fn.Revision, error) {
+		for _, r := range revisions {
+			if r.Name == revision {
+				r.Traffic = 100
+				return r, nil
+			}
+		}
+		return fn.Revision{}, fn.ErrRevisionNotFound
+	}
+	client := fn.New(fn.WithRegistry(TestRegistry), fn.WithRollbacker(rollbacker))
+	if err := client.New(context.Background(), fn.Function{Root: root, Runtime: TestRuntime, Name: "myfunc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Defaults to the revision prior to that currently serving
+	r, err := client.Rollback(context.Background(), root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Name != "myfunc-00001" {
+		t.Fatalf("expected rollback to 'myfunc-00001', got '%v'", r.Name)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.Image != "example.com/alice/myfunc@sha256:1" {
+		t.Fatalf("expected the revision's image to be recorded, got '%v'", f.Deploy.Image)
+	}
+
+	// An explicit revision
+	if r, err = client.Rollback(context.Background(), root, "myfunc-00003"); err != nil {
+		t.Fatal(err)
+	}
+	if r.Name != "myfunc-00003" {
+		t.Fatalf("expected rollback to 'myfunc-00003', got '%v'", r.Name)
+	}
+
+	// No revision prior to the oldest
+	revisions[1].Traffic = 0
+	revisions[2].Traffic = 100
+	if _, err = client.Rollback(context.Background(), root, ""); !errors.Is(err, fn.ErrRevisionNotFound) {
+		t.Fatalf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
+
+// TestClient_InvokeTag ensures that a tagged revision of the deployed
+// function, such as a canary, can be invoked directly by its tag.
+func TestClient_InvokeTag(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	// A masquerading canary
+	invoked := false
+	l, err := net.Listen("tcp4", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := http.Server{Handler: http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		invoked = true
+		_, _ = res.Write([]byte("canary"))
+	})}
+	go func() {
+		if err = s.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "error serving: %v", err)
+		}
+	}()
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	describer := mock.NewDescriber()
+	describer.DescribeFn = func(string) (fn.Instance, error) {
+		return fn.Instance{
+			Route: "http://myfunc.example.com",
+			Traffic: []fn.TrafficTarget{
+				{Revision: "myfunc-00002", Latest: true, Tag: "canary", Percent: 10, URL: "http://" + l.Addr().String()},
+				{Revision: "myfunc-00001", Percent: 90},
+			},
+		}, nil
+	}
+	client := fn.New(fn.WithRegistry(TestRegistry), fn.WithDescriber(describer))
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root, Template: "http", Name: "myfunc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, r, err := client.Invoke(context.Background(), root, "canary", fn.NewInvokeMessage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !invoked || r != "canary" {
+		t.Fatalf("expected the canary to be invoked, got %q", r)
+	}
+}
+
+// TestClient_InvokeHost ensures that a target which is a valid tag name but
+// tags no revision of the deployed function is invoked verbatim as a host.
+func TestClient_InvokeHost(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	describer := mock.NewDescriber()
+	describer.DescribeFn = func(string) (fn.Instance, error) {
+		return fn.Instance{
+			Route:   "http://myfunc.example.com",
+			Traffic: []fn.TrafficTarget{{Revision: "myfunc-00001", Latest: true, Tag: "canary", Percent: 100, URL: "http://canary-myfunc.example.com"}},
+		}, nil
+	}
+	var requested string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requested = req.URL.String()
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}}, nil
+	})
+	client := fn.New(fn.WithRegistry(TestRegistry), fn.WithDescriber(describer), fn.WithTransport(transport))
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root, Template: "http", Name: "myfunc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := client.Invoke(context.Background(), root, "myhost", fn.NewInvokeMessage()); err != nil {
+		t.Fatal(err)
+	}
+	if requested != "myhost" {
+		t.Fatalf("expected the host to be invoked verbatim, got %q", requested)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
 // TestClient_BuiltStamps ensures that the client creates and considers a
 // buildstamp on build which reports whether or not a given path contains a built
 // function.
@@ -1551,6 +1899,125 @@ func TestClient_BuiltStamps(t *testing.T) {
 	}
 }
 
+// TestClient_BuiltDetectsBuildSettings ensures that the client's Built command
+// detects changes to the settings which affect the built image (builder,
+// builder image, build envs and platform) as indicating build staleness.
+func TestClient_BuiltDetectsBuildSettings(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	client := fn.New(fn.WithBuilder(mock.NewBuilder()), fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	name, value := "EXAMPLE", "value"
+	edits := map[string]func(*fn.Function){
+		"builder":      func(f *fn.Function) { f.Build.Builder = "s2i" },
+		"builderImage": func(f *fn.Function) { f.Build.BuilderImages = map[string]string{"s2i": "example.com/builder"} },
+		"buildEnvs":    func(f *fn.Function) { f.Build.BuildEnvs = []fn.Env{{Name: &name, Value: &value}} },
+	}
+	for _, setting := range []string{"builder", "builderImage", "buildEnvs"} {
+		if err := client.Build(context.Background(), root); err != nil {
+			t.Fatal(err)
+		}
+		if !client.Built(root) {
+			t.Fatalf("freshly built function reported Built==false before editing %v", setting)
+		}
+		f, err := fn.NewFunction(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		edits[setting](&f)
+		if err := f.Write(); err != nil {
+			t.Fatal(err)
+		}
+		if client.Built(root) {
+			t.Fatalf("client did not detect a change of %v as indicating build staleness", setting)
+		}
+	}
+
+	// Built for one platform is stale for another
+	if err := client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	client = fn.New(fn.WithBuilder(mock.NewBuilder()), fn.WithRegistry(TestRegistry), fn.WithPlatform("linux/arm64"))
+	if client.Built(root) {
+		t.Fatal("client did not detect a change of platform as indicating build staleness")
+	}
+}
+
+// TestClient_BuildLocalState ensures that building and pushing a function
+// record the resultant image as local state rather than modifying func.yaml,
+// and that the built image is that which is deployed and recorded on deploy.
+func TestClient_BuildLocalState(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	var (
+		pusher   = mock.NewPusher()
+		deployer = mock.NewDeployer()
+		digest   = "sha256:42a6c7ba5b7e2a7b4e36e2b4c9a4b8f6ab9c6b0d0c5e4f3e2d1c0b9a8f7e6d5c"
+		image    = "example.com/alice/myfunc:latest"
+	)
+	pusher.PushFn = func(fn.Function) (string, error) { return digest, nil }
+	deployer.DeployFn = func(_ context.Context, f fn.Function) (fn.DeploymentResult, error) {
+		if f.ImageWithDigest() != "example.com/alice/myfunc@"+digest {
+			t.Fatalf("deployer expected the built image with digest, got '%v'", f.ImageWithDigest())
+		}
+		return fn.DeploymentResult{}, nil
+	}
+	client := fn.New(
+		fn.WithBuilder(mock.NewBuilder()),
+		fn.WithPusher(pusher),
+		fn.WithDeployer(deployer),
+		fn.WithRegistry("example.com/alice"))
+
+	if err := client.Create(fn.Function{Name: "myfunc", Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(filepath.Join(root, fn.FunctionFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Build and push do not modify func.yaml
+	if err = client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Push(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.ReadFile(filepath.Join(root, fn.FunctionFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Fatalf("expected func.yaml to be unmodified by build and push. before:\n%s\nafter:\n%s", before, after)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Built.Image != image || f.Built.Digest != digest {
+		t.Fatalf("expected built image '%v' with digest '%v', got '%v'", image, digest, f.Built)
+	}
+
+	// Deploy records the deployed image in func.yaml
+	if err = client.Deploy(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = fn.NewFunction(root); err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.Image != "example.com/alice/myfunc@"+digest {
+		t.Fatalf("expected deployed image to be recorded, got '%v'", f.Deploy.Image)
+	}
+	if f.Image != "" || f.ImageDigest != "" {
+		t.Fatalf("expected image and digest to remain unset, got '%v' and '%v'", f.Image, f.ImageDigest)
+	}
+}
+
 // TestClient_CreateMigration ensures that the client includes the most recent
 // migration version when creating a new function
 func TestClient_CreateMigration(t *testing.T) {
@@ -1577,7 +2044,8 @@ func TestClient_CreateMigration(t *testing.T) {
 
 // TestClient_BuiltDetects ensures that the client's Built command detects
 // filesystem changes as indicating the function is no longer Built (aka stale)
-// This includes modifying timestamps, removing or adding files.
+// This includes modifying file contents, removing or adding files, but not
+// merely modifying timestamps.
 func TestClient_BuiltDetects(t *testing.T) {
 	var (
 		ctx      = context.Background()
@@ -1612,8 +2080,18 @@ func TestClient_BuiltDetects(t *testing.T) {
 	// Release thread and wait to ensure that the clock advances even in constrained CI environments
 	time.Sleep(100 * time.Millisecond)
 
+	// The contents are unchanged, so the function is still Built
+	if !client.Built(root) {
+		t.Fatal("client detected a file timestamp change as indicating build staleness")
+	}
+
+	// Edit the filesystem by modifying the contents of a file
+	if err := os.WriteFile(filepath.Join(root, "handle.go"), []byte("package function\n// modified\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
 	if client.Built(root) {
-		t.Fatal("client did not detect file timestamp change as indicating build staleness")
+		t.Fatal("client did not detect file content change as indicating build staleness")
 	}
 
 	// Build and double-check Built has been reset
@@ -1653,3 +2131,212 @@ func TestClient_BuiltDetects(t *testing.T) {
 		t.Fatal("client did not detect a removed file as indicating build staleness")
 	}
 }
+
+// TestClient_Render ensures that rendering the manifests of an unbuilt
+// function uses the image into which it would be built, and that rendering
+// requires a deployer which supports it.
+func TestClient_Render(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	deployer := mock.NewDeployer()
+	deployer.RenderFn = func(_ context.Context, f fn.Function) (fn.Manifests, error) {
+		if f.Image != TestRegistry+"/myfunc:latest" {
+			t.Errorf("expected the image to be derived from the registry, got '%v'", f.Image)
+		}
+		return fn.Manifests{}, nil
+	}
+	client := fn.New(fn.WithRegistry(TestRegistry), fn.WithDeployer(deployer))
+	if err := client.New(context.Background(), fn.Function{Root: root, Runtime: TestRuntime, Name: "myfunc"}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Render(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if !deployer.RenderInvoked {
+		t.Fatal("deployer's renderer not invoked")
+	}
+
+	client = fn.New(fn.WithRegistry(TestRegistry), fn.WithDeployer(deployOnly{}))
+	if _, err = client.Render(context.Background(), f); !errors.Is(err, fn.ErrRenderNotSupported) {
+		t.Fatalf("expected ErrRenderNotSupported, got %v", err)
+	}
+}
+
+// deployOnly is a deployer which does not support rendering.
+type deployOnly struct{}
+
+func (deployOnly) Deploy(context.Context, fn.Function) (fn.DeploymentResult, error) {
+	return fn.DeploymentResult{}, nil
+}
+
+// TestClient_Build_ImageLayout ensures that the image layout written by a
+// prior daemonless build is removed on build, such that it is not pushed in
+// place of the image built by a builder which uses a daemon.
+func TestClient_Build_ImageLayout(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	client := fn.New(fn.WithBuilder(mock.NewBuilder()), fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.MkdirAll(f.ImageLayout(), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(f.ImageLayout(), "index.json"), []byte("{}"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if !f.HasImageLayout() {
+		t.Fatal("expected the function to have an image layout")
+	}
+
+	if err = client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if f.HasImageLayout() {
+		t.Fatal("expected the image layout of the prior build to be removed")
+	}
+}
+
+// TestClient_Save ensures that only a built function's image is saved, and
+// that it is the image built.
+func TestClient_Save(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	archiver := mock.NewArchiver()
+	client := fn.New(fn.WithBuilder(mock.NewBuilder()), fn.WithArchiver(archiver), fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Save(context.Background(), root, "oci-archive:f.tar"); !errors.Is(err, fn.ErrNotBuilt) {
+		t.Fatalf("expected ErrNotBuilt saving an unbuilt function, got %v", err)
+	}
+
+	if err := client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	expectedImage := TestRegistry + "/" + filepath.Base(root) + ":latest"
+	archiver.SaveFn = func(f fn.Function, output string) (string, error) {
+		if f.Image != expectedImage || output != "oci-archive:f.tar" {
+			t.Fatalf("expected %v saved to oci-archive:f.tar, got %v to %v", expectedImage, f.Image, output)
+		}
+		return "sha256:abc", nil
+	}
+	if err := client.Save(context.Background(), root, "oci-archive:f.tar"); err != nil {
+		t.Fatal(err)
+	}
+	if !archiver.SaveInvoked {
+		t.Fatal("expected the archiver to be invoked")
+	}
+}
+
+// TestClient_Load ensures that an image loaded is recorded as the function's
+// built image, named as if built now, such that it is pushed.
+func TestClient_Load(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	var (
+		archiver      = mock.NewArchiver()
+		pusher        = mock.NewPusher()
+		expectedImage = TestRegistry + "/" + filepath.Base(root) + ":latest"
+	)
+	client := fn.New(fn.WithArchiver(archiver), fn.WithPusher(pusher), fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	archiver.LoadFn = func(f fn.Function, from string) (string, error) {
+		if f.Image != expectedImage || from != "f.tar" {
+			t.Fatalf("expected %v loaded from f.tar, got %v from %v", expectedImage, f.Image, from)
+		}
+		return "sha256:abc", nil
+	}
+	pusher.PushFn = func(f fn.Function) (string, error) {
+		if f.Image != expectedImage {
+			t.Fatalf("expected %v pushed, got %v", expectedImage, f.Image)
+		}
+		return "sha256:abc", nil
+	}
+	if err := client.Load(context.Background(), root, "f.tar"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Push(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Built.Image != expectedImage || f.Built.Digest != "sha256:abc" {
+		t.Fatalf("expected the image loaded to be recorded as pushed, got %+v", f.Built)
+	}
+	if client.Built(root) {
+		t.Fatal("expected a function whose image was loaded not to be considered built from its source")
+	}
+}
+
+// TestClient_Build_SBOM ensures that the SBOM of a function's image is
+// generated on build only when build.sbom is set, and that the SBOM of a
+// prior build is removed.
+func TestClient_Build_SBOM(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	generator := mock.NewSBOMGenerator()
+	client := fn.New(fn.WithBuilder(mock.NewBuilder()), fn.WithSBOMGenerator(generator), fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A stale SBOM is removed, and none generated when not set.
+	stale := filepath.Join(f.SBOMDir(), "image.spdx.json")
+	if err = os.MkdirAll(f.SBOMDir(), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(stale, []byte("{}"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if generator.GenerateInvoked {
+		t.Fatal("expected no SBOM to be generated when build.sbom is not set")
+	}
+	if _, err = os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatal("expected the SBOM of the prior build to be removed")
+	}
+
+	// Generated of the image built when set.
+	f.Build.SBOM = fn.SBOMFormatSPDX
+	if err = f.Write(); err != nil {
+		t.Fatal(err)
+	}
+	generator.GenerateFn = func(f fn.Function) error {
+		if f.Image == "" || f.Build.SBOM != fn.SBOMFormatSPDX {
+			t.Fatalf("expected the built image in SPDX, got %q in %q", f.Image, f.Build.SBOM)
+		}
+		return nil
+	}
+	if err = client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if !generator.GenerateInvoked {
+		t.Fatal("expected the SBOM to be generated")
+	}
+}
@@ -11,6 +11,8 @@ import (
 
 	"knative.dev/func/buildpacks"
 	"knative.dev/func/config"
+	"knative.dev/func/dockerfile"
+	"knative.dev/func/oci"
 	"knative.dev/func/s2i"
 
 	fn "knative.dev/func"
@@ -59,13 +61,28 @@ EXAMPLES
 	o Build a function specifying the Source-to-Image (S2I) builder
 	  $ {{.Name}} build --builder=s2i
 
+	o Build a function from the Dockerfile (or Containerfile) in its root,
+	  for a given platform
+	  $ {{.Name}} build --builder=dockerfile --platform=linux/arm64
+
+	o Build a multi-architecture image of a function, pushed as an image index
+	  $ {{.Name}} build --builder=host --platform=linux/amd64,linux/arm64 --push
+
+	o Build a Go function without a container daemon, compiling it with the
+	  local Go toolchain, and push it directly to the registry
+	  $ {{.Name}} build --builder=host --push
+
+	o Build a function and save its image to an OCI archive, which may be
+	  pushed later with '{{.Name}} push --from', without a registry
+	  $ {{.Name}} build --output oci-archive:f.tar
+
 	o Build a function specifying the Pack builder with a custom Buildpack
 	  builder image.
 		$ {{.Name}} build --builder=pack --builder-image=cnbs/sample-builder:bionic
 
 `,
 		SuggestFor: []string{"biuld", "buidl", "built"},
-		PreRunE:    bindEnv("image", "path", "builder", "registry", "confirm", "push", "builder-image", "platform"),
+		PreRunE:    bindEnv("image", "path", "builder", "registry", "confirm", "push", "builder-image", "platform", "output"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runBuild(cmd, args, newClient)
 		},
@@ -115,7 +132,10 @@ EXAMPLES
 	cmd.Flags().BoolP("push", "u", false,
 		"Attempt to push the function image to the configured registry after being successfully built")
 	cmd.Flags().StringP("platform", "", "",
-		"Optionally specify a target platform, for example \"linux/amd64\" when using the s2i build strategy")
+		"Optionally specify a target platform, for example \"linux/amd64\" when using the s2i, dockerfile or host build strategy. "+
+			"Several comma-separated platforms, for example \"linux/amd64,linux/arm64\", are built and pushed as a multi-architecture image index")
+	cmd.Flags().StringP("output", "o", "",
+		"Save the built image to an OCI image layout (oci-layout:<dir>) or archive (oci-archive:<file.tar>), from which it may be pushed with 'push --from' (Env: $FUNC_OUTPUT)")
 	setPathFlag(cmd)
 
 	// Tab Completion
@@ -178,13 +198,24 @@ func runBuild(cmd *cobra.Command, _ []string, newClient ClientFactory) (err erro
 			s2i.WithName(builders.S2I),
 			s2i.WithPlatform(cfg.Platform),
 			s2i.WithVerbose(cfg.Verbose))
+	} else if f.Build.Builder == builders.Dockerfile {
+		builder = dockerfile.NewBuilder(
+			dockerfile.WithName(builders.Dockerfile),
+			dockerfile.WithPlatform(cfg.Platform),
+			dockerfile.WithVerbose(cfg.Verbose))
+	} else if f.Build.Builder == builders.Host {
+		builder = oci.NewBuilder(
+			oci.WithName(builders.Host),
+			oci.WithPlatform(cfg.Platform),
+			oci.WithVerbose(cfg.Verbose))
 	} else {
 		return builders.ErrUnknownBuilder{Name: f.Build.Builder, Known: KnownBuilders()}
 	}
 
 	client, done := newClient(ClientConfig{Verbose: cfg.Verbose},
 		fn.WithRegistry(cfg.Registry),
-		fn.WithBuilder(builder))
+		fn.WithBuilder(builder),
+		fn.WithPlatform(cfg.Platform))
 	defer done()
 
 	// TODO(lkingland): this write will be unnecessary when the client API is
@@ -198,7 +229,12 @@ func runBuild(cmd *cobra.Command, _ []string, newClient ClientFactory) (err erro
 		return
 	}
 	if cfg.Push {
-		err = client.Push(cmd.Context(), cfg.Path)
+		if err = client.Push(cmd.Context(), cfg.Path); err != nil {
+			return
+		}
+	}
+	if cfg.Output != "" {
+		err = client.Save(cmd.Context(), cfg.Path, cfg.Output)
 	}
 
 	// TODO(lkingland): when the above Build and Push calls are refactored to not
@@ -226,11 +262,16 @@ type buildConfig struct {
 	// working directory of the process.
 	Path string
 
-	// Platform ofr resultant image (s2i builder only)
+	// Platform ofr resultant image (s2i, dockerfile and host builders only),
+	// or a comma-separated list of platforms for a multi-architecture image.
 	Platform string
 
 	// Push the resulting image to the registry after building.
 	Push bool
+
+	// Output to which the resulting image is saved after building, an OCI
+	// image layout or archive (build only).
+	Output string
 }
 
 // newBuildConfig gathers options into a single build request.
@@ -247,6 +288,7 @@ func newBuildConfig() buildConfig {
 		Path:         viper.GetString("path"),
 		Platform:     viper.GetString("platform"),
 		Push:         viper.GetBool("push"),
+		Output:       viper.GetString("output"),
 	}
 }
 
@@ -260,7 +302,7 @@ func (c buildConfig) Configure(f fn.Function) fn.Function {
 		f.Build.BuilderImages[f.Build.Builder] = c.BuilderImage
 	}
 	f.Image = c.Image
-	// Path, Platform and Push are not part of a function's state.
+	// Path, Platform, Push and Output are not part of a function's state.
 	return f
 }
 
@@ -337,11 +379,23 @@ func (c buildConfig) Validate() (err error) {
 		return
 	}
 
-	// Platform is only supportd with the S2I builder at this time
-	if c.Platform != "" && c.Builder != builders.S2I {
-		err = errors.New("Only S2I builds currently support specifying platform")
+	// Platform is only supportd with the S2I, Dockerfile and Host builders at this time
+	if c.Platform != "" && c.Builder != builders.S2I && c.Builder != builders.Dockerfile && c.Builder != builders.Host {
+		err = errors.New("Only S2I, Dockerfile and Host builds currently support specifying platform")
 		return
 	}
 
+	// Platform may list several platforms, built as an image index
+	if _, err = oci.ParsePlatforms(c.Platform); err != nil {
+		return fmt.Errorf("invalid --platform: %w", err)
+	}
+
+	// Output must be an OCI image layout or archive
+	if c.Output != "" {
+		if _, err = oci.ParseOutput(c.Output); err != nil {
+			return fmt.Errorf("invalid --output: %w", err)
+		}
+	}
+
 	return
 }
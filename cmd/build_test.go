@@ -7,6 +7,7 @@ import (
 
 	fn "knative.dev/func"
 	"knative.dev/func/builders"
+	"knative.dev/func/config"
 	"knative.dev/func/mock"
 )
 
@@ -65,8 +66,8 @@ func TestBuild_ConfigApplied(t *testing.T) {
 	if f, err = fn.NewFunction(root); err != nil {
 		t.Fatal(err)
 	}
-	if f.Image != "registry.example.com/charlie/f:latest" {
-		t.Fatalf("expected image 'registry.example.com/charlie/f:latest' got '%v'", f.Image)
+	if f.Built.Image != "registry.example.com/charlie/f:latest" {
+		t.Fatalf("expected image 'registry.example.com/charlie/f:latest' got '%v'", f.Built.Image)
 	}
 
 	// Ensure environment variables loaded: Push
@@ -275,6 +276,61 @@ func TestBuild_BuilderValidated(t *testing.T) {
 	testBuilderValidated(NewBuildCmd, t)
 }
 
+// TestBuild_PlatformValidated ensures that one or several platforms may be
+// specified to the builders which support them.
+func TestBuild_PlatformValidated(t *testing.T) {
+	tests := []struct {
+		builder  string
+		platform string
+		valid    bool
+	}{
+		{builders.Pack, "", true},
+		{builders.Pack, "linux/amd64", false},
+		{builders.S2I, "linux/amd64", true},
+		{builders.Dockerfile, "linux/amd64,linux/arm64", true},
+		{builders.Host, "linux/amd64,linux/arm64", true},
+		{builders.Host, "linux/amd64,linux/amd64", false},
+		{builders.Host, "linux/amd64,", false},
+	}
+	for _, tt := range tests {
+		err := buildConfig{Global: config.Global{Builder: tt.builder}, Platform: tt.platform}.Validate()
+		if (err == nil) != tt.valid {
+			t.Errorf("builder %q with platform %q: expected valid %v, got %v", tt.builder, tt.platform, tt.valid, err)
+		}
+	}
+}
+
+// TestBuild_Output ensures that the image built is saved to the OCI image
+// layout or archive given with --output, and that other outputs are invalid.
+func TestBuild_Output(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	if err := fn.New().Create(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+
+	archiver := mock.NewArchiver()
+	archiver.SaveFn = func(f fn.Function, output string) (string, error) {
+		if output != "oci-archive:f.tar" {
+			t.Errorf("unexpected output '%v'", output)
+		}
+		return "sha256:1", nil
+	}
+	cmd := NewBuildCmd(NewTestClient(fn.WithBuilder(mock.NewBuilder()), fn.WithArchiver(archiver)))
+	cmd.SetArgs([]string{"--output=oci-archive:f.tar"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !archiver.SaveInvoked {
+		t.Fatal("expected the image built to be saved")
+	}
+
+	cmd.SetArgs([]string{"--output=docker-archive:f.tar"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error building to an unsupported output")
+	}
+}
+
 // TestBuild_Push ensures that the build command properly pushes and respects
 // the --push flag.
 // - Push triggered after a successful build
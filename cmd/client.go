@@ -1,20 +1,28 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
 	fn "knative.dev/func"
 	"knative.dev/func/buildpacks"
 	"knative.dev/func/config"
+	"knative.dev/func/deployers"
 	"knative.dev/func/docker"
 	"knative.dev/func/docker/creds"
 	fnhttp "knative.dev/func/http"
+	"knative.dev/func/k8s"
 	"knative.dev/func/knative"
+	"knative.dev/func/oci"
 	"knative.dev/func/openshift"
 	"knative.dev/func/pipelines/tekton"
 	"knative.dev/func/progress"
+	"knative.dev/func/sbom"
 )
 
 // ClientConfig settings for use with NewClient
@@ -75,9 +83,13 @@ func NewClient(cfg ClientConfig, options ...fn.Option) (*fn.Client, func()) {
 			fn.WithTransport(t),
 			fn.WithRepositoriesPath(config.RepositoriesPath()),
 			fn.WithBuilder(buildpacks.NewBuilder(buildpacks.WithVerbose(cfg.Verbose))),
-			fn.WithRemover(knative.NewRemover(cfg.Namespace, cfg.Verbose)),
-			fn.WithDescriber(knative.NewDescriber(cfg.Namespace, cfg.Verbose)),
-			fn.WithLister(knative.NewLister(cfg.Namespace, cfg.Verbose)),
+			fn.WithRemover(newRemover(cfg.Namespace, cfg.Verbose)),
+			fn.WithDescriber(newDescriber(cfg.Namespace, cfg.Verbose)),
+			fn.WithLogger(newLogger(cfg.Namespace, cfg.Verbose)),
+			fn.WithLister(newLister(cfg.Namespace, cfg.Verbose)),
+			fn.WithRollbacker(knative.NewRollbacker(cfg.Namespace, cfg.Verbose)),
+			fn.WithTrafficSplitter(knative.NewTrafficSplitter(cfg.Namespace, cfg.Verbose)),
+			fn.WithDNSProvider(knative.NewDNSProvider(cfg.Namespace, cfg.Verbose)),
 			fn.WithRunner(docker.NewRunner(cfg.Verbose, os.Stdout, os.Stderr)),
 			fn.WithDeployer(d),
 			fn.WithPipelinesProvider(pp),
@@ -86,6 +98,8 @@ func NewClient(cfg ClientConfig, options ...fn.Option) (*fn.Client, func()) {
 				docker.WithProgressListener(p),
 				docker.WithTransport(t),
 				docker.WithVerbose(cfg.Verbose))),
+			fn.WithArchiver(oci.NewArchiver()),
+			fn.WithSBOMGenerator(sbom.NewGenerator()),
 		}
 	)
 
@@ -147,6 +161,153 @@ func newKnativeDeployer(namespace string, verbose bool) fn.Deployer {
 	return knative.NewDeployer(options...)
 }
 
+// newDeployer returns the deployer of the given name, which defaults to the
+// Knative deployer.
+func newDeployer(name, namespace string, verbose bool) (fn.Deployer, error) {
+	switch name {
+	case "", deployers.Knative:
+		return newKnativeDeployer(namespace, verbose), nil
+	case deployers.Kubernetes:
+		return k8s.NewDeployer(
+			k8s.WithDeployerNamespace(namespace),
+			k8s.WithDeployerVerbose(verbose)), nil
+	default:
+		return nil, deployers.ErrUnknownDeployer{Name: name, Known: KnownDeployers()}
+	}
+}
+
+// newLister returns a lister of the functions deployed by any deployer,
+// including those deployed as local containers.
+func newLister(namespace string, verbose bool) fn.Lister {
+	return deployersLister{
+		knative.NewLister(namespace, verbose),
+		k8s.NewLister(namespace, verbose),
+		docker.NewLister(verbose),
+	}
+}
+
+// newDescriber returns a describer of functions deployed by any deployer,
+// including those deployed as local containers.
+func newDescriber(namespace string, verbose bool) fn.Describer {
+	return deployersDescriber{
+		knative.NewDescriber(namespace, verbose),
+		k8s.NewDescriber(namespace, verbose),
+		docker.NewDescriber(verbose),
+	}
+}
+
+// newRemover returns a remover of functions deployed by any deployer,
+// including those deployed as local containers.
+func newRemover(namespace string, verbose bool) fn.Remover {
+	return deployersRemover{
+		knative.NewRemover(namespace, verbose),
+		k8s.NewRemover(namespace, verbose),
+		docker.NewRemover(verbose),
+	}
+}
+
+// deployersLister lists the functions of each deployer.  Deployers whose
+// resources can not be listed, such as Knative on a cluster without Knative
+// Serving or any deployer to a cluster when offline, are skipped unless none
+// can be listed.
+type deployersLister []fn.Lister
+
+func (l deployersLister) List(ctx context.Context) (items []fn.ListItem, err error) {
+	var errs []error
+	for _, lister := range l {
+		ii, err := lister.List(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		items = append(items, ii...)
+	}
+	if len(errs) == len(l) {
+		return nil, firstError(errs)
+	}
+	return items, nil
+}
+
+// deployersDescriber describes a function as does the first deployer by
+// which it is found to be deployed.
+type deployersDescriber []fn.Describer
+
+func (d deployersDescriber) Describe(ctx context.Context, name string) (fn.Instance, error) {
+	var errs []error
+	for _, describer := range d {
+		instance, err := describer.Describe(ctx, name)
+		if err == nil {
+			return instance, nil
+		}
+		errs = append(errs, err)
+	}
+	return fn.Instance{}, firstError(errs)
+}
+
+// deployersRemover removes a function using every deployer by which it is
+// found to be deployed, such as both locally and to the cluster.
+type deployersRemover []fn.Remover
+
+func (r deployersRemover) Remove(ctx context.Context, name string) error {
+	var (
+		errs    []error
+		removed bool
+	)
+	for _, remover := range r {
+		if err := remover.Remove(ctx, name); err != nil {
+			errs = append(errs, err)
+		} else {
+			removed = true
+		}
+	}
+	err := firstError(errs)
+	if removed && notFound(err) {
+		return nil
+	}
+	return err
+}
+
+// firstError of the deployers which is not that the function was not found,
+// or if the function was not found by any deployer the first such error.
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if !notFound(err) {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// notFound returns true if the error is that the function was not found to be
+// deployed, or running.
+func notFound(err error) bool {
+	return k8serrors.IsNotFound(err) || errors.Is(err, fn.ErrNotRunning)
+}
+
+// newLogger returns a logger of both locally running functions, which are
+// docker containers, and deployed functions, which are Knative services.
+func newLogger(namespace string, verbose bool) fn.Logger {
+	return targetLogger{
+		local:  docker.NewLogger(verbose),
+		remote: knative.NewLogger(namespace, verbose),
+	}
+}
+
+// targetLogger delegates to the logger of the target environment.
+type targetLogger struct {
+	local, remote fn.Logger
+}
+
+func (l targetLogger) Logs(ctx context.Context, f fn.Function, opts fn.LogOptions, emit func(fn.LogEntry)) error {
+	if opts.Target == fn.EnvironmentLocal {
+		return l.local.Logs(ctx, f, opts, emit)
+	}
+	return l.remote.Logs(ctx, f, opts, emit)
+}
+
 type deployDecorator struct {
 	oshDec openshift.OpenshiftMetadataDecorator
 }
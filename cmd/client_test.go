@@ -2,8 +2,12 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
 	fn "knative.dev/func"
 	"knative.dev/func/mock"
 )
@@ -45,3 +49,37 @@ func Test_NewTestClient(t *testing.T) {
 		t.Fatalf("test client factory should ignore options when invoked.")
 	}
 }
+
+// Test_deployersRemover ensures that a function is removed by every deployer
+// by which it is deployed, such as both locally and to the cluster, and that
+// only its not being found by the others is not an error.
+func Test_deployersRemover(t *testing.T) {
+	notFound := func(string) error {
+		return fmt.Errorf("remover failed to delete the service: %w", k8serrors.NewNotFound(schema.GroupResource{Resource: "services"}, "f"))
+	}
+	failed := func(string) error { return fmt.Errorf("connection refused") }
+
+	local, remote := mock.NewRemover(), mock.NewRemover()
+	if err := (deployersRemover{remote, local}).Remove(context.Background(), "f"); err != nil {
+		t.Fatal(err)
+	}
+	if !local.RemoveInvoked || !remote.RemoveInvoked {
+		t.Errorf("expected the function to be removed by both removers, removed locally %v and remotely %v", local.RemoveInvoked, remote.RemoveInvoked)
+	}
+
+	// Not found by one
+	other := &mock.Remover{RemoveFn: notFound}
+	if err := (deployersRemover{other, mock.NewRemover()}).Remove(context.Background(), "f"); err != nil {
+		t.Errorf("expected the function not being found by one remover to be ignored, got %v", err)
+	}
+
+	// Not found by any
+	if err := (deployersRemover{other, &mock.Remover{RemoveFn: func(string) error { return fn.ErrNotRunning }}}).Remove(context.Background(), "f"); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected not found when removed by no remover, got %v", err)
+	}
+
+	// Failed by one
+	if err := (deployersRemover{&mock.Remover{RemoveFn: failed}, mock.NewRemover()}).Remove(context.Background(), "f"); err == nil {
+		t.Error("expected the failure of one remover to be reported")
+	}
+}
@@ -67,6 +67,12 @@ func CompleteOutputFormatList(cmd *cobra.Command, args []string, toComplete stri
 	return
 }
 
+func CompleteLogsOutputFormatList(cmd *cobra.Command, args []string, toComplete string) (strings []string, directive cobra.ShellCompDirective) {
+	directive = cobra.ShellCompDirectiveDefault
+	strings = []string{"human", "plain", "json"}
+	return
+}
+
 func CompleteRegistryList(cmd *cobra.Command, args []string, toComplete string) (strings []string, directive cobra.ShellCompDirective) {
 	directive = cobra.ShellCompDirectiveError
 	u, err := user.Current()
@@ -146,3 +152,27 @@ func CompleteBuilderList(cmd *cobra.Command, args []string, complete string) (ma
 
 	return
 }
+
+func CompleteProfileList(cmd *cobra.Command, args []string, complete string) (profiles []string, directive cobra.ShellCompDirective) {
+	directive = cobra.ShellCompDirectiveError
+
+	path, err := cmd.Flags().GetString("path")
+	if err != nil {
+		return
+	}
+
+	f, err := fn.NewFunction(path)
+	if err != nil {
+		return
+	}
+
+	profiles = []string{}
+	for _, name := range f.ProfileNames() {
+		if strings.HasPrefix(name, complete) {
+			profiles = append(profiles, name)
+		}
+	}
+
+	directive = cobra.ShellCompDirectiveNoFileComp
+	return
+}
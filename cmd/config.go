@@ -51,6 +51,8 @@ func NewConfigCmd(loadSaver functionLoaderSaver) *cobra.Command {
 Interactive prompt that allows configuration of Volume mounts, Environment
 variables, and Labels for a function project present in the current directory
 or from the directory specified with --path.
+
+Deployment profiles defined in func.yaml can be listed with 'config profiles'.
 `,
 		SuggestFor: []string{"cfg", "cofnig"},
 		PreRunE:    bindEnv("path"),
@@ -63,6 +65,7 @@ or from the directory specified with --path.
 	cmd.AddCommand(NewConfigLabelsCmd(loadSaver))
 	cmd.AddCommand(NewConfigEnvsCmd(loadSaver))
 	cmd.AddCommand(NewConfigVolumesCmd())
+	cmd.AddCommand(NewConfigProfilesCmd(loadSaver))
 
 	return cmd
 }
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+
+	fn "knative.dev/func"
+)
+
+func NewConfigProfilesCmd(loadSaver functionLoaderSaver) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "List the deployment profiles defined for a function",
+		Long: `List the deployment profiles defined for a function
+
+Prints the profiles, and the values each overrides, for a function project
+present in the current directory or from the directory specified with --path.
+
+Use --profile to print the effective configuration values (namespace,
+registry, environment variables, labels and annotations) which are in effect
+when the given profile is selected.
+`,
+		SuggestFor: []string{"profile", "porfiles"},
+		PreRunE:    bindEnv("path", "output", "profile"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			function, err := initConfigCommand(loadSaver)
+			if err != nil {
+				return
+			}
+
+			return listProfiles(function, viper.GetString("profile"), cmd.OutOrStdout(), Format(viper.GetString("output")))
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "human", "Output format (human|json) (Env: $FUNC_OUTPUT)")
+	cmd.Flags().StringP("profile", "", "", "Print the effective configuration when the given profile is selected (Env: $FUNC_PROFILE)")
+	setPathFlag(cmd)
+
+	if err := cmd.RegisterFlagCompletionFunc("profile", CompleteProfileList); err != nil {
+		fmt.Println("internal: error while calling RegisterFlagCompletionFunc: ", err)
+	}
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	return cmd
+}
+
+// listProfiles writes either the set of profiles defined by the function or,
+// if a profile name is provided, the values in effect with that profile.
+func listProfiles(f fn.Function, profile string, w io.Writer, outputFormat Format) error {
+	if profile != "" {
+		pf, err := f.ApplyProfile(profile)
+		if err != nil {
+			return err
+		}
+		return listEffectiveProfile(pf, profile, w, outputFormat)
+	}
+
+	switch outputFormat {
+	case Human:
+		if len(f.Profiles) == 0 {
+			_, err := fmt.Fprintln(w, "There aren't any configured profiles")
+			return err
+		}
+		fmt.Fprintln(w, "Configured profiles:")
+		for _, name := range f.ProfileNames() {
+			p := f.Profiles[name]
+			fmt.Fprintf(w, " - %v\n", name)
+			if p.Namespace != "" {
+				fmt.Fprintf(w, "     namespace: %v\n", p.Namespace)
+			}
+			if p.Registry != "" {
+				fmt.Fprintf(w, "     registry: %v\n", p.Registry)
+			}
+			for _, e := range p.Envs {
+				fmt.Fprintf(w, "     %v\n", e)
+			}
+			for _, l := range p.Labels {
+				fmt.Fprintf(w, "     %v\n", l)
+			}
+			for _, k := range sortedKeys(p.Annotations) {
+				fmt.Fprintf(w, "     Annotation \"%v\" with value \"%v\"\n", k, p.Annotations[k])
+			}
+			if p.Options != nil {
+				fmt.Fprintln(w, "     options overridden")
+			}
+		}
+		return nil
+	case JSON:
+		return json.NewEncoder(w).Encode(f.Profiles)
+	default:
+		return fmt.Errorf("bad format: %v", outputFormat)
+	}
+}
+
+// listEffectiveProfile writes the values of a function which has had the
+// named profile applied.
+func listEffectiveProfile(f fn.Function, profile string, w io.Writer, outputFormat Format) error {
+	switch outputFormat {
+	case Human:
+		fmt.Fprintf(w, "Effective configuration with profile %q:\n", profile)
+		fmt.Fprintf(w, "  namespace: %v\n", f.Deploy.Namespace)
+		fmt.Fprintf(w, "  registry: %v\n", f.Registry)
+		for _, e := range f.Run.Envs {
+			fmt.Fprintf(w, "  %v\n", e)
+		}
+		for _, l := range f.Deploy.Labels {
+			fmt.Fprintf(w, "  %v\n", l)
+		}
+		for _, k := range sortedKeys(f.Deploy.Annotations) {
+			fmt.Fprintf(w, "  Annotation \"%v\" with value \"%v\"\n", k, f.Deploy.Annotations[k])
+		}
+		return nil
+	case JSON:
+		return json.NewEncoder(w).Encode(struct {
+			Profile     string            `json:"profile"`
+			Namespace   string            `json:"namespace"`
+			Registry    string            `json:"registry"`
+			Envs        []fn.Env          `json:"envs"`
+			Labels      []fn.Label        `json:"labels"`
+			Annotations map[string]string `json:"annotations"`
+			Options     fn.Options        `json:"options"`
+		}{profile, f.Deploy.Namespace, f.Registry, f.Run.Envs, f.Deploy.Labels, f.Deploy.Annotations, f.Deploy.Options})
+	default:
+		return fmt.Errorf("bad format: %v", outputFormat)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	fn "knative.dev/func"
 	"knative.dev/func/k8s"
@@ -55,7 +56,8 @@ func NewConfigVolumesAddCmd() *cobra.Command {
 		Short: "Add volume to the function configuration",
 		Long: `Add volume to the function configuration
 
-Interactive prompt to add Secrets and ConfigMaps as Volume mounts to the function project
+Interactive prompt to add Secrets, ConfigMaps, PersistentVolumeClaims, EmptyDirs
+and ServiceAccountTokens as Volume mounts to the function project
 in the current directory or from the directory specified with --path.
 `,
 		SuggestFor: []string{"ad", "create", "insert", "append"},
@@ -119,12 +121,19 @@ func runAddVolumesPrompt(ctx context.Context, f fn.Function) (err error) {
 	if err != nil {
 		return
 	}
+	claims, err := k8s.ListPersistentVolumeClaimsNamesIfConnected(ctx, f.Deploy.Namespace)
+	if err != nil {
+		return
+	}
 
 	// SECTION - select resource type to be mounted
 	options := []string{}
 	selectedOption := ""
 	const optionConfigMap = "ConfigMap"
 	const optionSecret = "Secret"
+	const optionPersistentVolumeClaim = "PersistentVolumeClaim"
+	const optionEmptyDir = "EmptyDir"
+	const optionServiceAccountToken = "ServiceAccountToken"
 
 	if len(configMaps) > 0 {
 		options = append(options, optionConfigMap)
@@ -132,42 +141,42 @@ func runAddVolumesPrompt(ctx context.Context, f fn.Function) (err error) {
 	if len(secrets) > 0 {
 		options = append(options, optionSecret)
 	}
+	if len(claims) > 0 {
+		options = append(options, optionPersistentVolumeClaim)
+	}
+	// EmptyDir and ServiceAccountToken volumes do not refer to any resource
+	// in the cluster, so they are always available.
+	options = append(options, optionEmptyDir, optionServiceAccountToken)
 
-	switch len(options) {
-	case 0:
-		fmt.Printf("There aren't any Secrets or ConfiMaps in the namespace \"%s\"\n", f.Deploy.Namespace)
+	err = survey.AskOne(&survey.Select{
+		Message: "What do you want to mount as a Volume?",
+		Options: options,
+	}, &selectedOption)
+	if err != nil {
 		return
-	case 1:
-		selectedOption = options[0]
-	case 2:
-		err = survey.AskOne(&survey.Select{
-			Message: "What do you want to mount as a Volume?",
-			Options: options,
-		}, &selectedOption)
-		if err != nil {
-			return
-		}
 	}
 
 	// SECTION - select the specific resource to be mounted
 	optionsResoures := []string{}
-	resourceType := ""
+	resourceType := selectedOption
 	switch selectedOption {
 	case optionConfigMap:
-		resourceType = optionConfigMap
 		optionsResoures = configMaps
 	case optionSecret:
-		resourceType = optionSecret
 		optionsResoures = secrets
+	case optionPersistentVolumeClaim:
+		optionsResoures = claims
 	}
 
 	selectedResource := ""
-	err = survey.AskOne(&survey.Select{
-		Message: fmt.Sprintf("Which \"%s\" do you want to mount?", resourceType),
-		Options: optionsResoures,
-	}, &selectedResource)
-	if err != nil {
-		return
+	if len(optionsResoures) > 0 {
+		err = survey.AskOne(&survey.Select{
+			Message: fmt.Sprintf("Which \"%s\" do you want to mount?", resourceType),
+			Options: optionsResoures,
+		}, &selectedResource)
+		if err != nil {
+			return
+		}
 	}
 
 	// SECTION - specify mount Path of the Volume
@@ -185,15 +194,39 @@ func runAddVolumesPrompt(ctx context.Context, f fn.Function) (err error) {
 		return
 	}
 
-	// we have all necessary information -> let's store the new Volume
+	// SECTION - options specific to the type of the Volume
 	newVolume := fn.Volume{Path: &path}
 	switch selectedOption {
 	case optionConfigMap:
 		newVolume.ConfigMap = &selectedResource
 	case optionSecret:
 		newVolume.Secret = &selectedResource
+	case optionPersistentVolumeClaim:
+		claim := fn.PersistentVolumeClaim{ClaimName: &selectedResource}
+		err = survey.AskOne(&survey.Confirm{
+			Message: "Should the volume be mounted read-only?",
+			Default: false,
+		}, &claim.ReadOnly)
+		if err != nil {
+			return
+		}
+		newVolume.PersistentVolumeClaim = &claim
+	case optionEmptyDir:
+		if newVolume.EmptyDir, err = promptEmptyDir(); err != nil {
+			return
+		}
+	case optionServiceAccountToken:
+		token := fn.ServiceAccountToken{}
+		err = survey.AskOne(&survey.Input{
+			Message: "Please specify the audience of the token (optional):",
+		}, &token.Audience)
+		if err != nil {
+			return
+		}
+		newVolume.ServiceAccountToken = &token
 	}
 
+	// we have all necessary information -> let's store the new Volume
 	f.Run.Volumes = append(f.Run.Volumes, newVolume)
 
 	err = f.Write()
@@ -204,6 +237,43 @@ func runAddVolumesPrompt(ctx context.Context, f fn.Function) (err error) {
 	return
 }
 
+// promptEmptyDir for the medium and size limit of an EmptyDir volume.
+func promptEmptyDir() (emptyDir *fn.EmptyDir, err error) {
+	emptyDir = &fn.EmptyDir{}
+
+	inMemory := false
+	err = survey.AskOne(&survey.Confirm{
+		Message: "Should the volume be backed by memory (tmpfs)?",
+		Default: false,
+	}, &inMemory)
+	if err != nil {
+		return
+	}
+	if inMemory {
+		emptyDir.Medium = "Memory"
+	}
+
+	sizeLimit := ""
+	err = survey.AskOne(&survey.Input{
+		Message: "Please specify the size limit of the volume, such as 1Gi (optional):",
+	}, &sizeLimit, survey.WithValidator(func(val interface{}) error {
+		if str, ok := val.(string); ok && str != "" {
+			if _, err := resource.ParseQuantity(str); err != nil {
+				return fmt.Errorf("The input must be a valid quantity, such as 500Mi or 1Gi.")
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		return
+	}
+	if sizeLimit != "" {
+		emptyDir.SizeLimit = &sizeLimit
+	}
+
+	return
+}
+
 func runRemoveVolumesPrompt(f fn.Function) (err error) {
 	if len(f.Run.Volumes) == 0 {
 		fmt.Println("There aren't any configured Volume mounts")
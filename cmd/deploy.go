@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -15,15 +16,22 @@ import (
 	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/ory/viper"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/client/pkg/util"
+	"sigs.k8s.io/yaml"
 
 	fn "knative.dev/func"
 	"knative.dev/func/builders"
 	"knative.dev/func/buildpacks"
 	"knative.dev/func/config"
+	"knative.dev/func/deployers"
 	"knative.dev/func/docker"
 	"knative.dev/func/docker/creds"
+	"knative.dev/func/dockerfile"
 	"knative.dev/func/k8s"
+	"knative.dev/func/oci"
 	"knative.dev/func/s2i"
 )
 
@@ -39,11 +47,14 @@ SYNOPSIS
 	{{.Name}} deploy [-R|--remote] [-r|--registry] [-i|--image] [-n|--namespace]
 	             [-e|env] [-g|--git-url] [-t|git-branch] [-d|--git-dir]
 	             [-b|--build] [--builder] [--builder-image] [-p|--push]
-	             [--platform] [-c|--confirm] [-v|--verbose]
+	             [--platform] [--profile] [--tag] [--traffic] [--dry-run]
+	             [-o|--output] [--deployer] [--target] [-c|--confirm]
+	             [-v|--verbose]
 
 DESCRIPTION
 
-	Deploys a function to the currently configured Knative-enabled cluster.
+	Deploys a function to the currently configured Knative-enabled cluster, or
+	with --deployer=kubernetes to a cluster without Knative Serving.
 
 	By default the function in the current working directory is deployed, or at
 	the path defined by --path.
@@ -83,6 +94,58 @@ DESCRIPTION
 	  of a git repository instead of local source, combine with '--git-url':
 	  '{{.Name}} deploy --remote --git-url=git.example.com/alice/f.git'
 
+	Profiles
+	  A function may define named profiles (such as 'staging' or 'prod') in
+	  its func.yaml which override the namespace, registry, environment
+	  variables, options, labels and annotations of the base configuration.
+	  The --profile flag selects which profile's values are in effect for the
+	  deployment.  The function's base configuration is not modified.
+
+	Traffic
+	  By default all traffic is routed to the revision being deployed.  The
+	  --traffic flag routes only the given percentage to it, for example as a
+	  canary, with the remainder staying with the revisions currently serving.
+	  The --tag flag tags the revision being deployed, making it reachable at
+	  its own URL (see '{{.Name}} invoke --target').  Both are remembered for
+	  subsequent deployments, and can be reset with --traffic=100 --tag="".
+	  See '{{.Name}} traffic' to shift traffic among deployed revisions.
+
+	Domains
+	  The custom domains listed in deploy.domains of the function's func.yaml
+	  are mapped to it by Knative DomainMappings on each deploy, and those of
+	  domains no longer listed are removed.  Domains which do not become ready
+	  are reported.  DNS records of the domains are managed separately.
+
+	Deployers
+	  By default the function is deployed as a Knative Service.  Deploying with
+	  '{{.Name}} deploy --deployer=kubernetes' instead creates a Kubernetes
+	  Deployment and Service, for clusters without Knative Serving.  The
+	  function is exposed outside of the cluster by an Ingress if it defines
+	  deploy.ingress in its func.yaml, and is autoscaled on CPU utilization by
+	  a HorizontalPodAutoscaler if its scale options allow more than its
+	  minimum number of replicas.  It does not scale to zero, and traffic
+	  options are not supported.  The deployer is remembered for subsequent
+	  deployments.
+
+	Local
+	  With '{{.Name}} deploy --target local' the function is deployed as a
+	  long-lived container of the local container engine rather than to a
+	  cluster, such as for demos or when offline.  The container is detached,
+	  restarted unless removed, and reachable on a host port which is kept
+	  when the function is redeployed.  The image is not pushed unless --push
+	  is provided.  Locally deployed functions are included by '{{.Name}} list',
+	  invoked by '{{.Name}} invoke --target remote' and removed by
+	  '{{.Name}} delete'.  Secrets, ConfigMaps and volumes are not supported.
+
+	Dry Run
+	  The --dry-run flag prints the manifests with which the function would be
+	  deployed, such as its Knative Service and Triggers, rather than deploying
+	  it.  The function is not built, pushed or updated, and no connection to
+	  the cluster is required.  Manifests are printed as YAML, or as a JSON
+	  List with --output json.  Secrets, ConfigMaps and PersistentVolumeClaims
+	  referenced by the function are not printed, and must exist when the
+	  manifests are applied.
+
 EXAMPLES
 
 	o Deploy the function using interactive prompts. This is useful for the first
@@ -118,9 +181,27 @@ EXAMPLES
 	  manually deleted from the cluster, it can be quickly redeployed with:
 	  $ {{.Name}} deploy --build=false --push=false
 
+	o Deploy the function using the overrides defined by its 'staging' profile.
+	  $ {{.Name}} deploy --profile staging
+
+	o Deploy the function as a canary receiving 10% of traffic, reachable at
+	  the URL of the 'canary' tag.
+	  $ {{.Name}} deploy --tag canary --traffic 10
+
+	o Deploy the function as a Kubernetes Deployment and Service to a cluster
+	  without Knative Serving.
+	  $ {{.Name}} deploy --deployer kubernetes
+
+	o Deploy the function as a long-lived local container, without a cluster.
+	  $ {{.Name}} deploy --target local
+
+	o Print the manifests with which the function would be deployed using its
+	  'prod' profile, for review or for committing to a GitOps repository.
+	  $ {{.Name}} deploy --dry-run --profile prod > manifests.yaml
+
 `,
 		SuggestFor: []string{"delpoy", "deplyo"},
-		PreRunE:    bindEnv("confirm", "env", "git-url", "git-branch", "git-dir", "remote", "build", "builder", "builder-image", "image", "registry", "push", "platform", "path", "namespace"),
+		PreRunE:    bindEnv("confirm", "env", "git-url", "git-branch", "git-dir", "remote", "build", "builder", "builder-image", "image", "registry", "push", "platform", "path", "namespace", "profile", "tag", "traffic", "dry-run", "output", "deployer", "target"),
 	}
 
 	// Config
@@ -138,6 +219,8 @@ EXAMPLES
 	cmd.Flags().StringP("git-branch", "t", "", "Git branch to be used for remote builds (Env: $FUNC_GIT_BRANCH)")
 	cmd.Flags().StringP("git-dir", "d", "", "Directory in the repo where the function is located (Env: $FUNC_GIT_DIR)")
 	cmd.Flags().BoolP("remote", "", false, "Trigger a remote deployment.  Default is to deploy and build from the local system: $FUNC_REMOTE)")
+	cmd.Flags().StringP("target", "", fn.EnvironmentRemote, "Environment to which the function is deployed.  Can be 'remote' (the cluster) or 'local' (a long-lived local container). (Env: $FUNC_TARGET)")
+	cmd.Flags().StringP("deployer", "", "", fmt.Sprintf("Deployer with which the function is deployed. Currently supported deployers are %s.  Defaults to that of the function, or %q. (Env: $FUNC_DEPLOYER)", KnownDeployers(), deployers.Default))
 
 	// Flags shared with Build (specifically related to the build step):
 	cmd.Flags().StringP("build", "", "auto", "Build the function. [auto|true|false]. [Env: $FUNC_BUILD]")
@@ -147,8 +230,13 @@ EXAMPLES
 	cmd.Flags().StringP("image", "i", "", "Full image name in the form [registry]/[namespace]/[name]:[tag]@[digest]. This option takes precedence over --registry. Specifying digest is optional, but if it is given, 'build' and 'push' phases are disabled. (Env: $FUNC_IMAGE)")
 	cmd.Flags().StringP("registry", "r", "", "Registry + namespace part of the image to build, ex 'ghcr.io/myuser'.  The full image name is automatically determined. (Env: $FUNC_REGISTRY)")
 	cmd.Flags().BoolP("push", "u", true, "Push the function image to registry before deploying (Env: $FUNC_PUSH)")
-	cmd.Flags().StringP("platform", "", "", "Target platform to build (e.g. linux/amd64).")
+	cmd.Flags().StringP("platform", "", "", "Target platform to build (e.g. linux/amd64), or a comma-separated list of platforms for a multi-architecture image (e.g. linux/amd64,linux/arm64).")
 	cmd.Flags().StringP("namespace", "n", cfg.Namespace, "Deploy into a specific namespace. Will use function's current namespace by default if already deployed. (Env: $FUNC_NAMESPACE)")
+	cmd.Flags().StringP("profile", "", "", "Name of the function profile whose overrides are applied to the deployment (Env: $FUNC_PROFILE)")
+	cmd.Flags().StringP("tag", "", "", "Tag of the revision being deployed, at whose URL it is reachable directly. (Env: $FUNC_TAG)")
+	cmd.Flags().Int64P("traffic", "", 100, "Percent of traffic routed to the revision being deployed.  The remainder stays with the revisions currently serving. (Env: $FUNC_TRAFFIC)")
+	cmd.Flags().BoolP("dry-run", "", false, "Print the manifests with which the function would be deployed rather than deploying it. (Env: $FUNC_DRY_RUN)")
+	cmd.Flags().StringP("output", "o", "yaml", "Output format of the manifests printed by --dry-run (yaml|json) (Env: $FUNC_OUTPUT)")
 	setPathFlag(cmd)
 
 	if err := cmd.RegisterFlagCompletionFunc("builder", CompleteBuilderList); err != nil {
@@ -159,6 +247,10 @@ EXAMPLES
 		fmt.Println("internal: error while calling RegisterFlagCompletionFunc: ", err)
 	}
 
+	if err := cmd.RegisterFlagCompletionFunc("profile", CompleteProfileList); err != nil {
+		fmt.Println("internal: error while calling RegisterFlagCompletionFunc: ", err)
+	}
+
 	cmd.SetHelpFunc(defaultTemplatedHelp)
 
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
@@ -218,16 +310,38 @@ func runDeploy(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 		f.Deploy.Namespace = cfg.Namespace
 	}
 
+	if cmd.Flags().Changed("deployer") {
+		f.Deploy.Deployer = cfg.Deployer
+	}
 	if cmd.Flags().Changed("remote") {
 		f.Deploy.Remote = cfg.Remote
 	} else {
 		cfg.Remote = f.Deploy.Remote
 	}
+	if cmd.Flags().Changed("tag") || cmd.Flags().Changed("traffic") {
+		// Remembered for subsequent deploys until reset to the defaults
+		traffic := fn.TrafficOptions{}
+		if f.Deploy.Traffic != nil {
+			traffic = *f.Deploy.Traffic
+		}
+		if cmd.Flags().Changed("tag") {
+			traffic.Tag = cfg.Tag
+		}
+		if cmd.Flags().Changed("traffic") {
+			traffic.Percent = &cfg.Traffic
+		}
+		f.Deploy.Traffic = &traffic
+		if traffic.Tag == "" && (traffic.Percent == nil || *traffic.Percent == 100) {
+			f.Deploy.Traffic = nil
+		}
+	}
 	if cfg.Image != "" {
 		f.Image = cfg.Image
 	}
 	if cfg.ImageDigest != "" {
-		fmt.Fprintf(cmd.OutOrStdout(), "Deploying image '%v' with digest '%s'. Build and push are disabled.\n", f.Image, f.ImageDigest)
+		if !cfg.DryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "Deploying image '%v' with digest '%s'. Build and push are disabled.\n", f.Image, cfg.ImageDigest)
+		}
 		f.ImageDigest = cfg.ImageDigest
 	}
 	if cfg.Builder != "" {
@@ -261,6 +375,32 @@ func runDeploy(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 		return
 	}
 
+	// The selected profile (if any) is resolved here only to determine the
+	// effective namespace and registry; the client applies it in full.
+	// An explicitly provided --namespace takes precedence over the profile.
+	pf, err := f.ApplyProfile(cfg.Profile)
+	if err != nil {
+		return
+	}
+	namespace := f.Deploy.Namespace
+	if cfg.Profile != "" && !cmd.Flags().Changed("namespace") {
+		namespace = pf.Deploy.Namespace
+	}
+
+	// Choose a deployer based on the value of the --deployer flag or that
+	// previously set on the function, unless deploying to a local container.
+	var deployer fn.Deployer
+	if cfg.Target == fn.EnvironmentLocal {
+		deployer = docker.NewDeployer(cfg.Verbose)
+	} else {
+		if err = ValidateDeployer(pf.Deploy.Deployer); err != nil {
+			return
+		}
+		if deployer, err = newDeployer(pf.Deploy.Deployer, namespace, cfg.Verbose); err != nil {
+			return
+		}
+	}
+
 	// Choose a builder based on the value of the --builder flag and a possible
 	// override for the build image for that builder to use from the optional
 	// builder-image flag.
@@ -274,18 +414,31 @@ func runDeploy(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 			s2i.WithName(builders.S2I),
 			s2i.WithPlatform(cfg.Platform),
 			s2i.WithVerbose(cfg.Verbose))
+	} else if f.Build.Builder == builders.Dockerfile {
+		builder = dockerfile.NewBuilder(
+			dockerfile.WithName(builders.Dockerfile),
+			dockerfile.WithPlatform(cfg.Platform),
+			dockerfile.WithVerbose(cfg.Verbose))
+	} else if f.Build.Builder == builders.Host {
+		builder = oci.NewBuilder(
+			oci.WithName(builders.Host),
+			oci.WithPlatform(cfg.Platform),
+			oci.WithVerbose(cfg.Verbose))
 	} else {
 		err = fmt.Errorf("builder '%v' is not recognized", f.Build.Builder)
 		return
 	}
 
-	client, done := newClient(ClientConfig{Namespace: f.Deploy.Namespace, Verbose: cfg.Verbose},
+	client, done := newClient(ClientConfig{Namespace: namespace, Verbose: cfg.Verbose},
 		fn.WithRegistry(cfg.Registry),
-		fn.WithBuilder(builder))
+		fn.WithBuilder(builder),
+		fn.WithDeployer(deployer),
+		fn.WithPlatform(cfg.Platform),
+		fn.WithProfile(cfg.Profile))
 	defer done()
 
 	// Default Client Registry, Function Registry or explicit Image required
-	if client.Registry() == "" && f.Registry == "" && f.Image == "" {
+	if client.Registry() == "" && pf.Registry == "" && f.Image == "" {
 		if interactiveTerminal() {
 			// to be consistent, this should throw an error, with the registry
 			// prompting code placed within cfg.Prompt and triggered with --confirm
@@ -302,6 +455,22 @@ func runDeploy(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 		return fn.ErrRegistryRequired
 	}
 
+	// The function as updated by flags is validated here as it is not written
+	// before rendering or a remote deployment.
+	if err = f.Validate(); err != nil {
+		return
+	}
+
+	// Print the manifests rather than building and deploying.  The function
+	// is not written, as flags are not persisted without a deployment.
+	if cfg.DryRun {
+		m, err := client.Render(cmd.Context(), f)
+		if err != nil {
+			return err
+		}
+		return printManifests(cmd, m, cfg.Output)
+	}
+
 	// Perform the deployment either remote or local.
 	if cfg.Remote {
 		// Invoke a remote build/push/deploy pipeline
@@ -321,7 +490,9 @@ func runDeploy(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 		if f, err = fn.NewFunction(f.Root); err != nil { // TODO: remove when client API uses 'f'
 			return
 		}
-		if cfg.Push {
+		// A local container runs the image as built, which need not be
+		// pushed unless requested.
+		if cfg.Push && (cfg.Target != fn.EnvironmentLocal || cmd.Flags().Changed("push")) {
 			if err = client.Push(cmd.Context(), f.Root); err != nil {
 				return
 			}
@@ -338,6 +509,47 @@ func runDeploy(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 	return f.Write()
 }
 
+// printManifests to stdout as a stream of YAML documents, or as a JSON List,
+// noting on stderr the Secrets, ConfigMaps and PersistentVolumeClaims they
+// reference.
+func printManifests(cmd *cobra.Command, m fn.Manifests, output string) error {
+	out := cmd.OutOrStdout()
+	if output == "json" {
+		list := corev1.List{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"}}
+		for _, o := range m.Objects {
+			list.Items = append(list.Items, runtime.RawExtension{Object: o})
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(list); err != nil {
+			return err
+		}
+	} else {
+		for i, o := range m.Objects {
+			bb, err := yaml.Marshal(o)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				fmt.Fprintln(out, "---")
+			}
+			if _, err = out.Write(bb); err != nil {
+				return err
+			}
+		}
+	}
+	if len(m.Secrets) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Note: the function references the Secrets %v, which must exist in the target namespace\n", strings.Join(m.Secrets, ", "))
+	}
+	if len(m.ConfigMaps) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Note: the function references the ConfigMaps %v, which must exist in the target namespace\n", strings.Join(m.ConfigMaps, ", "))
+	}
+	if len(m.PersistentVolumeClaims) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Note: the function references the PersistentVolumeClaims %v, which must exist in the target namespace\n", strings.Join(m.PersistentVolumeClaims, ", "))
+	}
+	return nil
+}
+
 // build returns true if the value of buildStr is a truthy value, or if
 // it is the literal "auto" and the function reports as being currently
 // unbuilt.  Invalid errors are not reported as this is the purview of
@@ -385,6 +597,27 @@ func ValidateBuilder(name string) (err error) {
 	return builders.ErrUnknownBuilder{Name: name, Known: KnownBuilders()}
 }
 
+// ValidateDeployer ensures that the given deployer is one that the CLI
+// knows how to instantiate, returning a deployers.ErrUnknownDeployer
+// otherwise.  An empty name indicates the default deployer.
+func ValidateDeployer(name string) (err error) {
+	if name == "" {
+		return
+	}
+	for _, known := range KnownDeployers() {
+		if name == known {
+			return
+		}
+	}
+	return deployers.ErrUnknownDeployer{Name: name, Known: KnownDeployers()}
+}
+
+// KnownDeployers are a typed string slice of deployer short names which
+// this CLI understands.
+func KnownDeployers() deployers.Known {
+	return deployers.All()
+}
+
 // KnownBuilders are a typed string slice of builder short names which this
 // CLI understands.  Includes a customized String() representation intended
 // for use in flags and help text.
@@ -551,6 +784,29 @@ type deployConfig struct {
 
 	// ImageDigest is automatically split off an --image tag
 	ImageDigest string
+
+	// Profile is the name of the function profile whose overrides are
+	// applied to the deployment.  Empty indicates the base configuration.
+	Profile string
+
+	// Tag of the revision being deployed.
+	Tag string
+
+	// Traffic is the percent of traffic routed to the revision being deployed.
+	Traffic int64
+
+	// Deployer is the name of the deployer with which the function is
+	// deployed.  Empty indicates that of the function.
+	Deployer string
+
+	// Target environment of the deployment: the cluster ('remote') or a
+	// long-lived local container ('local').
+	Target string
+
+	// DryRun prints the manifests with which the function would be deployed
+	// in the format of Output (yaml or json) rather than deploying it.
+	DryRun bool
+	Output string
 }
 
 // newDeployConfig creates a buildConfig populated from command flags and
@@ -572,11 +828,21 @@ func newDeployConfig(cmd *cobra.Command) (deployConfig, error) {
 		GitBranch:   viper.GetString("git-branch"),
 		GitDir:      viper.GetString("git-dir"),
 		ImageDigest: "", // automatically split off --image if provided below
+		Profile:     viper.GetString("profile"),
+		Tag:         viper.GetString("tag"),
+		Traffic:     viper.GetInt64("traffic"),
+		Deployer:    viper.GetString("deployer"),
+		Target:      viper.GetString("target"),
+		DryRun:      viper.GetBool("dry-run"),
+		Output:      viper.GetString("output"),
 	}
 	if c.Image, c.ImageDigest, err = parseImage(c.Image); err != nil {
 		return c, err
 	}
 
+	// The --output of deploy is the format of --dry-run, not that of build.
+	c.buildConfig.Output = ""
+
 	return c, nil
 }
 
@@ -685,6 +951,33 @@ func (c deployConfig) Validate() (err error) {
 		return fmt.Errorf("invalid --git-url '%v'", c.GitURL)
 	}
 
+	if c.Traffic < 0 || c.Traffic > 100 {
+		return fmt.Errorf("invalid --traffic %v, must be between 0 and 100", c.Traffic)
+	}
+
+	if err = ValidateDeployer(c.Deployer); err != nil {
+		return
+	}
+
+	if c.Output != "yaml" && c.Output != "json" {
+		return fmt.Errorf("unrecognized value for --output '%v'.  accepts 'yaml' or 'json'", c.Output)
+	}
+
+	// A remote deployment is rendered by the cluster's pipeline
+	if c.DryRun && c.Remote {
+		return errors.New("--dry-run is not supported when triggering remote deployments (--remote)")
+	}
+
+	if c.Target != fn.EnvironmentRemote && c.Target != fn.EnvironmentLocal {
+		return fmt.Errorf("unrecognized value for --target '%v'.  accepts 'remote' or 'local'", c.Target)
+	}
+
+	// A local container is neither deployed by a pipeline nor described by
+	// manifests
+	if c.Target == fn.EnvironmentLocal && (c.Remote || c.DryRun) {
+		return errors.New("--remote and --dry-run are not supported when deploying to a local container (--target local)")
+	}
+
 	// --build can be "auto"|true|false
 	if c.Build != "auto" {
 		if _, err := strconv.ParseBool(c.Build); err != nil {
@@ -729,8 +1022,11 @@ func namespaceWarnings(cfg deployConfig, cmd *cobra.Command) {
 	if err != nil {
 		active = "default"
 	}
+	f, _ := fn.NewFunction(cfg.Path)
+	if pf, err := f.ApplyProfile(cfg.Profile); err == nil {
+		f = pf // the profile's namespace is that which is current
+	}
 	var (
-		f, _         = fn.NewFunction(cfg.Path)
 		current      = f.Deploy.Namespace               // Current
 		target       = current                          // Target Current by default
 		flagValue    = cfg.Namespace                    // Flag Value
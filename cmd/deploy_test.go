@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -13,6 +16,8 @@ import (
 	"github.com/spf13/cobra"
 	fn "knative.dev/func"
 	"knative.dev/func/builders"
+	"knative.dev/func/deployers"
+	"knative.dev/func/knative"
 	"knative.dev/func/mock"
 )
 
@@ -214,8 +219,8 @@ func testRegistryLoads(cmdFn commandConstructor, t *testing.T) {
 	}
 
 	expected := "example.com/alice/myFunc:latest"
-	if f.Image != expected {
-		t.Fatalf("expected image name '%v'. got %v", expected, f.Image)
+	if f.Built.Image != expected {
+		t.Fatalf("expected image name '%v'. got %v", expected, f.Built.Image)
 	}
 }
 
@@ -984,3 +989,281 @@ func TestDeploy_RemotePersists(t *testing.T) {
 		t.Fatalf("value of remote flag not persisted")
 	}
 }
+
+// TestDeploy_Profile ensures that a profile provided with --profile must be
+// defined by the function, and that deploying with a profile does not write
+// its values to the function's base configuration.
+func TestDeploy_Profile(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	if err := fn.New().Create(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Profiles = map[string]fn.Profile{"staging": {Namespace: "staging"}}
+	if err = f.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	// An undefined profile fails
+	cmd := NewDeployCmd(NewTestClient())
+	cmd.SetArgs([]string{"--profile", "prod"})
+	if err = cmd.Execute(); !errors.Is(err, fn.ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound, got %v", err)
+	}
+
+	// A defined profile succeeds, and its namespace is not persisted
+	cmd = NewDeployCmd(NewTestClient())
+	cmd.SetArgs([]string{"--profile", "staging"})
+	if err = cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = fn.NewFunction(root); err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.Namespace == "staging" {
+		t.Fatal("profile namespace was persisted to the base configuration")
+	}
+}
+
+// TestDeploy_TrafficPersists ensures that the --tag and --traffic flags are
+// passed to the deployer and remembered, until reset to their defaults.
+func TestDeploy_TrafficPersists(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	if err := fn.New().Create(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+
+	deployer := mock.NewDeployer()
+	deployer.DeployFn = func(_ context.Context, f fn.Function) (fn.DeploymentResult, error) {
+		if f.Deploy.Traffic == nil || f.Deploy.Traffic.Tag != "canary" || *f.Deploy.Traffic.Percent != 10 {
+			t.Errorf("expected canary traffic options, got %+v", f.Deploy.Traffic)
+		}
+		return fn.DeploymentResult{}, nil
+	}
+	cmd := NewDeployCmd(NewTestClient(fn.WithDeployer(deployer)))
+	cmd.SetArgs([]string{"--tag=canary", "--traffic=10"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !deployer.DeployInvoked {
+		t.Fatal("deployer not invoked")
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.Traffic == nil || f.Deploy.Traffic.Tag != "canary" {
+		t.Fatalf("traffic options not persisted, got %+v", f.Deploy.Traffic)
+	}
+
+	// Resetting to the defaults removes the options
+	viper.Reset()
+	cmd = NewDeployCmd(NewTestClient())
+	cmd.SetArgs([]string{"--tag=", "--traffic=100"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = fn.NewFunction(root); err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.Traffic != nil {
+		t.Fatalf("expected traffic options to be reset, got %+v", f.Deploy.Traffic)
+	}
+
+	// An out of range percentage is invalid
+	viper.Reset()
+	cmd = NewDeployCmd(NewTestClient())
+	cmd.SetArgs([]string{"--traffic=101"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error with --traffic=101")
+	}
+}
+
+// TestDeploy_DeployerPersists ensures that the deployer provided with
+// --deployer is validated and remembered for subsequent deploys.
+func TestDeploy_DeployerPersists(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	if err := fn.New().Create(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unknown deployer fails
+	cmd := NewDeployCmd(NewTestClient())
+	cmd.SetArgs([]string{"--deployer=invalid"})
+	if err := cmd.Execute(); !errors.As(err, &deployers.ErrUnknownDeployer{}) {
+		t.Fatalf("expected ErrUnknownDeployer, got %v", err)
+	}
+
+	// A known deployer is persisted
+	viper.Reset()
+	cmd = NewDeployCmd(NewTestClient())
+	cmd.SetArgs([]string{"--deployer", deployers.Kubernetes})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.Deployer != deployers.Kubernetes {
+		t.Fatalf("expected deployer '%v' to be persisted, got '%v'", deployers.Kubernetes, f.Deploy.Deployer)
+	}
+
+	// and retained when not provided
+	viper.Reset()
+	cmd = NewDeployCmd(NewTestClient())
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = fn.NewFunction(root); err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.Deployer != deployers.Kubernetes {
+		t.Fatalf("expected deployer '%v' to be retained, got '%v'", deployers.Kubernetes, f.Deploy.Deployer)
+	}
+}
+
+// TestDeploy_TargetValidated ensures that the --target flag accepts only the
+// cluster or a local container, and that a local deployment is neither
+// remote nor a dry run.
+func TestDeploy_TargetValidated(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	if err := fn.New().Create(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"--target=cluster"},
+		{"--target=local", "--remote"},
+		{"--target=local", "--dry-run"},
+	} {
+		viper.Reset()
+		cmd := NewDeployCmd(NewTestClient())
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("expected an error with %v", args)
+		}
+	}
+}
+
+// TestDeploy_DomainsKubernetes ensures that custom domains, which are mapped
+// to a Knative Service, are rejected when deploying with the kubernetes
+// deployer, including remotely where the function is not first written.
+func TestDeploy_DomainsKubernetes(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	f := fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}
+	if err := fn.New().Create(f); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Deploy.Domains = []fn.Domain{{Name: "api.example.com"}}
+	if err = f.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	pipeliner := mock.NewPipelinesProvider()
+	cmd := NewDeployCmd(NewTestClient(fn.WithPipelinesProvider(pipeliner)))
+	cmd.SetArgs([]string{"--deployer", "kubernetes", "--remote"})
+	if err = cmd.Execute(); err == nil || !strings.Contains(err.Error(), "domains can not be used with the 'kubernetes' deployer") {
+		t.Fatalf("expected domains to be rejected with the kubernetes deployer, got %v", err)
+	}
+	if pipeliner.RunInvoked {
+		t.Fatal("remote deployment triggered")
+	}
+}
+
+// TestDeploy_DryRun ensures that --dry-run prints the manifests of the
+// function without building, deploying or modifying it.
+func TestDeploy_DryRun(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	if err := fn.New().Create(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(filepath.Join(root, fn.FunctionFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder := mock.NewBuilder()
+	deployer := knative.NewDeployer(knative.WithDeployerNamespace("ns"))
+	cmd := NewDeployCmd(NewTestClient(fn.WithBuilder(builder), fn.WithDeployer(deployer)))
+	cmd.SetArgs([]string{"--dry-run", "--tag=canary"})
+	out := bytes.Buffer{}
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if builder.BuildInvoked {
+		t.Error("builder invoked on a dry run")
+	}
+	for _, s := range []string{"kind: Service", "namespace: ns", "image: " + TestRegistry + "/", "tag: canary"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("expected the manifests to contain %q, got:\n%v", s, out.String())
+		}
+	}
+	after, err := os.ReadFile(filepath.Join(root, fn.FunctionFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Errorf("function modified by a dry run:\n%s", after)
+	}
+
+	// Manifests can be printed as a JSON List
+	viper.Reset()
+	cmd = NewDeployCmd(NewTestClient(fn.WithBuilder(builder), fn.WithDeployer(deployer)))
+	cmd.SetArgs([]string{"--dry-run", "-o", "json"})
+	out.Reset()
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var list struct {
+		Kind  string
+		Items []struct{ Kind string }
+	}
+	if err := json.Unmarshal(out.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Kind != "List" || len(list.Items) != 1 || list.Items[0].Kind != "Service" {
+		t.Errorf("unexpected list of manifests %+v", list)
+	}
+
+	// The image is that of a given digest, as it would be deployed
+	viper.Reset()
+	image := "example.com/alice/f@sha256:" + strings.Repeat("a", 64)
+	cmd = NewDeployCmd(NewTestClient(fn.WithBuilder(builder), fn.WithDeployer(deployer)))
+	cmd.SetArgs([]string{"--dry-run", "--image", image, "--push=false"})
+	out.Reset()
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "image: "+image) {
+		t.Errorf("expected the manifests to contain the image %v, got:\n%v", image, out.String())
+	}
+	if strings.Contains(out.String(), "Build and push are disabled") {
+		t.Errorf("unexpected message in the manifests:\n%v", out.String())
+	}
+
+	// Other formats are invalid
+	viper.Reset()
+	cmd = NewDeployCmd(NewTestClient(fn.WithBuilder(builder), fn.WithDeployer(deployer)))
+	cmd.SetArgs([]string{"--dry-run", "-o", "xml"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error with --output xml")
+	}
+}
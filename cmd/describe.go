@@ -3,9 +3,12 @@ package cmd
 import (
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 
 	"github.com/ory/viper"
 	"github.com/spf13/cobra"
@@ -13,6 +16,7 @@ import (
 
 	fn "knative.dev/func"
 	"knative.dev/func/config"
+	"knative.dev/func/sbom"
 )
 
 func NewDescribeCmd(newClient ClientFactory) *cobra.Command {
@@ -23,6 +27,10 @@ func NewDescribeCmd(newClient ClientFactory) *cobra.Command {
 
 Prints the name, route and event subscriptions for a deployed function in
 the current directory or from the directory specified with --path.
+
+With --sbom, the software bill of materials (SBOM) generated of the image most
+recently built is printed instead, in the format set by build.sbom, and the
+SBOMs recorded by any buildpacks which built it are listed.
 `,
 		Example: `
 # Show the details of a function as declared in the local func.yaml
@@ -30,12 +38,18 @@ the current directory or from the directory specified with --path.
 
 # Show the details of the function in the directory with yaml output
 {{.Name}} info --output yaml --path myotherfunc
+
+# Show the details of the function as deployed using its 'staging' profile
+{{.Name}} info --profile staging
+
+# Show the SBOM of the image most recently built
+{{.Name}} info --sbom
 `,
 		SuggestFor: []string{"ifno", "fino", "get"},
 
 		ValidArgsFunction: CompleteFunctionList,
 		Aliases:           []string{"info", "desc"},
-		PreRunE:           bindEnv("output", "path", "namespace"),
+		PreRunE:           bindEnv("output", "path", "namespace", "profile", "sbom"),
 	}
 
 	// Config
@@ -47,11 +61,16 @@ the current directory or from the directory specified with --path.
 	// Flags
 	cmd.Flags().StringP("output", "o", "human", "Output format (human|plain|json|xml|yaml|url) (Env: $FUNC_OUTPUT)")
 	cmd.Flags().StringP("namespace", "n", cfg.Namespace, "The namespace in which to look for the named function. (Env: $FUNC_NAMESPACE)")
+	cmd.Flags().StringP("profile", "", "", "Name of the function profile whose overrides are in effect (Env: $FUNC_PROFILE)")
+	cmd.Flags().BoolP("sbom", "", false, "Print the SBOM of the image most recently built rather than describing the deployed function (Env: $FUNC_SBOM)")
 	setPathFlag(cmd)
 
 	if err := cmd.RegisterFlagCompletionFunc("output", CompleteOutputFormatList); err != nil {
 		fmt.Println("internal: error while calling RegisterFlagCompletionFunc: ", err)
 	}
+	if err := cmd.RegisterFlagCompletionFunc("profile", CompleteProfileList); err != nil {
+		fmt.Println("internal: error while calling RegisterFlagCompletionFunc: ", err)
+	}
 
 	cmd.SetHelpFunc(defaultTemplatedHelp)
 
@@ -78,6 +97,9 @@ func runDescribe(cmd *cobra.Command, args []string, newClient ClientFactory) (er
 		if !f.Initialized() {
 			return fmt.Errorf("the given path '%v' does not contain an initialized function.", cfg.Path)
 		}
+		if cfg.SBOM {
+			return describeSBOM(cmd, f, cfg.Profile)
+		}
 		// Use Function's Namespace with precedence
 		//
 		// Unless the namespace flag was explicitly provided (not the default),
@@ -86,11 +108,16 @@ func runDescribe(cmd *cobra.Command, args []string, newClient ClientFactory) (er
 		// TODO(lkingland): this stanza can be removed when Global Config: Function
 		// Context is merged.
 		if !cmd.Flags().Changed("namespace") {
-			cfg.Namespace = f.Deploy.Namespace
+			pf, err := f.ApplyProfile(cfg.Profile)
+			if err != nil {
+				return err
+			}
+			cfg.Namespace = pf.Deploy.Namespace
 		}
 	}
 
-	client, done := newClient(ClientConfig{Namespace: cfg.Namespace, Verbose: cfg.Verbose})
+	client, done := newClient(ClientConfig{Namespace: cfg.Namespace, Verbose: cfg.Verbose},
+		fn.WithProfile(cfg.Profile))
 	defer done()
 
 	// TODO(lkingland): update API to use the above function instance rather than path
@@ -111,6 +138,8 @@ type describeConfig struct {
 	Namespace string
 	Output    string
 	Path      string
+	Profile   string
+	SBOM      bool
 	Verbose   bool
 }
 
@@ -119,6 +148,8 @@ func newDescribeConfig(args []string) describeConfig {
 		Namespace: viper.GetString("namespace"),
 		Output:    viper.GetString("output"),
 		Path:      viper.GetString("path"),
+		Profile:   viper.GetString("profile"),
+		SBOM:      viper.GetBool("sbom"),
 		Verbose:   viper.GetBool("verbose"),
 	}
 	if len(args) > 0 {
@@ -131,9 +162,53 @@ func (c describeConfig) Validate(cmd *cobra.Command) (err error) {
 	if c.Name != "" && c.Path != "" && cmd.Flags().Changed("path") {
 		return fmt.Errorf("Only one of --path or [NAME] should be provided")
 	}
+	if c.Name != "" && c.Profile != "" {
+		return fmt.Errorf("--profile can only be used when describing the function at --path")
+	}
+	if c.Name != "" && c.SBOM {
+		return fmt.Errorf("--sbom can only be used when describing the function at --path")
+	}
 	return
 }
 
+// describeSBOM prints the SBOM of the function's image most recently built,
+// kept in .func, and lists to stderr those recorded by the buildpacks which
+// built it, relative to the function's root, such that the SBOM printed may
+// be piped as is.
+func describeSBOM(cmd *cobra.Command, f fn.Function, profile string) error {
+	pf, err := f.ApplyProfile(profile)
+	if err != nil {
+		return err
+	}
+	file, _ := pf.SBOMFile()
+	if file == "" {
+		return fmt.Errorf("the function has no SBOM.  Set build.sbom to %v or %v and build it", fn.SBOMFormatSPDX, fn.SBOMFormatCycloneDX)
+	}
+	doc, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("the function has no SBOM.  Has it been built since build.sbom was set?")
+	} else if err != nil {
+		return err
+	}
+	if _, err = cmd.OutOrStdout().Write(doc); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(pf.SBOMDir(), sbom.BuildpacksDir)
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		} else if err != nil || d.IsDir() {
+			return err
+		}
+		if path, err = filepath.Rel(pf.Root, path); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Buildpack SBOM: %v\n", path)
+		return nil
+	})
+}
+
 // Output Formatting (serializers)
 // -------------------------------
 
@@ -146,6 +221,10 @@ func (i info) Human(w io.Writer) error {
 	fmt.Fprintf(w, "  %v\n", i.Image)
 	fmt.Fprintln(w, "Function is deployed in namespace:")
 	fmt.Fprintf(w, "  %v\n", i.Namespace)
+	if i.Profile != "" {
+		fmt.Fprintln(w, "Function profile in effect:")
+		fmt.Fprintf(w, "  %v\n", i.Profile)
+	}
 	fmt.Fprintln(w, "Routes:")
 
 	for _, route := range i.Routes {
@@ -158,6 +237,13 @@ func (i info) Human(w io.Writer) error {
 			fmt.Fprintf(w, "  %v %v %v\n", s.Source, s.Type, s.Broker)
 		}
 	}
+
+	if timeouts := timeoutsOf(i.Timeouts); len(timeouts) > 0 {
+		fmt.Fprintln(w, "Timeouts:")
+		for _, t := range timeouts {
+			fmt.Fprintf(w, "  %v %vs\n", t.name, t.seconds)
+		}
+	}
 	return nil
 }
 
@@ -165,6 +251,9 @@ func (i info) Plain(w io.Writer) error {
 	fmt.Fprintf(w, "Name %v\n", i.Name)
 	fmt.Fprintf(w, "Image %v\n", i.Image)
 	fmt.Fprintf(w, "Namespace %v\n", i.Namespace)
+	if i.Profile != "" {
+		fmt.Fprintf(w, "Profile %v\n", i.Profile)
+	}
 
 	for _, route := range i.Routes {
 		fmt.Fprintf(w, "Route %v\n", route)
@@ -175,9 +264,35 @@ func (i info) Plain(w io.Writer) error {
 			fmt.Fprintf(w, "Subscription %v %v %v\n", s.Source, s.Type, s.Broker)
 		}
 	}
+
+	for _, t := range timeoutsOf(i.Timeouts) {
+		fmt.Fprintf(w, "Timeout %v %v\n", t.name, t.seconds)
+	}
 	return nil
 }
 
+type timeout struct {
+	name    string
+	seconds int64
+}
+
+// timeoutsOf a function which are set, in order.
+func timeoutsOf(o *fn.TimeoutsOptions) (timeouts []timeout) {
+	if o == nil {
+		return
+	}
+	if o.Request != nil {
+		timeouts = append(timeouts, timeout{"request", *o.Request})
+	}
+	if o.ResponseStart != nil {
+		timeouts = append(timeouts, timeout{"responseStart", *o.ResponseStart})
+	}
+	if o.Idle != nil {
+		timeouts = append(timeouts, timeout{"idle", *o.Idle})
+	}
+	return
+}
+
 func (i info) JSON(w io.Writer) error {
 	return json.NewEncoder(w).Encode(i)
 }
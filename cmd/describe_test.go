@@ -1,11 +1,18 @@
 package cmd
 
 import (
+	"bytes"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
+	"knative.dev/pkg/ptr"
+
 	fn "knative.dev/func"
 	"knative.dev/func/mock"
+	"knative.dev/func/sbom"
 )
 
 // TestDescribe_ByName ensures that describing a function by name invokes
@@ -138,3 +145,88 @@ func TestDescribe_Namespace(t *testing.T) {
 	}
 
 }
+
+// TestDescribe_Timeouts ensures that the timeouts of a deployed function are
+// included in its description when set.
+func TestDescribe_Timeouts(t *testing.T) {
+	i := info(fn.Instance{
+		Name:     "testname",
+		Timeouts: &fn.TimeoutsOptions{Request: ptr.Int64(600), Idle: ptr.Int64(120)},
+	})
+
+	var b bytes.Buffer
+	if err := i.Human(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "Timeouts:\n  request 600s\n  idle 120s\n") {
+		t.Errorf("expected request and idle timeouts in description, got:\n%v", b.String())
+	}
+
+	b.Reset()
+	if err := i.Plain(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "Timeout request 600\nTimeout idle 120\n") {
+		t.Errorf("expected request and idle timeouts in plain description, got:\n%v", b.String())
+	}
+}
+
+// TestDescribe_SBOM ensures that the SBOM of the image most recently built is
+// printed, with those of its buildpacks listed, without describing the
+// deployed function.
+func TestDescribe_SBOM(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	f := fn.Function{Root: root, Runtime: "go", Registry: TestRegistry}
+	if err := fn.New().Create(f); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Build.SBOM = fn.SBOMFormatCycloneDX
+	if err = f.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	describer := mock.NewDescriber()
+	newCmd := func() (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+		var stdout, stderr bytes.Buffer
+		cmd := NewDescribeCmd(NewTestClient(fn.WithDescriber(describer)))
+		cmd.SetArgs([]string{"--sbom"})
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		return cmd, &stdout, &stderr
+	}
+
+	// Not yet built
+	if cmd, _, _ := newCmd(); cmd.Execute() == nil {
+		t.Fatal("expected describing the SBOM of a function not built to error")
+	}
+
+	// Built
+	file, _ := f.SBOMFile()
+	bpFile := filepath.Join(f.SBOMDir(), sbom.BuildpacksDir, "launch", "paketo-buildpacks_go-build", "sbom.cdx.json")
+	if err = os.MkdirAll(filepath.Dir(bpFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{file, bpFile} {
+		if err = os.WriteFile(path, []byte(`{"bomFormat": "CycloneDX"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd, stdout, stderr := newCmd()
+	if err = cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != `{"bomFormat": "CycloneDX"}` {
+		t.Errorf("expected the SBOM to be printed, got %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), filepath.Join(fn.RunDataDir, "sbom", sbom.BuildpacksDir, "launch", "paketo-buildpacks_go-build", "sbom.cdx.json")) {
+		t.Errorf("expected the buildpack SBOM to be listed, got %q", stderr.String())
+	}
+	if describer.DescribeInvoked {
+		t.Error("expected the deployed function not to be described")
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+
+	fn "knative.dev/func"
+	"knative.dev/func/config"
+	"knative.dev/func/export"
+)
+
+func NewExportCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <dir>",
+		Short: "Export a function as a package of deployment manifests",
+		Long: `Export a function as a package of deployment manifests
+
+Writes the manifests with which the function in the current directory or from
+the directory specified with --path would be deployed to the given directory,
+for delivery by tools such as Argo CD or Flux rather than by '{{.Name}} deploy'.
+The function is not built; the image of its most recent build is used, or if
+it has not been built the image into which it would be built.
+
+The --format flag selects the kind of package:
+
+  raw        The manifests, one per file.
+  kustomize  A Kustomize base.  Overlays override the image using 'images',
+             the namespace using 'namespace', and the values of environment
+             variables by merging into the ConfigMap '<name>-env'.
+  helm       A Helm chart whose values are the image (image.repository,
+             image.tag and image.digest), namespace and environment variables
+             (env) of the function.
+
+The version of Kustomize bases and Helm charts is given by --version.  Secrets and ConfigMaps referenced by the function are not
+exported, and must exist when the package is deployed.
+`,
+		Example: `
+# Export the function in the current directory as a Kustomize base
+{{.Name}} export --format kustomize ./deploy/base
+
+# Export the function using its 'prod' profile as a Helm chart of version 1.2.0
+{{.Name}} export --format helm --version 1.2.0 --profile prod ./charts/myfunc
+`,
+		SuggestFor: []string{"exprot", "render"},
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    bindEnv("path", "namespace", "format", "version", "profile"),
+	}
+
+	// Config
+	cfg, err := config.NewDefault()
+	if err != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "error loading config at '%v'. %v\n", config.File(), err)
+	}
+
+	// Flags
+	cmd.Flags().StringP("namespace", "n", cfg.Namespace, "Namespace into which the package is deployed by default.  Defaults to that of the function. (Env: $FUNC_NAMESPACE)")
+	cmd.Flags().StringP("format", "", string(export.Raw), fmt.Sprintf("Format of the package %v (Env: $FUNC_FORMAT)", export.Formats()))
+	cmd.Flags().StringP("version", "", export.DefaultVersion, "Semantic version of the package. (Env: $FUNC_VERSION)")
+	cmd.Flags().StringP("profile", "", "", "Name of the function profile whose overrides are applied to the package (Env: $FUNC_PROFILE)")
+	setPathFlag(cmd)
+
+	if err := cmd.RegisterFlagCompletionFunc("profile", CompleteProfileList); err != nil {
+		fmt.Println("internal: error while calling RegisterFlagCompletionFunc: ", err)
+	}
+
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runExport(cmd, args, newClient)
+	}
+
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string, newClient ClientFactory) (err error) {
+	cfg := newExportConfig()
+
+	f, err := fn.NewFunction(cfg.Path)
+	if err != nil {
+		return
+	}
+	if !f.Initialized() {
+		return fmt.Errorf("the given path '%v' does not contain an initialized function", cfg.Path)
+	}
+
+	// The profile (if any) is applied by the client when rendering, and here
+	// to determine the package's namespace and environment variables.  The
+	// namespace of the function is used unless the flag was explicitly
+	// provided.
+	pf, err := f.ApplyProfile(cfg.Profile)
+	if err != nil {
+		return
+	}
+	if !cmd.Flags().Changed("namespace") && pf.Deploy.Namespace != "" {
+		cfg.Namespace = pf.Deploy.Namespace
+	}
+
+	deployer, err := newDeployer(pf.Deploy.Deployer, cfg.Namespace, cfg.Verbose)
+	if err != nil {
+		return
+	}
+
+	client, done := newClient(ClientConfig{Namespace: cfg.Namespace, Verbose: cfg.Verbose},
+		fn.WithDeployer(deployer),
+		fn.WithProfile(cfg.Profile))
+	defer done()
+
+	m, err := client.Render(cmd.Context(), f)
+	if err != nil {
+		return
+	}
+	err = export.Write(args[0], pf, m, export.Options{
+		Format:    export.Format(cfg.Format),
+		Version:   cfg.Version,
+		Namespace: cfg.Namespace,
+	})
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Function exported to %v\n", args[0])
+	return
+}
+
+// CLI Configuration (parameters)
+// ------------------------------
+
+type exportConfig struct {
+	Namespace string
+	Format    string
+	Version   string
+	Profile   string
+	Path      string
+	Verbose   bool
+}
+
+func newExportConfig() exportConfig {
+	return exportConfig{
+		Namespace: viper.GetString("namespace"),
+		Format:    viper.GetString("format"),
+		Version:   viper.GetString("version"),
+		Profile:   viper.GetString("profile"),
+		Path:      viper.GetString("path"),
+		Verbose:   viper.GetBool("verbose"),
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	fn "knative.dev/func"
+	"knative.dev/func/knative"
+)
+
+// TestExport_Helm ensures that a function is exported as a Helm chart of
+// the given version in the namespace of the function.
+func TestExport_Helm(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	f := fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}
+	if err := fn.New().Create(f); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Deploy.Namespace = "prod"
+	if err = f.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "chart")
+	cmd := NewExportCmd(NewTestClient(fn.WithDeployer(knative.NewDeployer())))
+	cmd.SetArgs([]string{"--format=helm", "--version=1.2.0", dir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	chart, err := os.ReadFile(filepath.Join(dir, "Chart.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(chart), "version: 1.2.0") {
+		t.Errorf("expected chart version 1.2.0, got:\n%s", chart)
+	}
+	values, err := os.ReadFile(filepath.Join(dir, "values.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`repository: "` + TestRegistry + "/" + f.Name + `"`, `namespace: "prod"`} {
+		if !strings.Contains(string(values), s) {
+			t.Errorf("expected values to contain %q, got:\n%s", s, values)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "templates", "service-"+f.Name+".yaml")); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestExport_InvalidFormat ensures that an unrecognized format is an error.
+func TestExport_InvalidFormat(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	if err := fn.New().Create(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewExportCmd(NewTestClient(fn.WithDeployer(knative.NewDeployer())))
+	cmd.SetArgs([]string{"--format=ksonnet", t.TempDir()})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error with an unrecognized format")
+	}
+}
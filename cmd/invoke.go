@@ -47,10 +47,13 @@ DESCRIPTION
 
 	Invocation Target
 	  The function instance to invoke can be specified using the --target flag
-	  which accepts the values "local", "remote", or <URL>.  By default the
+	  which accepts the values "local", "remote", <TAG> or <URL>.  By default the
 	  local function instance is chosen if running (see {{.Name}} run).
 	  To explicitly target the remote (deployed) function:
 	    {{.Name}} invoke --target=remote
+	  To target a tagged revision of the remote function, such as a canary
+	  deployed with '{{.Name}} deploy --tag canary', provide the tag:
+	    {{.Name}} invoke --target=canary
 	  To target an arbitrary endpoint, provide a URL:
 	    {{.Name}} invoke --target=https://myfunction.example.com
 
@@ -114,7 +117,7 @@ EXAMPLES
 	// Flags
 	setPathFlag(cmd)
 	cmd.Flags().StringP("format", "f", "", "Format of message to send, 'http' or 'cloudevent'.  Default is to choose automatically. (Env: $FUNC_FORMAT)")
-	cmd.Flags().StringP("target", "t", "", "Function instance to invoke.  Can be 'local', 'remote' or a URL.  Defaults to auto-discovery if not provided. (Env: $FUNC_TARGET)")
+	cmd.Flags().StringP("target", "t", "", "Function instance to invoke.  Can be 'local', 'remote', the tag of a deployed revision or a URL.  Defaults to auto-discovery if not provided. (Env: $FUNC_TARGET)")
 	cmd.Flags().StringP("id", "", "", "ID for the request data. (Env: $FUNC_ID)")
 	cmd.Flags().StringP("source", "", fn.DefaultInvokeSource, "Source value for the request data. (Env: $FUNC_SOURCE)")
 	cmd.Flags().StringP("type", "", fn.DefaultInvokeType, "Type value for the request data. (Env: $FUNC_TYPE)")
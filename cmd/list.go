@@ -23,7 +23,8 @@ func NewListCmd(newClient ClientFactory) *cobra.Command {
 		Short: "List functions",
 		Long: `List functions
 
-Lists all deployed functions in a given namespace.
+Lists all deployed functions in a given namespace, and those deployed as local
+containers with '{{.Name}} deploy --target local', which have no namespace.
 `,
 		Example: `
 # List all functions in the current namespace with human readable output
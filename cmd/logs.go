@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+
+	fn "knative.dev/func"
+	"knative.dev/func/config"
+)
+
+func NewLogsCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Print the logs of a function",
+		Long: `Print the logs of a function
+
+Prints the logs of all instances of the function in the current directory or
+from the directory specified with --path.  If the function is running locally
+the logs of its local container are printed.  Otherwise the logs of all pods
+of the current revision of the deployed function are printed.  The instances
+can be explicitly chosen using --target.
+`,
+		Example: `
+# Print the logs of the function in the current directory
+{{.Name}} logs
+
+# Follow the logs of the deployed function, including those of new instances
+{{.Name}} logs --target remote --follow
+
+# Print the last ten minutes of logs of a given revision as JSON
+{{.Name}} logs --since 10m --revision myfunc-00002 --output json
+
+# Print the logs of the queue-proxy container of each pod
+{{.Name}} logs --container queue-proxy
+`,
+		SuggestFor: []string{"log", "lgos"},
+		PreRunE:    bindEnv("path", "namespace", "target", "since", "follow", "revision", "container", "output"),
+	}
+
+	// Config
+	cfg, err := config.NewDefault()
+	if err != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "error loading config at '%v'. %v\n", config.File(), err)
+	}
+
+	// Flags
+	cmd.Flags().StringP("namespace", "n", cfg.Namespace, "The namespace of the deployed function. (Env: $FUNC_NAMESPACE)")
+	cmd.Flags().StringP("target", "t", "", "Function instances whose logs to print.  Can be 'local' or 'remote'.  Defaults to local if running locally, otherwise remote. (Env: $FUNC_TARGET)")
+	cmd.Flags().StringP("since", "", "", "Only print logs newer than a relative duration such as 5m, or a time in RFC3339 format. (Env: $FUNC_SINCE)")
+	cmd.Flags().BoolP("follow", "f", false, "Continue printing logs as they are written, including those of new instances. (Env: $FUNC_FOLLOW)")
+	cmd.Flags().StringP("revision", "r", "", "Revision of the deployed function.  Defaults to the current revision. (Env: $FUNC_REVISION)")
+	cmd.Flags().StringP("container", "c", "", "Container of each instance.  Defaults to the function's own container. (Env: $FUNC_CONTAINER)")
+	cmd.Flags().StringP("output", "o", "human", "Output format (human|plain|json) (Env: $FUNC_OUTPUT)")
+	setPathFlag(cmd)
+
+	if err := cmd.RegisterFlagCompletionFunc("output", CompleteLogsOutputFormatList); err != nil {
+		fmt.Println("internal: error while calling RegisterFlagCompletionFunc: ", err)
+	}
+
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runLogs(cmd, newClient)
+	}
+
+	return cmd
+}
+
+func runLogs(cmd *cobra.Command, newClient ClientFactory) (err error) {
+	cfg, err := newLogsConfig()
+	if err != nil {
+		return
+	}
+
+	f, err := fn.NewFunction(cfg.Path)
+	if err != nil {
+		return
+	}
+	if !f.Initialized() {
+		return fmt.Errorf("the given path '%v' does not contain an initialized function", cfg.Path)
+	}
+	// Unless the namespace flag was explicitly provided, use the function's
+	// current namespace.
+	if !cmd.Flags().Changed("namespace") && f.Deploy.Namespace != "" {
+		cfg.Namespace = f.Deploy.Namespace
+	}
+
+	client, done := newClient(ClientConfig{Namespace: cfg.Namespace, Verbose: cfg.Verbose})
+	defer done()
+
+	var (
+		out = cmd.OutOrStdout()
+		mu  sync.Mutex // entries may be emitted concurrently by instance
+		enc = json.NewEncoder(out)
+	)
+	emit := func(e fn.LogEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch Format(cfg.Output) {
+		case JSON:
+			_ = enc.Encode(e)
+		case Plain:
+			fmt.Fprintln(out, e.Message)
+		default:
+			fmt.Fprintf(out, "[%v] %v\n", e.Instance, e.Message)
+		}
+	}
+
+	return client.Logs(cmd.Context(), f.Root, cfg.LogOptions, emit)
+}
+
+// CLI Configuration (parameters)
+// ------------------------------
+
+type logsConfig struct {
+	fn.LogOptions
+	Namespace string
+	Output    string
+	Path      string
+	Verbose   bool
+}
+
+func newLogsConfig() (c logsConfig, err error) {
+	c = logsConfig{
+		LogOptions: fn.LogOptions{
+			Target:    viper.GetString("target"),
+			Follow:    viper.GetBool("follow"),
+			Revision:  viper.GetString("revision"),
+			Container: viper.GetString("container"),
+		},
+		Namespace: viper.GetString("namespace"),
+		Output:    viper.GetString("output"),
+		Path:      viper.GetString("path"),
+		Verbose:   viper.GetBool("verbose"),
+	}
+	if c.Since, err = parseSince(viper.GetString("since"), time.Now()); err != nil {
+		return
+	}
+	switch Format(c.Output) {
+	case Human, Plain, JSON:
+	default:
+		err = fmt.Errorf("format not recognized: %v", c.Output)
+	}
+	return
+}
+
+// parseSince returns the time denoted by the value of the --since flag, which
+// is either a duration prior to now or an absolute RFC3339 timestamp.  An
+// empty value is the zero time.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: expected a duration such as 5m or an RFC3339 time", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	fn "knative.dev/func"
+	"knative.dev/func/mock"
+)
+
+// TestLogs_Options ensures that the flags of the logs command are passed to
+// the logger, and that entries are written in the requested output format.
+func TestLogs_Options(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	err := fn.New().Create(fn.Function{
+		Name:     "testname",
+		Runtime:  "go",
+		Registry: TestRegistry,
+		Root:     root,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := mock.NewLogger()
+	logger.LogsFn = func(f fn.Function, opts fn.LogOptions, emit func(fn.LogEntry)) error {
+		if f.Name != "testname" {
+			t.Errorf("expected logs of 'testname', got '%v'", f.Name)
+		}
+		if opts.Target != fn.EnvironmentRemote || !opts.Follow ||
+			opts.Revision != "testname-00002" || opts.Container != "queue-proxy" {
+			t.Errorf("unexpected log options %+v", opts)
+		}
+		if since := time.Since(opts.Since); since < 10*time.Minute || since > 11*time.Minute {
+			t.Errorf("expected logs since 10m ago, got %v", opts.Since)
+		}
+		emit(fn.LogEntry{Instance: "testname-00002-pod", Message: "hello"})
+		return nil
+	}
+
+	var out bytes.Buffer
+	cmd := NewLogsCmd(NewTestClient(fn.WithLogger(logger)))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--target=remote", "--follow", "--since=10m",
+		"--revision=testname-00002", "--container=queue-proxy", "--output=json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !logger.LogsInvoked {
+		t.Fatal("logger not invoked")
+	}
+
+	var entry fn.LogEntry
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a JSON log entry, got %q: %v", out.String(), err)
+	}
+	if entry.Instance != "testname-00002-pod" || entry.Message != "hello" {
+		t.Fatalf("unexpected log entry %+v", entry)
+	}
+}
+
+// TestLogs_Since ensures that --since accepts both a relative duration and
+// an absolute time.
+func TestLogs_Since(t *testing.T) {
+	now := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := map[string]time.Time{
+		"":                     {},
+		"90s":                  now.Add(-90 * time.Second),
+		"2h":                   now.Add(-2 * time.Hour),
+		"2023-01-01T12:00:00Z": time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	for value, expected := range tests {
+		since, err := parseSince(value, now)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", value, err)
+		}
+		if !since.Equal(expected) {
+			t.Errorf("expected %q to be %v, got %v", value, expected, since)
+		}
+	}
+	if _, err := parseSince("yesterday", now); err == nil {
+		t.Error("expected an error for an invalid --since")
+	}
+}
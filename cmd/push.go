@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+
+	fn "knative.dev/func"
+	"knative.dev/func/config"
+)
+
+func NewPushCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "push",
+		Short: "Push the image of a function to its registry",
+		Long: `Push the image of a function to its registry
+
+Pushes the image most recently built for the function in the current directory
+or from the directory specified with --path, and records its digest for use
+by deploy.
+
+With --from, the image saved by '{{.Name}} build --output' to an OCI image
+layout or archive is pushed instead, such as one built elsewhere and handed
+off without a registry.  The source is given as oci-layout:<dir> or
+oci-archive:<file.tar>, or as a path: a directory is read as a layout and a
+file as an archive.  The image is pushed under the function's image name with
+its digest unchanged, using the usual registry credentials.
+`,
+		Example: `
+# Push the image most recently built
+{{.Name}} push
+
+# Push an image saved with '{{.Name}} build --output oci-archive:f.tar'
+{{.Name}} push --from oci-archive:f.tar --registry registry.example.com/alice
+`,
+		SuggestFor: []string{"psuh", "publish", "upload"},
+		PreRunE:    bindEnv("path", "registry", "from"),
+	}
+
+	// Config
+	cfg, err := config.NewDefault()
+	if err != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "error loading config at '%v'. %v\n", config.File(), err)
+	}
+
+	// Flags
+	cmd.Flags().StringP("registry", "r", cfg.Registry,
+		"Registry + namespace part of the image, ex 'quay.io/myuser'.  The full image name is automatically determined (Env: $FUNC_REGISTRY)")
+	cmd.Flags().StringP("from", "", "",
+		"OCI image layout or archive from which to push the image, as oci-layout:<dir>, oci-archive:<file.tar> or a path (Env: $FUNC_FROM)")
+	setPathFlag(cmd)
+
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runPush(cmd, newClient)
+	}
+
+	return cmd
+}
+
+func runPush(cmd *cobra.Command, newClient ClientFactory) (err error) {
+	if err = config.CreatePaths(); err != nil {
+		return // see docker/creds potential mutation of auth.json
+	}
+
+	cfg := newPushConfig()
+
+	f, err := fn.NewFunction(cfg.Path)
+	if err != nil {
+		return
+	}
+	if !f.Initialized() {
+		return fmt.Errorf("the given path '%v' does not contain an initialized function", cfg.Path)
+	}
+
+	client, done := newClient(ClientConfig{Verbose: cfg.Verbose},
+		fn.WithRegistry(cfg.Registry))
+	defer done()
+
+	if cfg.From != "" {
+		if err = client.Load(cmd.Context(), f.Root, cfg.From); err != nil {
+			return
+		}
+	}
+	if err = client.Push(cmd.Context(), f.Root); err != nil {
+		return
+	}
+
+	if f, err = fn.NewFunction(cfg.Path); err != nil {
+		return
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Function image pushed: %v@%v\n", f.Built.Image, f.Built.Digest)
+	return
+}
+
+// CLI Configuration (parameters)
+// ------------------------------
+
+type pushConfig struct {
+	Registry string
+	From     string
+	Path     string
+	Verbose  bool
+}
+
+func newPushConfig() pushConfig {
+	return pushConfig{
+		Registry: registry(), // deferred defaulting
+		From:     viper.GetString("from"),
+		Path:     viper.GetString("path"),
+		Verbose:  viper.GetBool("verbose"),
+	}
+}
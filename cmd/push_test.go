@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	fn "knative.dev/func"
+	"knative.dev/func/mock"
+)
+
+// TestPush_From ensures that the image loaded from the layout or archive
+// given with --from is pushed as the function's image, and its digest
+// recorded.
+func TestPush_From(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	err := fn.New().Create(fn.Function{
+		Name:     "testname",
+		Runtime:  "go",
+		Registry: TestRegistry,
+		Root:     root,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedImage := TestRegistry + "/testname:latest"
+	archiver := mock.NewArchiver()
+	archiver.LoadFn = func(f fn.Function, from string) (string, error) {
+		if f.Image != expectedImage || from != "oci-archive:f.tar" {
+			t.Errorf("unexpected load of '%v' from '%v'", f.Image, from)
+		}
+		return "sha256:1", nil
+	}
+	pusher := mock.NewPusher()
+	pusher.PushFn = func(f fn.Function) (string, error) {
+		if f.Image != expectedImage {
+			t.Errorf("unexpected push of '%v'", f.Image)
+		}
+		return "sha256:1", nil
+	}
+
+	var out bytes.Buffer
+	cmd := NewPushCmd(NewTestClient(fn.WithArchiver(archiver), fn.WithPusher(pusher)))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--from=oci-archive:f.tar"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !archiver.LoadInvoked || !pusher.PushInvoked {
+		t.Fatal("expected the image to be loaded and pushed")
+	}
+	if !strings.Contains(out.String(), expectedImage+"@sha256:1") {
+		t.Fatalf("expected the image pushed to be reported, got %q", out.String())
+	}
+
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Built.Image != expectedImage || f.Built.Digest != "sha256:1" {
+		t.Fatalf("expected the image pushed to be recorded, got %+v", f.Built)
+	}
+}
+
+// TestPush_NotBuilt ensures that pushing a function which has neither been
+// built nor given an image with --from fails.
+func TestPush_NotBuilt(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	if err := fn.New().Create(fn.Function{Runtime: "go", Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	pusher := mock.NewPusher()
+	cmd := NewPushCmd(NewTestClient(fn.WithPusher(pusher)))
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); !errors.Is(err, fn.ErrNotBuilt) {
+		t.Fatalf("expected ErrNotBuilt, got %v", err)
+	}
+	if pusher.PushInvoked {
+		t.Fatal("expected an unbuilt function not to be pushed")
+	}
+}
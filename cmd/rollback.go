@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	fn "knative.dev/func"
+	"knative.dev/func/config"
+)
+
+func NewRollbackCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Roll back a function to a previous revision",
+		Long: `Roll back a function to a previous revision
+
+Routes all traffic of the deployed function in the current directory or from
+the directory specified with --path to a previous revision, and records the
+image of that revision as the function's deployed image.  By default the
+revision deployed prior to the one currently serving is used.  Subsequent
+revisions receive no traffic until the function is next deployed.
+
+Use --list to print the revisions of the function, including the image, time
+of deployment and git commit (when known) of each.
+`,
+		Example: `
+# List the revisions of the function in the current directory
+{{.Name}} rollback --list
+
+# Roll back to the previous revision
+{{.Name}} rollback
+
+# Roll back to a specific revision
+{{.Name}} rollback --to myfunc-00002
+`,
+		SuggestFor: []string{"rolback", "revert", "undo"},
+		PreRunE:    bindEnv("path", "namespace", "to", "list", "output"),
+	}
+
+	// Config
+	cfg, err := config.NewDefault()
+	if err != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "error loading config at '%v'. %v\n", config.File(), err)
+	}
+
+	// Flags
+	cmd.Flags().StringP("namespace", "n", cfg.Namespace, "The namespace of the deployed function. (Env: $FUNC_NAMESPACE)")
+	cmd.Flags().StringP("to", "", "", "Revision to which to roll back.  Defaults to the revision prior to the current revision. (Env: $FUNC_TO)")
+	cmd.Flags().BoolP("list", "l", false, "List the revisions of the function rather than rolling back. (Env: $FUNC_LIST)")
+	cmd.Flags().StringP("output", "o", "human", "Output format of the list of revisions (human|plain|json|xml|yaml) (Env: $FUNC_OUTPUT)")
+	setPathFlag(cmd)
+
+	if err := cmd.RegisterFlagCompletionFunc("output", CompleteOutputFormatList); err != nil {
+		fmt.Println("internal: error while calling RegisterFlagCompletionFunc: ", err)
+	}
+
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runRollback(cmd, newClient)
+	}
+
+	return cmd
+}
+
+func runRollback(cmd *cobra.Command, newClient ClientFactory) (err error) {
+	cfg := newRollbackConfig()
+	if cfg.List && cfg.To != "" {
+		return fmt.Errorf("only one of --list and --to may be specified")
+	}
+	if Format(cfg.Output) == URL {
+		return fmt.Errorf("format not recognized: %v", cfg.Output)
+	}
+
+	f, err := fn.NewFunction(cfg.Path)
+	if err != nil {
+		return
+	}
+	if !f.Initialized() {
+		return fmt.Errorf("the given path '%v' does not contain an initialized function", cfg.Path)
+	}
+	// Unless the namespace flag was explicitly provided, use the function's
+	// current namespace.
+	if !cmd.Flags().Changed("namespace") && f.Deploy.Namespace != "" {
+		cfg.Namespace = f.Deploy.Namespace
+	}
+
+	client, done := newClient(ClientConfig{Namespace: cfg.Namespace, Verbose: cfg.Verbose})
+	defer done()
+
+	if cfg.List {
+		revisions, err := client.Revisions(cmd.Context(), f.Root)
+		if err != nil {
+			return err
+		}
+		if len(revisions) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "no revisions found")
+			return nil
+		}
+		write(cmd.OutOrStdout(), revisionItems(revisions), cfg.Output)
+		return nil
+	}
+
+	r, err := client.Rollback(cmd.Context(), f.Root, cfg.To)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Function rolled back to revision %v\n   %v\n", r.Name, r.Image)
+	return
+}
+
+// CLI Configuration (parameters)
+// ------------------------------
+
+type rollbackConfig struct {
+	Namespace string
+	To        string
+	List      bool
+	Output    string
+	Path      string
+	Verbose   bool
+}
+
+func newRollbackConfig() rollbackConfig {
+	return rollbackConfig{
+		Namespace: viper.GetString("namespace"),
+		To:        viper.GetString("to"),
+		List:      viper.GetBool("list"),
+		Output:    viper.GetString("output"),
+		Path:      viper.GetString("path"),
+		Verbose:   viper.GetBool("verbose"),
+	}
+}
+
+// Output Formatting (serializers)
+// -------------------------------
+
+type revisionItems []fn.Revision
+
+func (items revisionItems) Human(w io.Writer) error {
+	tabWriter := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	defer tabWriter.Flush()
+
+	fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\t%s\n", "REVISION", "TRAFFIC", "DEPLOYED", "COMMIT", "IMAGE")
+	for _, r := range items {
+		commit := r.GitCommit
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		fmt.Fprintf(tabWriter, "%s\t%d%%\t%s\t%s\t%s\n", r.Name, r.Traffic, r.Created.Local().Format(time.RFC3339), commit, r.Image)
+	}
+	return nil
+}
+
+func (items revisionItems) Plain(w io.Writer) error {
+	for _, r := range items {
+		commit := r.GitCommit
+		if commit == "" {
+			commit = "-"
+		}
+		fmt.Fprintf(w, "%s %d %s %s %s\n", r.Name, r.Traffic, r.Created.Format(time.RFC3339), commit, r.Image)
+	}
+	return nil
+}
+
+func (items revisionItems) JSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(items)
+}
+
+func (items revisionItems) XML(w io.Writer) error {
+	return xml.NewEncoder(w).Encode(items)
+}
+
+func (items revisionItems) YAML(w io.Writer) error {
+	return yaml.NewEncoder(w).Encode(items)
+}
+
+func (items revisionItems) URL(w io.Writer) error {
+	return fmt.Errorf("revisions have no URL")
+}
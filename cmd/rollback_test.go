@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	fn "knative.dev/func"
+	"knative.dev/func/mock"
+)
+
+// TestRollback_To ensures that the revision given with --to is passed to the
+// rollbacker, and the image of the revision recorded.
+func TestRollback_To(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	err := fn.New().Create(fn.Function{
+		Name:     "testname",
+		Runtime:  "go",
+		Registry: TestRegistry,
+		Root:     root,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rollbacker := mock.NewRollbacker()
+	rollbacker.RollbackFn = func(name, revision string) (fn.Revision, error) {
+		if name != "testname" || revision != "testname-00001" {
+			t.Errorf("unexpected rollback of '%v' to '%v'", name, revision)
+		}
+		return fn.Revision{Name: revision, Image: "example.com/alice/testname@sha256:1"}, nil
+	}
+
+	var out bytes.Buffer
+	cmd := NewRollbackCmd(NewTestClient(fn.WithRollbacker(rollbacker)))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--to=testname-00001"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !rollbacker.RollbackInvoked {
+		t.Fatal("rollbacker not invoked")
+	}
+	if !strings.Contains(out.String(), "testname-00001") {
+		t.Fatalf("expected the revision to be reported, got %q", out.String())
+	}
+
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.Image != "example.com/alice/testname@sha256:1" {
+		t.Fatalf("expected the revision's image to be recorded, got '%v'", f.Deploy.Image)
+	}
+}
+
+// TestRollback_List ensures that --list prints the revisions of the function
+// without rolling back.
+func TestRollback_List(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	err := fn.New().Create(fn.Function{
+		Name:     "testname",
+		Runtime:  "go",
+		Registry: TestRegistry,
+		Root:     root,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rollbacker := mock.NewRollbacker()
+	rollbacker.RevisionsFn = func(string) ([]fn.Revision, error) {
+		return []fn.Revision{
+			{Name: "testname-00002", GitCommit: "c2", Traffic: 100},
+			{Name: "testname-00001", GitCommit: "c1"},
+		}, nil
+	}
+
+	var out bytes.Buffer
+	cmd := NewRollbackCmd(NewTestClient(fn.WithRollbacker(rollbacker)))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--list", "--output=json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if rollbacker.RollbackInvoked {
+		t.Fatal("rolled back when listing")
+	}
+
+	var revisions []fn.Revision
+	if err := json.Unmarshal(out.Bytes(), &revisions); err != nil {
+		t.Fatalf("expected JSON revisions, got %q: %v", out.String(), err)
+	}
+	if len(revisions) != 2 || revisions[1].GitCommit != "c1" {
+		t.Fatalf("unexpected revisions %+v", revisions)
+	}
+
+	// --list and --to are mutually exclusive
+	cmd.SetArgs([]string{"--list", "--to=testname-00001"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error with both --list and --to")
+	}
+}
@@ -102,12 +102,17 @@ EXAMPLES
 				NewDeleteCmd(newClient),
 				NewDeployCmd(newClient),
 				NewDescribeCmd(newClient),
+				NewExportCmd(newClient),
 				NewInvokeCmd(newClient),
 				NewLanguagesCmd(newClient),
 				NewListCmd(newClient),
+				NewLogsCmd(newClient),
+				NewPushCmd(newClient),
 				NewRepositoryCmd(newClient),
+				NewRollbackCmd(newClient),
 				NewRunCmd(newClient),
 				NewTemplatesCmd(newClient),
+				NewTrafficCmd(newClient),
 			},
 		},
 		{
@@ -26,6 +26,20 @@ Building
 By default the function will be built if never built, or if changes are detected
 to the function's source.  Use --build to override this behavior.
 
+Watching
+Use --watch to rebuild and restart the function on the same port whenever its
+source changes.  Paths listed in .funcignore are not watched.  Should a
+rebuild fail, the error is printed and the previous build continues to run.
+
+Timeouts
+When a request timeout is set in the function's options.timeouts.request,
+requests which do not complete within it fail with 504 Gateway Timeout, as
+they would when deployed.
+
+Sidecars
+The function's sidecars, in deploy.sidecars, are started next to it and share
+its network, such that they are reachable on localhost as when deployed.
+
 `,
 		Example: `
 # Run the function locally, building if necessary
@@ -41,9 +55,12 @@ to the function's source.  Use --build to override this behavior.
 #   run the previously built image without rebuilding.
 {{.Name}} run --build=false
 
+# Run the function, rebuilding and restarting it as its source changes.
+{{.Name}} run --watch
+
 `,
 		SuggestFor: []string{"rnu"},
-		PreRunE:    bindEnv("build", "path", "registry"),
+		PreRunE:    bindEnv("build", "path", "registry", "watch"),
 	}
 
 	cmd.Flags().StringArrayP("env", "e", []string{},
@@ -53,6 +70,7 @@ to the function's source.  Use --build to override this behavior.
 	cmd.Flags().StringP("build", "b", "auto", "Build the function. [auto|true|false].")
 	cmd.Flags().Lookup("build").NoOptDefVal = "true" // --build is equivalient to --build=true
 	cmd.Flags().StringP("registry", "r", "", "Registry + namespace part of the image if building, ex 'quay.io/myuser' (Env: $FUNC_REGISTRY)")
+	cmd.Flags().BoolP("watch", "w", false, "Rebuild and restart the function when its source changes. (Env: $FUNC_WATCH)")
 	setPathFlag(cmd)
 
 	cmd.SetHelpFunc(defaultTemplatedHelp)
@@ -128,19 +146,69 @@ func runRun(cmd *cobra.Command, args []string, newClient ClientFactory) (err err
 	if err != nil {
 		return
 	}
-	defer job.Stop()
+	defer func() { // job is replaced on each restart when watching
+		if job != nil {
+			job.Stop()
+		}
+	}()
 
 	fmt.Fprintf(cmd.OutOrStderr(), "Function started on port %v\n", job.Port)
 
-	select {
-	case <-cmd.Context().Done():
-		if !errors.Is(cmd.Context().Err(), context.Canceled) {
-			err = cmd.Context().Err()
+	// Changes to the function's source, which are only received when watching.
+	var changes <-chan struct{}
+	var watchErrs <-chan error
+	if cfg.Watch {
+		if changes, watchErrs, err = function.Watch(cmd.Context(), fn.DefaultWatchDebounce); err != nil {
+			return
 		}
-		return
-	case err = <-job.Errors:
-		return
+		fmt.Fprintln(cmd.OutOrStderr(), "Watching for changes")
 	}
+
+	for {
+		select {
+		case <-cmd.Context().Done():
+			if !errors.Is(cmd.Context().Err(), context.Canceled) {
+				err = cmd.Context().Err()
+			}
+			return
+		case err = <-job.Errors:
+			return
+		case err = <-watchErrs:
+			if errors.Is(err, fn.ErrWatchStopped) {
+				return
+			}
+			// Transient, such as a directory which could not be watched.
+			fmt.Fprintf(cmd.OutOrStderr(), "Error watching for changes: %v\n", err)
+			err = nil
+		case <-changes:
+			if job, err = restart(cmd, client, function.Root, job); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// restart the function if its source has changed since it was last built,
+// returning the job of the new instance, which is run on the same port as the
+// previous.  Should the build fail, the error is printed and the previous
+// instance continues to run.
+func restart(cmd *cobra.Command, client *fn.Client, root string, job *fn.Job) (*fn.Job, error) {
+	if client.Built(root) {
+		return job, nil // for example a file was saved without modification
+	}
+	fmt.Fprintln(cmd.OutOrStderr(), "Changes detected.  Rebuilding")
+	if err := client.Build(cmd.Context(), root); err != nil {
+		fmt.Fprintf(cmd.OutOrStderr(), "Build failed.  Continuing to run the previous build. %v\n", err)
+		return job, nil
+	}
+	port := job.Port
+	job.Stop()
+	job, err := client.Run(cmd.Context(), root, fn.RunWithPort(port))
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(cmd.OutOrStderr(), "Function restarted on port %v\n", job.Port)
+	return job, nil
 }
 
 type runConfig struct {
@@ -163,6 +231,9 @@ type runConfig struct {
 
 	// Registry for the build tag if building
 	Registry string
+
+	// Watch the function's source, rebuilding and restarting on change.
+	Watch bool
 }
 
 func newRunConfig(cmd *cobra.Command) (cfg runConfig, err error) {
@@ -175,6 +246,7 @@ func newRunConfig(cmd *cobra.Command) (cfg runConfig, err error) {
 		Path:        viper.GetString("path"),
 		Verbose:     viper.GetBool("verbose"), // defined on root
 		Registry:    viper.GetString("registry"),
+		Watch:       viper.GetBool("watch"),
 		EnvToUpdate: envToUpdate,
 		EnvToRemove: envToRemove,
 	}
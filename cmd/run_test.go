@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	fn "knative.dev/func"
+	"knative.dev/func/knative"
 	"knative.dev/func/mock"
 )
 
@@ -164,3 +168,89 @@ created: 2009-11-10 23:00:00`,
 		})
 	}
 }
+
+// TestRun_Watch ensures that when watching, a change to the function's source
+// rebuilds the function and restarts it on the same port, and that a failed
+// rebuild leaves the previous instance running.
+func TestRun_Watch(t *testing.T) {
+	root := fromTempDirectory(t)
+	if err := fn.New().Create(fn.Function{Root: root, Runtime: "go", Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The initial build succeeds, the first rebuild fails and the second succeeds.
+	builds := make(chan error, 3)
+	builds <- nil
+	builds <- fmt.Errorf("generic build error")
+	builds <- nil
+	builder := mock.NewBuilder()
+	builder.BuildFn = func(fn.Function) error { return <-builds }
+
+	ports := make(chan string, 3)
+	runner := mock.NewRunner()
+	runner.RunFn = func(_ context.Context, f fn.Function) (*fn.Job, error) {
+		ports <- runner.PortRequested // runner is locked while running
+		return fn.NewJob(f, "8080", make(chan error), func() {})
+	}
+
+	var out knative.SynchronizedBuffer
+	cmd := NewRunCmd(NewTestClient(fn.WithRunner(runner), fn.WithBuilder(builder)))
+	cmd.SetArgs([]string{"--watch"})
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() {
+		_, err := cmd.ExecuteContextC(ctx)
+		errs <- err
+	}()
+
+	awaitOutput := func(s string) {
+		t.Helper()
+		for i := 0; !strings.Contains(out.String(), s); i++ {
+			if i > 100 {
+				t.Fatalf("expected output %q, got %q", s, out.String())
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	awaitPort := func(expected string) {
+		t.Helper()
+		select {
+		case port := <-ports:
+			if port != expected {
+				t.Fatalf("expected port %q requested, got %q", expected, port)
+			}
+		case err := <-errs:
+			t.Fatalf("run returned early: %v", err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout awaiting run")
+		}
+	}
+	awaitPort("") // initial run chooses any port
+	awaitOutput("Watching for changes")
+
+	// A failed rebuild does not restart
+	if err := os.WriteFile(filepath.Join(root, "handle.go"), []byte("package function // 1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	awaitOutput("Build failed")
+	select {
+	case <-ports:
+		t.Fatal("unexpected restart after a failed build")
+	default:
+	}
+
+	// A successful rebuild restarts on the same port
+	if err := os.WriteFile(filepath.Join(root, "handle.go"), []byte("package function // 2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	awaitPort("8080")
+
+	cancel()
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
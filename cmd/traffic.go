@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	fn "knative.dev/func"
+	"knative.dev/func/config"
+)
+
+func NewTrafficCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "traffic",
+		Short: "Manage the traffic split of a function among its revisions",
+		Long: `Manage the traffic split of a function among its revisions
+
+Prints how the traffic of the deployed function in the current directory or
+from the directory specified with --path is split among its revisions,
+including the URL of each tagged revision.
+
+A share of traffic can be routed to a new revision when deploying, such as a
+canary (see '{{.Name}} deploy --tag --traffic').  The split can then be
+shifted step by step using '{{.Name}} traffic promote', or set explicitly using
+'{{.Name}} traffic set'.
+`,
+		Example: `
+# Print the traffic split of the function in the current directory
+{{.Name}} traffic
+
+# Deploy a canary receiving 10% of traffic, then shift traffic to it 30% at
+# a time until it receives all traffic
+{{.Name}} deploy --tag canary --traffic 10
+{{.Name}} traffic promote canary --step 30
+
+# Split traffic evenly between the canary and the revision myfunc-00001
+{{.Name}} traffic set canary=50 myfunc-00001=50
+`,
+		SuggestFor: []string{"trafic", "split"},
+		PreRunE:    bindEnv("path", "namespace", "output"),
+	}
+
+	addTrafficFlags(cmd)
+	cmd.Flags().StringP("output", "o", "human", "Output format (human|plain|json|xml|yaml) (Env: $FUNC_OUTPUT)")
+	if err := cmd.RegisterFlagCompletionFunc("output", CompleteOutputFormatList); err != nil {
+		fmt.Println("internal: error while calling RegisterFlagCompletionFunc: ", err)
+	}
+
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runTraffic(cmd, newClient)
+	}
+
+	cmd.AddCommand(NewTrafficPromoteCmd(newClient))
+	cmd.AddCommand(NewTrafficSetCmd(newClient))
+
+	return cmd
+}
+
+func NewTrafficPromoteCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Short: "Shift traffic to a revision",
+		Use:   "promote <tag|revision>",
+		Long: `Shift traffic to a revision
+
+Increases the share of traffic routed to the revision with the given tag or
+name by --step percent, decreasing the shares of the other revisions in
+proportion.  By default all traffic is routed to the revision.
+`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: bindEnv("path", "namespace", "step"),
+	}
+
+	addTrafficFlags(cmd)
+	cmd.Flags().Int64P("step", "s", 0, "Percent by which to increase the revision's share of traffic.  Defaults to all traffic. (Env: $FUNC_STEP)")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runTrafficPromote(cmd, args, newClient)
+	}
+
+	return cmd
+}
+
+func NewTrafficSetCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Short: "Set the traffic split among revisions",
+		Use:   "set <tag|revision>=<percent> ...",
+		Long: `Set the traffic split among revisions
+
+Routes the given percentages of traffic to the revisions with the given tags
+or names.  The percentages must total 100.  Tagged revisions which are not
+given retain their tags but receive no traffic.
+`,
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: bindEnv("path", "namespace"),
+	}
+
+	addTrafficFlags(cmd)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runTrafficSet(cmd, args, newClient)
+	}
+
+	return cmd
+}
+
+// addTrafficFlags adds the flags common to the traffic commands.
+func addTrafficFlags(cmd *cobra.Command) {
+	cfg, err := config.NewDefault()
+	if err != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "error loading config at '%v'. %v\n", config.File(), err)
+	}
+	cmd.Flags().StringP("namespace", "n", cfg.Namespace, "The namespace of the deployed function. (Env: $FUNC_NAMESPACE)")
+	setPathFlag(cmd)
+}
+
+func runTraffic(cmd *cobra.Command, newClient ClientFactory) (err error) {
+	client, done, root, err := newTrafficClient(cmd, newClient)
+	if err != nil {
+		return
+	}
+	defer done()
+
+	output := viper.GetString("output")
+	if Format(output) == URL {
+		return fmt.Errorf("format not recognized: %v", output)
+	}
+	targets, err := client.Traffic(cmd.Context(), root)
+	if err != nil {
+		return
+	}
+	write(cmd.OutOrStdout(), trafficTargets(targets), output)
+	return
+}
+
+func runTrafficPromote(cmd *cobra.Command, args []string, newClient ClientFactory) (err error) {
+	client, done, root, err := newTrafficClient(cmd, newClient)
+	if err != nil {
+		return
+	}
+	defer done()
+
+	targets, err := client.Promote(cmd.Context(), root, args[0], viper.GetInt64("step"))
+	if err != nil {
+		return
+	}
+	return trafficTargets(targets).Human(cmd.OutOrStdout())
+}
+
+func runTrafficSet(cmd *cobra.Command, args []string, newClient ClientFactory) (err error) {
+	percents, err := parseTrafficSplit(args)
+	if err != nil {
+		return
+	}
+	client, done, root, err := newTrafficClient(cmd, newClient)
+	if err != nil {
+		return
+	}
+	defer done()
+
+	targets, err := client.SetTraffic(cmd.Context(), root, percents)
+	if err != nil {
+		return
+	}
+	return trafficTargets(targets).Human(cmd.OutOrStdout())
+}
+
+// newTrafficClient returns a client for the function at --path in the
+// namespace of --namespace, defaulting to that of the function.
+func newTrafficClient(cmd *cobra.Command, newClient ClientFactory) (client *fn.Client, done func(), root string, err error) {
+	f, err := fn.NewFunction(viper.GetString("path"))
+	if err != nil {
+		return
+	}
+	if !f.Initialized() {
+		err = fmt.Errorf("the given path '%v' does not contain an initialized function", f.Root)
+		return
+	}
+	namespace := viper.GetString("namespace")
+	if !cmd.Flags().Changed("namespace") && f.Deploy.Namespace != "" {
+		namespace = f.Deploy.Namespace
+	}
+	client, done = newClient(ClientConfig{Namespace: namespace, Verbose: viper.GetBool("verbose")})
+	return client, done, f.Root, nil
+}
+
+// parseTrafficSplit parses arguments of the form <tag|revision>=<percent>.
+func parseTrafficSplit(args []string) (map[string]int64, error) {
+	percents := make(map[string]int64, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid traffic target '%v', expected <tag|revision>=<percent>", arg)
+		}
+		p, err := strconv.ParseInt(strings.TrimSuffix(parts[1], "%"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid traffic percent '%v' of %v", parts[1], parts[0])
+		}
+		if _, ok := percents[parts[0]]; ok {
+			return nil, fmt.Errorf("traffic target %v given more than once", parts[0])
+		}
+		percents[parts[0]] = p
+	}
+	return percents, nil
+}
+
+// Output Formatting (serializers)
+// -------------------------------
+
+type trafficTargets []fn.TrafficTarget
+
+func (targets trafficTargets) Human(w io.Writer) error {
+	tabWriter := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	defer tabWriter.Flush()
+
+	fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n", "REVISION", "TAG", "TRAFFIC", "URL")
+	for _, t := range targets {
+		revision := t.Revision
+		if t.Latest {
+			revision = fmt.Sprintf("@latest (%v)", t.Revision)
+		}
+		fmt.Fprintf(tabWriter, "%s\t%s\t%d%%\t%s\n", revision, t.Tag, t.Percent, t.URL)
+	}
+	return nil
+}
+
+func (targets trafficTargets) Plain(w io.Writer) error {
+	for _, t := range targets {
+		tag := t.Tag
+		if tag == "" {
+			tag = "-"
+		}
+		fmt.Fprintf(w, "%s %s %d %s\n", t.Revision, tag, t.Percent, t.URL)
+	}
+	return nil
+}
+
+func (targets trafficTargets) JSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(targets)
+}
+
+func (targets trafficTargets) XML(w io.Writer) error {
+	return xml.NewEncoder(w).Encode(targets)
+}
+
+func (targets trafficTargets) YAML(w io.Writer) error {
+	return yaml.NewEncoder(w).Encode(targets)
+}
+
+func (targets trafficTargets) URL(w io.Writer) error {
+	return fmt.Errorf("traffic targets have no single URL")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	fn "knative.dev/func"
+	"knative.dev/func/mock"
+)
+
+// TestTraffic_Promote ensures that promoting a tagged revision shifts the
+// given step of traffic to it.
+func TestTraffic_Promote(t *testing.T) {
+	root := fromTempDirectory(t)
+
+	err := fn.New().Create(fn.Function{
+		Name:     "testname",
+		Runtime:  "go",
+		Registry: TestRegistry,
+		Root:     root,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	describer := mock.NewDescriber()
+	describer.DescribeFn = func(string) (fn.Instance, error) {
+		return fn.Instance{Traffic: []fn.TrafficTarget{
+			{Revision: "testname-00002", Latest: true, Tag: "canary", Percent: 10, URL: "http://canary-testname.example.com"},
+			{Revision: "testname-00001", Percent: 90},
+		}}, nil
+	}
+	splitter := mock.NewTrafficSplitter()
+	splitter.SplitFn = func(name string, targets []fn.TrafficTarget) error {
+		if name != "testname" {
+			t.Errorf("expected traffic of 'testname', got '%v'", name)
+		}
+		if len(targets) != 2 || targets[0].Tag != "canary" || targets[0].Percent != 40 || targets[1].Percent != 60 {
+			t.Errorf("expected 40%% promoted to the canary, got %+v", targets)
+		}
+		return nil
+	}
+
+	var out bytes.Buffer
+	cmd := NewTrafficCmd(NewTestClient(fn.WithDescriber(describer), fn.WithTrafficSplitter(splitter)))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"promote", "canary", "--step=30"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !splitter.SplitInvoked {
+		t.Fatal("traffic splitter not invoked")
+	}
+	if !strings.Contains(out.String(), "40%") {
+		t.Fatalf("expected the resultant split to be printed, got %q", out.String())
+	}
+}
+
+// Test_parseTrafficSplit ensures that arguments of traffic set are parsed.
+func Test_parseTrafficSplit(t *testing.T) {
+	percents, err := parseTrafficSplit([]string{"canary=25", "myfunc-00001=75%"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if percents["canary"] != 25 || percents["myfunc-00001"] != 75 {
+		t.Fatalf("unexpected split %v", percents)
+	}
+	for _, args := range [][]string{{"canary"}, {"=10"}, {"canary=x"}, {"canary=10", "canary=90"}} {
+		if _, err := parseTrafficSplit(args); err == nil {
+			t.Errorf("expected an error parsing %v", args)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s/labels"
+)
+
+const (
+	// DefaultDeployTimeout for a deployed function's container to accept
+	// requests.
+	DefaultDeployTimeout = 60 * time.Second
+
+	// deployerName is the value of the deployer label of the containers of
+	// functions deployed by the Deployer.
+	deployerName = "docker"
+
+	// portLabel of the containers of deployed functions, whose value is the
+	// host port on which the function is reachable.  A function keeps its
+	// port when redeployed.
+	portLabel = "function.knative.dev/port"
+
+	// restartPolicy of the containers of deployed functions, which are
+	// restarted if they exit and when the daemon restarts until removed.
+	restartPolicy = "unless-stopped"
+)
+
+// Deployer of functions as long-lived local containers, for use without a
+// cluster.  Each function is a single detached container which is restarted
+// unless removed, reachable on a host port which is kept across deploys.
+type Deployer struct {
+	verbose bool
+}
+
+// NewDeployer creates a deployer of functions as local containers.
+func NewDeployer(verbose bool) *Deployer {
+	return &Deployer{verbose: verbose}
+}
+
+// Deploy the function as a local container, replacing the container of a
+// previous deployment.  The new container is created under a temporary name
+// and the previous stopped only to release its port, such that should the new
+// container fail to start or accept requests, it is removed and the previous
+// restarted.  Environment variables referencing Secrets or ConfigMaps, and
+// volumes, are not supported, as they exist only in a cluster.
+func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (result fn.DeploymentResult, err error) {
+	image := f.ImageWithDigest()
+	if image == "" {
+		return result, errors.New("Function has no associated image. Has it been built?")
+	}
+	c, _, err := NewClient(client.DefaultDockerHost)
+	if err != nil {
+		return result, errors.Wrap(err, "failed to create Docker API client")
+	}
+	defer c.Close()
+
+	// The port and containers of a previous deployment, which are replaced.
+	// The container of an interrupted deploy is removed outright.
+	existing, err := listDeployed(ctx, c, f.Name)
+	if err != nil {
+		return
+	}
+	var previous []types.Container
+	status, port := fn.Deployed, ""
+	for _, ctr := range existing {
+		if hasName(ctr, nextContainerName(f.Name)) {
+			if err = c.ContainerRemove(ctx, ctr.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+				return result, errors.Wrap(err, "deployer unable to remove the container of an interrupted deploy")
+			}
+			continue
+		}
+		previous = append(previous, ctr)
+		status, port = fn.Updated, ctr.Labels[portLabel]
+	}
+	if port == "" {
+		port = choosePort(DefaultHost, DefaultPort, DefaultDialTimeout)
+	}
+
+	containerCfg, err := newContainerConfig(f, port, d.verbose)
+	if err != nil {
+		return
+	}
+	containerCfg.Image = image
+	containerCfg.AttachStdout, containerCfg.AttachStderr = false, false
+	containerCfg.Labels = map[string]string{
+		labels.FunctionKey:        labels.FunctionValue,
+		labels.FunctionNameKey:    f.Name,
+		labels.FunctionRuntimeKey: f.Runtime,
+		labels.DeployerKey:        deployerName,
+		portLabel:                 port,
+	}
+	hostCfg, err := newHostConfig(port)
+	if err != nil {
+		return
+	}
+	hostCfg.RestartPolicy = container.RestartPolicy{Name: restartPolicy}
+
+	t, err := c.ContainerCreate(ctx, &containerCfg, &hostCfg, nil, nil, nextContainerName(f.Name))
+	if client.IsErrNotFound(err) {
+		// The image was neither built nor pulled locally
+		if err = pullImage(ctx, c, image); err != nil {
+			return
+		}
+		t, err = c.ContainerCreate(ctx, &containerCfg, &hostCfg, nil, nil, nextContainerName(f.Name))
+	}
+	if err != nil {
+		return result, errors.Wrap(err, "deployer unable to create container")
+	}
+
+	// Stop the previous containers, releasing the port, and should the new
+	// container not become ready remove it and restart those which were running.
+	timeout := DefaultStopTimeout
+	for _, ctr := range previous {
+		if err = c.ContainerStop(ctx, ctr.ID, &timeout); err != nil {
+			err = errors.Wrap(err, "deployer unable to stop the previous container")
+			break
+		}
+	}
+	if err == nil {
+		err = d.start(ctx, c, t.ID, port)
+	}
+	if err != nil {
+		restore(c, t.ID, previous)
+		return
+	}
+
+	// The new container replaces the previous
+	for _, ctr := range previous {
+		if err = c.ContainerRemove(ctx, ctr.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return result, errors.Wrap(err, "deployer unable to remove the previous container")
+		}
+	}
+	if err = c.ContainerRename(ctx, t.ID, containerName(f.Name)); err != nil {
+		return result, errors.Wrap(err, "deployer unable to rename container")
+	}
+	return fn.DeploymentResult{
+		Status: status,
+		URL:    routeOf(port),
+	}, nil
+}
+
+// start the container of a deploy, waiting for it to accept requests on the
+// given host port.
+func (d *Deployer) start(ctx context.Context, c client.CommonAPIClient, id, port string) (err error) {
+	if err = c.ContainerStart(ctx, id, types.ContainerStartOptions{}); err != nil {
+		return errors.Wrap(err, "deployer unable to start container")
+	}
+	if d.verbose {
+		fmt.Println("Waiting for the container to accept requests")
+	}
+	if err = waitForContainer(ctx, c, id, routeOf(port)); err != nil {
+		return errors.Wrap(err, "deployer failed to wait for the container to accept requests")
+	}
+	return
+}
+
+// restore the previous containers of a failed deploy, removing the container
+// which failed and restarting those previously running.  Errors are printed,
+// as the deploy has already failed.
+func restore(c client.CommonAPIClient, id string, previous []types.Container) {
+	ctx := context.Background() // the deploy's context may be canceled
+	if err := c.ContainerRemove(ctx, id, types.ContainerRemoveOptions{Force: true}); err != nil {
+		fmt.Fprintf(os.Stderr, "error removing container %v: %v\n", id, err)
+	}
+	for _, ctr := range previous {
+		if ctr.State != "running" {
+			continue
+		}
+		if err := c.ContainerStart(ctx, ctr.ID, types.ContainerStartOptions{}); err != nil {
+			fmt.Fprintf(os.Stderr, "error restarting the previous container %v: %v\n", ctr.ID, err)
+		}
+	}
+}
+
+// listDeployed returns the containers of the named function, or of all
+// functions if no name is given, deployed by the Deployer.
+func listDeployed(ctx context.Context, c client.CommonAPIClient, name string) ([]types.Container, error) {
+	args := filters.NewArgs(
+		filters.Arg("label", fmt.Sprintf("%v=%v", labels.FunctionKey, labels.FunctionValue)),
+		filters.Arg("label", fmt.Sprintf("%v=%v", labels.DeployerKey, deployerName)))
+	if name != "" {
+		args.Add("label", fmt.Sprintf("%v=%v", labels.FunctionNameKey, name))
+	}
+	containers, err := c.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: args})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list containers")
+	}
+	return containers, nil
+}
+
+// containerName of the container of a deployed function.
+func containerName(name string) string {
+	return "func-" + name
+}
+
+// nextContainerName of the container of a deploy of the function until it
+// replaces that of the previous deployment.  Function names have no dots, so
+// it is not the container name of any function.
+func nextContainerName(name string) string {
+	return containerName(name) + ".next"
+}
+
+// hasName returns true if the container has the given name.  The names of
+// listed containers are prefixed with a slash.
+func hasName(ctr types.Container, name string) bool {
+	for _, n := range ctr.Names {
+		if strings.TrimPrefix(n, "/") == name {
+			return true
+		}
+	}
+	return false
+}
+
+// routeOf a function deployed on the given host port.
+func routeOf(port string) string {
+	return fmt.Sprintf("http://localhost:%s/", port)
+}
+
+// pullImage to the daemon, discarding the progress reported.
+func pullImage(ctx context.Context, c client.CommonAPIClient, image string) error {
+	rc, err := c.ImagePull(ctx, image, types.ImagePullOptions{})
+	if err != nil {
+		return errors.Wrap(err, "deployer unable to pull image")
+	}
+	defer rc.Close()
+	_, err = io.Copy(io.Discard, rc)
+	return err
+}
+
+// waitForContainer to accept requests at the given URL, failing if the
+// container exits in the meantime.  Any response is accepted, as functions
+// need not serve the root path.
+func waitForContainer(ctx context.Context, c client.CommonAPIClient, id, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultDeployTimeout)
+	defer cancel()
+
+	httpClient := http.Client{Timeout: DefaultDialTimeout}
+	for {
+		info, err := c.ContainerInspect(ctx, id)
+		if err != nil {
+			return err
+		}
+		if info.RestartCount > 0 || (info.State != nil && info.State.Status == "exited") {
+			exitCode := 0
+			if info.State != nil {
+				exitCode = info.State.ExitCode
+			}
+			return fmt.Errorf("container exited with code %v", exitCode)
+		}
+		if res, err := httpClient.Get(url); err == nil {
+			res.Body.Close()
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
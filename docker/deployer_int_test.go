@@ -0,0 +1,94 @@
+//go:build integration
+// +build integration
+
+package docker_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	fn "knative.dev/func"
+	"knative.dev/func/docker"
+)
+
+// TestDeployer ensures that a function deployed as a local container is
+// listed, described, reachable on the same port when redeployed, and
+// removed.
+func TestDeployer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*10)
+	t.Cleanup(cancel)
+
+	prePullTestImages(t)
+
+	f := fn.Function{
+		Name:  "deployer-test",
+		Root:  t.TempDir(),
+		Image: displayEventImg,
+	}
+	remover := docker.NewRemover(true)
+	t.Cleanup(func() { _ = remover.Remove(context.Background(), f.Name) })
+
+	deployer := docker.NewDeployer(true)
+	result, err := deployer.Deploy(ctx, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != fn.Deployed {
+		t.Fatalf("expected status Deployed, got %v", result.Status)
+	}
+	res, err := http.Get(result.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	items, err := docker.NewLister(true).List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, item := range items {
+		found = found || (item.Name == f.Name && item.URL == result.URL)
+	}
+	if !found {
+		t.Fatalf("deployed function not listed: %+v", items)
+	}
+
+	instance, err := docker.NewDescriber(true).Describe(ctx, f.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instance.Route != result.URL {
+		t.Fatalf("expected route %v, got %v", result.URL, instance.Route)
+	}
+
+	// A redeploy replaces the container, on the same port
+	redeployed, err := deployer.Deploy(ctx, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redeployed.Status != fn.Updated || redeployed.URL != result.URL {
+		t.Fatalf("expected the function to be updated at %v, got %+v", result.URL, redeployed)
+	}
+
+	// A failed redeploy keeps the previous container running
+	broken := f
+	broken.Image = "localhost:50000/nonexistent/image:latest"
+	if _, err = deployer.Deploy(ctx, broken); err == nil {
+		t.Fatal("expected a deploy of a nonexistent image to fail")
+	}
+	if res, err = http.Get(result.URL); err != nil {
+		t.Fatalf("expected the previous container to be running: %v", err)
+	}
+	res.Body.Close()
+
+	if err = remover.Remove(ctx, f.Name); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = docker.NewDescriber(true).Describe(ctx, f.Name); !errors.Is(err, fn.ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning once removed, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+
+	fn "knative.dev/func"
+)
+
+// Describer of functions deployed as local containers.
+type Describer struct {
+	verbose bool
+}
+
+// NewDescriber creates a describer of functions deployed as local
+// containers.
+func NewDescriber(verbose bool) *Describer {
+	return &Describer{verbose: verbose}
+}
+
+// Describe the named function deployed by the Deployer, whose route is on
+// the host port of its container.  If the function is not deployed as a
+// local container the error returned is fn.ErrNotRunning.
+func (d *Describer) Describe(ctx context.Context, name string) (description fn.Instance, err error) {
+	c, _, err := NewClient(client.DefaultDockerHost)
+	if err != nil {
+		return description, errors.Wrap(err, "failed to create Docker API client")
+	}
+	defer c.Close()
+
+	containers, err := listDeployed(ctx, c, name)
+	if err != nil {
+		return
+	}
+	if len(containers) == 0 {
+		return description, fn.ErrNotRunning
+	}
+	route := routeOf(containers[0].Labels[portLabel])
+
+	description.Name = name
+	description.Image = containers[0].Image
+	description.Route = route
+	description.Routes = []string{route}
+	return
+}
@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s/labels"
+)
+
+// Lister of functions deployed as local containers.
+type Lister struct {
+	verbose bool
+}
+
+// NewLister creates a lister of functions deployed as local containers.
+func NewLister(verbose bool) *Lister {
+	return &Lister{verbose: verbose}
+}
+
+// List the functions deployed by the Deployer, which have no namespace.
+func (l *Lister) List(ctx context.Context) (items []fn.ListItem, err error) {
+	c, _, err := NewClient(client.DefaultDockerHost)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Docker API client")
+	}
+	defer c.Close()
+
+	containers, err := listDeployed(ctx, c, "")
+	if err != nil {
+		return
+	}
+	for _, ctr := range containers {
+		ready := corev1.ConditionFalse
+		if ctr.State == "running" {
+			ready = corev1.ConditionTrue
+		}
+		items = append(items, fn.ListItem{
+			Name:    ctr.Labels[labels.FunctionNameKey],
+			Runtime: ctr.Labels[labels.FunctionRuntimeKey],
+			URL:     routeOf(ctr.Labels[portLabel]),
+			Ready:   string(ready),
+		})
+	}
+	return
+}
@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	fn "knative.dev/func"
+)
+
+// Logger retrieves the logs of the containers of locally running functions.
+type Logger struct {
+	verbose bool
+}
+
+// NewLogger creates a logger of functions run as local containers.
+func NewLogger(verbose bool) *Logger {
+	return &Logger{verbose: verbose}
+}
+
+// Logs of the containers started by a Runner for the given function.  The
+// revision and container options do not apply to local instances, each of
+// which is a single container.
+func (l *Logger) Logs(ctx context.Context, f fn.Function, opts fn.LogOptions, emit func(fn.LogEntry)) error {
+	c, _, err := NewClient(client.DefaultDockerHost)
+	if err != nil {
+		return errors.Wrap(err, "failed to create Docker API client")
+	}
+	defer c.Close()
+
+	containers, err := c.ContainerList(ctx, types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%v=%v", rootLabel, f.Root))),
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to list containers")
+	}
+	if len(containers) == 0 {
+		return fn.ErrNotRunning
+	}
+
+	logOpts := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+		Timestamps: true,
+	}
+	if !opts.Since.IsZero() {
+		logOpts.Since = fmt.Sprintf("%d.%09d", opts.Since.Unix(), opts.Since.Nanosecond())
+	}
+
+	var eg errgroup.Group
+	for _, ctr := range containers {
+		id := ctr.ID
+		eg.Go(func() error {
+			rc, err := c.ContainerLogs(ctx, id, logOpts)
+			if err != nil {
+				return errors.Wrapf(err, "unable to get logs of container %v", id)
+			}
+			defer rc.Close()
+
+			// The log stream multiplexes stdout and stderr, which are both
+			// written to a single pipe for line splitting.
+			pr, pw := io.Pipe()
+			go func() {
+				_, err := stdcopy.StdCopy(pw, pw, rc)
+				pw.CloseWithError(err)
+			}()
+			scanner := bufio.NewScanner(pr)
+			for scanner.Scan() {
+				emit(parseLogLine(id, scanner.Text()))
+			}
+			return scanner.Err()
+		})
+	}
+	return eg.Wait()
+}
+
+// parseLogLine into an entry, splitting the timestamp with which the line is
+// prefixed from the message.
+func parseLogLine(id, line string) fn.LogEntry {
+	if len(id) > 12 {
+		id = id[:12] // short form, as displayed by docker
+	}
+	e := fn.LogEntry{Instance: id, Message: line}
+	if ts, msg, ok := strings.Cut(line, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			e.Time, e.Message = t, msg
+		}
+	}
+	return e
+}
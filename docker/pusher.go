@@ -22,6 +22,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"golang.org/x/term"
 )
@@ -141,13 +142,25 @@ func (n *Pusher) Push(ctx context.Context, f fn.Function) (digest string, err er
 	}
 	n.progressListener.Increment(fmt.Sprintf("Pushing function image to the registry %q using the %q user credentials", registry, credentials.Username))
 
-	// if the registry is not cluster private do push directly from daemon
-	if _, err = net.DefaultResolver.LookupHost(ctx, registry); err == nil {
-		return n.daemonPush(ctx, f, credentials, output)
+	if f.HasImageLayout() {
+		// images built without a daemon are pushed from their OCI layout
+		digest, err = n.layoutPush(ctx, f, credentials, output)
+	} else if _, err = net.DefaultResolver.LookupHost(ctx, registry); err == nil {
+		// if the registry is not cluster private do push directly from daemon
+		digest, err = n.daemonPush(ctx, f, credentials, output)
+	} else {
+		// push with custom transport to be able to push into cluster private registries
+		digest, err = n.push(ctx, f, credentials, output)
+	}
+	if err != nil {
+		return "", err
 	}
 
-	// push with custom transport to be able to push into cluster private registries
-	return n.push(ctx, f, credentials, output)
+	// the SBOM of the image, if generated, is attached to it as a referrer
+	if err = n.attachSBOM(ctx, f, digest, credentials); err != nil {
+		return "", fmt.Errorf("failed to attach the SBOM to the image: %w", err)
+	}
+	return digest, nil
 }
 
 func (n *Pusher) daemonPush(ctx context.Context, f fn.Function, credentials Credentials, output io.Writer) (digest string, err error) {
@@ -230,21 +243,7 @@ func (n *Pusher) push(ctx context.Context, f fn.Function, credentials Credential
 		return "", err
 	}
 
-	progressChannel := make(chan v1.Update, 1024)
-	errChan := make(chan error)
-	go func() {
-		defer fmt.Fprint(output, "\n")
-
-		for progress := range progressChannel {
-			if progress.Error != nil {
-				errChan <- progress.Error
-				return
-			}
-			fmt.Fprintf(output, "\rprogress: %d%%", progress.Complete*100/progress.Total)
-		}
-
-		errChan <- nil
-	}()
+	progressChannel, errChan := writeProgress(output)
 
 	err = remote.Write(ref, img,
 		remote.WithAuth(auth),
@@ -267,3 +266,88 @@ func (n *Pusher) push(ctx context.Context, f fn.Function, credentials Credential
 
 	return hash.String(), nil
 }
+
+// layoutPush pushes the image of the function from the OCI layout to which it
+// was built (see fn.Function.ImageLayout), without a daemon.
+func (n *Pusher) layoutPush(ctx context.Context, f fn.Function, credentials Credentials, output io.Writer) (digest string, err error) {
+	auth := &authn.Basic{
+		Username: credentials.Username,
+		Password: credentials.Password,
+	}
+
+	ref, err := name.ParseReference(f.Image)
+	if err != nil {
+		return "", err
+	}
+
+	p, err := layout.FromPath(f.ImageLayout())
+	if err != nil {
+		return "", fmt.Errorf("failed to read the image layout: %w", err)
+	}
+	ii, err := p.ImageIndex()
+	if err != nil {
+		return "", err
+	}
+	im, err := ii.IndexManifest()
+	if err != nil {
+		return "", err
+	}
+	if len(im.Manifests) != 1 {
+		return "", fmt.Errorf("expected one image in the image layout %v, found %v", f.ImageLayout(), len(im.Manifests))
+	}
+	desc := im.Manifests[0]
+
+	// Images built for several platforms are pushed as their image index.
+	var write func(...remote.Option) error
+	if desc.MediaType.IsIndex() {
+		index, err := ii.ImageIndex(desc.Digest)
+		if err != nil {
+			return "", err
+		}
+		write = func(options ...remote.Option) error { return remote.WriteIndex(ref, index, options...) }
+	} else {
+		img, err := ii.Image(desc.Digest)
+		if err != nil {
+			return "", err
+		}
+		write = func(options ...remote.Option) error { return remote.Write(ref, img, options...) }
+	}
+
+	progressChannel, errChan := writeProgress(output)
+
+	err = write(
+		remote.WithAuth(auth),
+		remote.WithProgress(progressChannel),
+		remote.WithTransport(n.transport),
+		remote.WithJobs(1),
+		remote.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	if err = <-errChan; err != nil {
+		return "", err
+	}
+
+	return desc.Digest.String(), nil
+}
+
+// writeProgress of a remote write to the output, returning the channel of
+// its updates and that of its final error.
+func writeProgress(output io.Writer) (chan v1.Update, chan error) {
+	progressChannel := make(chan v1.Update, 1024)
+	errChan := make(chan error)
+	go func() {
+		defer fmt.Fprint(output, "\n")
+
+		for progress := range progressChannel {
+			if progress.Error != nil {
+				errChan <- progress.Error
+				return
+			}
+			fmt.Fprintf(output, "\rprogress: %d%%", progress.Complete*100/progress.Total)
+		}
+
+		errChan <- nil
+	}()
+	return progressChannel, errChan
+}
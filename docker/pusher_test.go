@@ -30,6 +30,9 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 
 	fn "knative.dev/func"
@@ -277,6 +280,261 @@ func TestNonDaemonPush(t *testing.T) {
 	}
 }
 
+// TestLayoutPush ensures that an image built to the function's OCI layout is
+// pushed from the layout, without a daemon.
+func TestLayoutPush(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+
+	// in memory network emulation
+	connections := conns(make(chan net.Conn))
+
+	serveRegistry(t, connections)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: true,
+	}
+	transport.DialContext = connections.DialContext
+
+	f := fn.Function{
+		Root:  t.TempDir(),
+		Image: functionImageRemote,
+	}
+	img, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := layout.Write(f.ImageLayout(), empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.AppendImage(img); err != nil {
+		t.Fatal(err)
+	}
+
+	dockerClientFactory := func() (docker.PusherDockerClient, error) {
+		t.Fatal("unexpected use of the daemon")
+		return nil, nil
+	}
+
+	pusher := docker.NewPusher(
+		docker.WithTransport(transport),
+		docker.WithCredentialsProvider(testCredProvider),
+		docker.WithPusherDockerClientFactory(dockerClientFactory),
+	)
+
+	actualDigest, err := pusher.Push(ctx, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDigest, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actualDigest != expectedDigest.String() {
+		t.Errorf("expected digest %v, got %v", expectedDigest, actualDigest)
+	}
+
+	remoteImg, err := remote.Image(name.MustParseReference(functionImageRemote),
+		remote.WithTransport(transport),
+		remote.WithAuth(&authn.Basic{Username: testUser, Password: testPwd}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteDigest, err := remoteImg.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remoteDigest != expectedDigest {
+		t.Errorf("expected the registry to serve %v, got %v", expectedDigest, remoteDigest)
+	}
+}
+
+// TestLayoutPush_Index ensures that an image built for several platforms is
+// pushed as its image index, whose digest is returned.
+func TestLayoutPush_Index(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+
+	// in memory network emulation
+	connections := conns(make(chan net.Conn))
+
+	serveRegistry(t, connections)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: true,
+	}
+	transport.DialContext = connections.DialContext
+
+	f := fn.Function{
+		Root:  t.TempDir(),
+		Image: functionImageRemote,
+	}
+	index, err := random.Index(1024, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := layout.Write(f.ImageLayout(), empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.AppendIndex(index); err != nil {
+		t.Fatal(err)
+	}
+
+	dockerClientFactory := func() (docker.PusherDockerClient, error) {
+		t.Fatal("unexpected use of the daemon")
+		return nil, nil
+	}
+
+	pusher := docker.NewPusher(
+		docker.WithTransport(transport),
+		docker.WithCredentialsProvider(testCredProvider),
+		docker.WithPusherDockerClientFactory(dockerClientFactory),
+	)
+
+	actualDigest, err := pusher.Push(ctx, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDigest, err := index.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actualDigest != expectedDigest.String() {
+		t.Errorf("expected digest %v, got %v", expectedDigest, actualDigest)
+	}
+
+	remoteIndex, err := remote.Index(name.MustParseReference(functionImageRemote),
+		remote.WithTransport(transport),
+		remote.WithAuth(&authn.Basic{Username: testUser, Password: testPwd}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteDigest, err := remoteIndex.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remoteDigest != expectedDigest {
+		t.Errorf("expected the registry to serve %v, got %v", expectedDigest, remoteDigest)
+	}
+}
+
+// TestLayoutPush_SBOM ensures that the SBOM generated of an image is attached
+// to it as a referrer artifact, listed once under its referrers fallback tag
+// however often pushed.
+func TestLayoutPush_SBOM(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+
+	// in memory network emulation
+	connections := conns(make(chan net.Conn))
+
+	serveRegistry(t, connections)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: true,
+	}
+	transport.DialContext = connections.DialContext
+
+	f := fn.Function{
+		Root:  t.TempDir(),
+		Image: functionImageRemote,
+		Build: fn.BuildSpec{SBOM: fn.SBOMFormatSPDX},
+	}
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := layout.Write(f.ImageLayout(), empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.AppendImage(img); err != nil {
+		t.Fatal(err)
+	}
+	sbomFile, sbomMediaType := f.SBOMFile()
+	sbom := []byte(`{"spdxVersion": "SPDX-2.3"}`)
+	if err = os.MkdirAll(f.SBOMDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(sbomFile, sbom, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pusher := docker.NewPusher(
+		docker.WithTransport(transport),
+		docker.WithCredentialsProvider(testCredProvider),
+	)
+	var digest string
+	for i := 0; i < 2; i++ {
+		if digest, err = pusher.Push(ctx, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	options := []remote.Option{
+		remote.WithTransport(transport),
+		remote.WithAuth(&authn.Basic{Username: testUser, Password: testPwd}),
+	}
+	repo := name.MustParseReference(functionImageRemote).Context()
+	desc, err := remote.Get(repo.Tag(strings.Replace(digest, ":", "-", 1)), options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index struct {
+		Manifests []struct {
+			Digest       string `json:"digest"`
+			ArtifactType string `json:"artifactType"`
+		} `json:"manifests"`
+	}
+	if err = json.Unmarshal(desc.Manifest, &index); err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Manifests) != 1 || index.Manifests[0].ArtifactType != sbomMediaType {
+		t.Fatalf("expected the SBOM to be listed once as a referrer, got %s", desc.Manifest)
+	}
+
+	if desc, err = remote.Get(repo.Digest(index.Manifests[0].Digest), options...); err != nil {
+		t.Fatal(err)
+	}
+	var manifest struct {
+		Subject struct {
+			Digest string `json:"digest"`
+		} `json:"subject"`
+		Layers []struct {
+			Digest    string `json:"digest"`
+			MediaType string `json:"mediaType"`
+		} `json:"layers"`
+	}
+	if err = json.Unmarshal(desc.Manifest, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Subject.Digest != digest {
+		t.Errorf("expected the subject %v, got %v", digest, manifest.Subject.Digest)
+	}
+	if len(manifest.Layers) != 1 || manifest.Layers[0].MediaType != sbomMediaType {
+		t.Fatalf("expected the SBOM layer, got %s", desc.Manifest)
+	}
+	layer, err := remote.Layer(repo.Digest(manifest.Layers[0].Digest), options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := layer.Compressed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if b, err := io.ReadAll(rc); err != nil || !bytes.Equal(b, sbom) {
+		t.Errorf("expected the SBOM %s, got %s (%v)", sbom, b, err)
+	}
+}
+
 func newMockPusherDockerClient() *mockPusherDockerClient {
 	return &mockPusherDockerClient{
 		negotiateAPIVersion: func(ctx context.Context) {},
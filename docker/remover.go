@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+
+	fn "knative.dev/func"
+)
+
+// Remover of functions deployed as local containers.
+type Remover struct {
+	verbose bool
+}
+
+// NewRemover creates a remover of functions deployed as local containers.
+func NewRemover(verbose bool) *Remover {
+	return &Remover{verbose: verbose}
+}
+
+// Remove the container of the named function deployed by the Deployer.  If
+// the function is not deployed as a local container the error returned is
+// fn.ErrNotRunning.
+func (r *Remover) Remove(ctx context.Context, name string) error {
+	c, _, err := NewClient(client.DefaultDockerHost)
+	if err != nil {
+		return errors.Wrap(err, "failed to create Docker API client")
+	}
+	defer c.Close()
+
+	containers, err := listDeployed(ctx, c, name)
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 {
+		return fn.ErrNotRunning
+	}
+	for _, ctr := range containers {
+		if err = c.ContainerRemove(ctx, ctr.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return errors.Wrapf(err, "remover unable to remove container %v", ctr.ID)
+		}
+	}
+	return nil
+}
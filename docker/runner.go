@@ -31,6 +31,11 @@ const (
 
 	// DefaultStopTimeout when attempting to stop underlying containers.
 	DefaultStopTimeout = 10 * time.Second
+
+	// rootLabel of the containers of a running function, whose value is the
+	// function's root path.  Used to find the containers of a function's
+	// local instances, such as when retrieving their logs.
+	rootLabel = "function.knative.dev/root"
 )
 
 // Runner starts and stops functions as local containers.
@@ -49,11 +54,10 @@ func NewRunner(verbose bool, out, errOut io.Writer) *Runner {
 	}
 }
 
-// Run the function.
+// Run the function on its run port, or on an available port if not set.
 func (n *Runner) Run(ctx context.Context, f fn.Function) (job *fn.Job, err error) {
 
 	var (
-		port = choosePort(DefaultHost, DefaultPort, DefaultDialTimeout)
 		c    client.CommonAPIClient // Docker client
 		id   string                 // ID of running container
 		conn net.Conn               // Connection to container's stdio
@@ -70,10 +74,24 @@ func (n *Runner) Run(ctx context.Context, f fn.Function) (job *fn.Job, err error
 	if f.Image == "" {
 		return job, errors.New("Function has no associated image. Has it been built?")
 	}
+	port := f.Run.Port
+	if port == "" {
+		port = choosePort(DefaultHost, DefaultPort, DefaultDialTimeout)
+	}
 	if c, _, err = NewClient(client.DefaultDockerHost); err != nil {
 		return job, errors.Wrap(err, "failed to create Docker API client")
 	}
-	if id, err = newContainer(ctx, c, f, port, n.verbose); err != nil {
+
+	// When requests time out, they are received by a proxy on the port which
+	// enforces the timeout, and which forwards them to the container on
+	// another port.
+	containerPort := port
+	timeout := requestTimeout(f)
+	if timeout > 0 {
+		containerPort = openPort(DefaultHost)
+	}
+
+	if id, err = newContainer(ctx, c, f, containerPort, n.verbose); err != nil {
 		return job, errors.Wrap(err, "runner unable to create container")
 	}
 	if conn, err = copyStdio(ctx, c, id, copyErrCh, n.out, n.errOut); err != nil {
@@ -107,11 +125,14 @@ func (n *Runner) Run(ctx context.Context, f fn.Function) (job *fn.Job, err error
 	}
 
 	// Stopper
+	stopProxy, stopSidecars := func() {}, func() {}
 	stop := func() {
 		var (
 			timeout = DefaultStopTimeout
 			ctx     = context.Background()
 		)
+		stopProxy()
+		stopSidecars()
 		if err = c.ContainerStop(ctx, id, &timeout); err != nil {
 			fmt.Fprintf(os.Stderr, "error stopping container %v: %v\n", id, err)
 		}
@@ -126,6 +147,27 @@ func (n *Runner) Run(ctx context.Context, f fn.Function) (job *fn.Job, err error
 		}
 	}
 
+	if len(f.Deploy.Sidecars) > 0 {
+		var stopStartedSidecars func()
+		if stopStartedSidecars, err = startSidecars(ctx, c, f, id, n.verbose, n.out); err != nil {
+			stop()
+			return
+		}
+		stopSidecars = stopStartedSidecars
+	}
+
+	if timeout > 0 {
+		var stopTimeoutProxy func()
+		if stopTimeoutProxy, err = startTimeoutProxy(DefaultHost, port, containerPort, timeout); err != nil {
+			stop()
+			return
+		}
+		stopProxy = stopTimeoutProxy
+		if n.verbose {
+			fmt.Fprintf(n.out, "Requests time out after %v\n", timeout)
+		}
+	}
+
 	// Job reporting port, runtime errors and provides a mechanism for stopping.
 	return fn.NewJob(f, port, runtimeErrCh, stop)
 }
@@ -152,7 +194,12 @@ func choosePort(host string, preferredPort string, dialTimeout time.Duration) st
 		return preferredPort
 	}
 
-	// Use an OS-chosen port
+	return openPort(host)
+}
+
+// openPort returns an unused port chosen by the OS, subject to the same race
+// as choosePort.
+func openPort(host string) string {
 	lis, err := net.Listen("tcp", net.JoinHostPort(host, "")) // listen on any open port
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "unable to check for open ports. using fallback %v. %v", DefaultPort, err)
@@ -197,6 +244,7 @@ func newContainerConfig(f fn.Function, _ string, verbose bool) (c container.Conf
 		AttachStdout: true,
 		AttachStdin:  false,
 		ExposedPorts: map[nat.Port]struct{}{httpPort: {}},
+		Labels:       map[string]string{rootLabel: f.Root},
 	}
 
 	// Environment Variables
@@ -39,9 +39,11 @@ func TestRun(t *testing.T) {
 	var out, errOut bytes.Buffer
 	runner := docker.NewRunner(true, &out, &errOut)
 
-	j, err := runner.Run(ctx, fn.Function{
+	f := fn.Function{
+		Root:  t.TempDir(),
 		Image: displayEventImg,
-	})
+	}
+	j, err := runner.Run(ctx, f)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -83,6 +85,18 @@ func TestRun(t *testing.T) {
 	if !(strings.Contains(outStr, id) && strings.Contains(outStr, src) && strings.Contains(outStr, typ)) {
 		t.Error("output doesn't contain invocation info")
 	}
+
+	// The logs of the running container are also available from the logger.
+	var logs strings.Builder
+	err = docker.NewLogger(true).Logs(ctx, f, fn.LogOptions{}, func(e fn.LogEntry) {
+		logs.WriteString(e.Message + "\n")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), id) {
+		t.Errorf("logs don't contain invocation info: %v", logs.String())
+	}
 }
 
 func prePullTestImages(t *testing.T) {
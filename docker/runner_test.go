@@ -57,3 +57,14 @@ func TestDockerRunImagelessError(t *testing.T) {
 		t.Fatalf("Expected error '%v', got '%v'", expectedErrorMessage, err)
 	}
 }
+
+func TestDockerDeployImagelessError(t *testing.T) {
+	deployer := docker.NewDeployer(true)
+	f := fn.NewFunctionWith(fn.Function{Name: "myfunc"})
+
+	_, err := deployer.Deploy(context.Background(), f)
+	expectedErrorMessage := "Function has no associated image. Has it been built?"
+	if err == nil || err.Error() != expectedErrorMessage {
+		t.Fatalf("Expected error '%v', got '%v'", expectedErrorMessage, err)
+	}
+}
@@ -0,0 +1,182 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	fn "knative.dev/func"
+)
+
+// emptyConfigMediaType of the config of artifacts, such as SBOMs, which have
+// none.
+const emptyConfigMediaType types.MediaType = "application/vnd.oci.empty.v1+json"
+
+// artifactManifest is an OCI image manifest of an artifact referring to its
+// subject, such as the SBOM of an image.  The manifests of go-containerregistry
+// have neither its artifact type nor subject.
+// See https://github.com/opencontainers/image-spec/blob/main/manifest.md
+type artifactManifest struct {
+	SchemaVersion int64           `json:"schemaVersion"`
+	MediaType     types.MediaType `json:"mediaType"`
+	ArtifactType  string          `json:"artifactType"`
+	Config        v1.Descriptor   `json:"config"`
+	Layers        []v1.Descriptor `json:"layers"`
+	Subject       *v1.Descriptor  `json:"subject,omitempty"`
+}
+
+// referrersIndex is the image index listing the referrers of an image, kept
+// under its fallback tag in registries without the referrers API.
+// See https://github.com/opencontainers/distribution-spec/blob/main/spec.md#referrers-tag-schema
+type referrersIndex struct {
+	SchemaVersion int64           `json:"schemaVersion"`
+	MediaType     types.MediaType `json:"mediaType"`
+	Manifests     []referrer      `json:"manifests"`
+}
+
+type referrer struct {
+	v1.Descriptor
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+// rawManifest of the given media type, put as is.
+type rawManifest struct {
+	raw       []byte
+	mediaType types.MediaType
+}
+
+func (m rawManifest) RawManifest() ([]byte, error)        { return m.raw, nil }
+func (m rawManifest) MediaType() (types.MediaType, error) { return m.mediaType, nil }
+
+// attachSBOM of the function's image, if generated (see fn.Function.SBOMFile),
+// to the image pushed with the given digest as an OCI referrer artifact.  The
+// artifact is also listed under the referrers fallback tag of the image, such
+// that it is found in registries with or without the referrers API.
+func (n *Pusher) attachSBOM(ctx context.Context, f fn.Function, digest string, credentials Credentials) error {
+	path, mediaType := f.SBOMFile()
+	if path == "" {
+		return nil
+	}
+	sbom, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil // built before an SBOM was enabled
+	} else if err != nil {
+		return err
+	}
+
+	ref, err := name.ParseReference(f.Image)
+	if err != nil {
+		return err
+	}
+	repo := ref.Context()
+	options := []remote.Option{
+		remote.WithAuth(&authn.Basic{Username: credentials.Username, Password: credentials.Password}),
+		remote.WithTransport(n.transport),
+		remote.WithContext(ctx),
+	}
+
+	// The subject of the artifact: the image as pushed.
+	if digest != "" {
+		ref = repo.Digest(digest)
+	}
+	subject, err := remote.Head(ref, options...)
+	if err != nil {
+		return err
+	}
+
+	// The artifact's blobs: the SBOM and its empty config.
+	sbomLayer := static.NewLayer(sbom, types.MediaType(mediaType))
+	configLayer := static.NewLayer([]byte("{}"), emptyConfigMediaType)
+	descriptors := make([]v1.Descriptor, 2)
+	for i, l := range []v1.Layer{configLayer, sbomLayer} {
+		if err = remote.WriteLayer(repo, l, options...); err != nil {
+			return err
+		}
+		if descriptors[i], err = layerDescriptor(l); err != nil {
+			return err
+		}
+	}
+
+	manifest, err := json.Marshal(artifactManifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		ArtifactType:  mediaType,
+		Config:        descriptors[0],
+		Layers:        descriptors[1:],
+		Subject:       &v1.Descriptor{MediaType: subject.MediaType, Size: subject.Size, Digest: subject.Digest},
+	})
+	if err != nil {
+		return err
+	}
+	hash, size, err := v1.SHA256(bytes.NewReader(manifest))
+	if err != nil {
+		return err
+	}
+	if err = remote.Put(repo.Digest(hash.String()), rawManifest{manifest, types.OCIManifestSchema1}, options...); err != nil {
+		return err
+	}
+
+	return addReferrer(repo, subject.Digest, referrer{
+		Descriptor:   v1.Descriptor{MediaType: types.OCIManifestSchema1, Size: size, Digest: hash},
+		ArtifactType: mediaType,
+	}, options...)
+}
+
+// addReferrer of the subject to the index under its referrers fallback tag,
+// creating the index if none.
+func addReferrer(repo name.Repository, subject v1.Hash, r referrer, options ...remote.Option) error {
+	tag := repo.Tag(strings.Replace(subject.String(), ":", "-", 1))
+
+	index := referrersIndex{SchemaVersion: 2, MediaType: types.OCIImageIndex}
+	desc, err := remote.Get(tag, options...)
+	var terr *transport.Error
+	if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+		err = nil
+	} else if err == nil {
+		if err = json.Unmarshal(desc.Manifest, &index); err != nil {
+			return fmt.Errorf("cannot read the referrers of %v: %w", subject, err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	manifests := []referrer{}
+	for _, m := range index.Manifests {
+		if m.Digest != r.Digest {
+			manifests = append(manifests, m)
+		}
+	}
+	index.Manifests = append(manifests, r)
+
+	raw, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return remote.Put(tag, rawManifest{raw, types.OCIImageIndex}, options...)
+}
+
+// layerDescriptor of a blob of an artifact.
+func layerDescriptor(l v1.Layer) (desc v1.Descriptor, err error) {
+	if desc.MediaType, err = l.MediaType(); err != nil {
+		return
+	}
+	if desc.Size, err = l.Size(); err != nil {
+		return
+	}
+	desc.Digest, err = l.Digest()
+	return
+}
@@ -0,0 +1,83 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+
+	fn "knative.dev/func"
+)
+
+// startSidecars of the function next to its running container of the given
+// ID, returning a function which stops and removes them.  Each sidecar joins
+// the network of the function's container, as do the containers of a pod,
+// such that the function and its sidecars reach one another on localhost.
+// Volumes are not mounted and init containers are not run locally.
+func startSidecars(ctx context.Context, c client.CommonAPIClient, f fn.Function, id string, verbose bool, out io.Writer) (stop func(), err error) {
+	var ids []string
+	stop = func() {
+		timeout := DefaultStopTimeout
+		for _, id := range ids {
+			if err := c.ContainerStop(context.Background(), id, &timeout); err != nil {
+				fmt.Fprintf(os.Stderr, "error stopping sidecar container %v: %v\n", id, err)
+			}
+			if err := c.ContainerRemove(context.Background(), id, types.ContainerRemoveOptions{}); err != nil {
+				fmt.Fprintf(os.Stderr, "error removing sidecar container %v: %v\n", id, err)
+			}
+		}
+	}
+
+	for _, s := range f.Deploy.Sidecars {
+		var containerCfg container.Config
+		if containerCfg, err = newSidecarConfig(s); err != nil {
+			stop()
+			return
+		}
+		hostCfg := container.HostConfig{NetworkMode: container.NetworkMode("container:" + id)}
+
+		t, err := c.ContainerCreate(ctx, &containerCfg, &hostCfg, nil, nil, "")
+		if client.IsErrNotFound(err) {
+			if err = pullImage(ctx, c, s.Image); err == nil {
+				t, err = c.ContainerCreate(ctx, &containerCfg, &hostCfg, nil, nil, "")
+			}
+		}
+		if err != nil {
+			stop()
+			return nil, errors.Wrapf(err, "runner unable to create sidecar %q", s.Name)
+		}
+		ids = append(ids, t.ID)
+
+		if err = c.ContainerStart(ctx, t.ID, types.ContainerStartOptions{}); err != nil {
+			stop()
+			return nil, errors.Wrapf(err, "runner unable to start sidecar %q", s.Name)
+		}
+		if verbose {
+			fmt.Fprintf(out, "Started sidecar %q (%v)\n", s.Name, s.Image)
+		}
+	}
+	return stop, nil
+}
+
+// newSidecarConfig of the container of a sidecar, with its local environment
+// variable references interpolated as for the function.
+func newSidecarConfig(s fn.Container) (c container.Config, err error) {
+	c = container.Config{
+		Image:      s.Image,
+		Entrypoint: s.Command,
+		Cmd:        s.Args,
+	}
+	envs, err := fn.Interpolate(s.Envs)
+	if err != nil {
+		return c, errors.Wrapf(err, "sidecar %q", s.Name)
+	}
+	for k, v := range envs {
+		c.Env = append(c.Env, k+"="+v)
+	}
+	return
+}
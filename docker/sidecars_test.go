@@ -0,0 +1,40 @@
+//go:build !integration
+// +build !integration
+
+package docker
+
+import (
+	"testing"
+
+	"knative.dev/pkg/ptr"
+
+	fn "knative.dev/func"
+)
+
+func TestNewSidecarConfig(t *testing.T) {
+	t.Setenv("PROXY_TOKEN", "secret")
+
+	c, err := newSidecarConfig(fn.Container{
+		Name:    "proxy",
+		Image:   "proxy:1.0",
+		Command: []string{"/proxy"},
+		Args:    []string{"--port=5432"},
+		Envs:    []fn.Env{{Name: ptr.String("TOKEN"), Value: ptr.String("{{ env:PROXY_TOKEN }}")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Image != "proxy:1.0" || c.Entrypoint[0] != "/proxy" || c.Cmd[0] != "--port=5432" {
+		t.Errorf("unexpected sidecar config %+v", c)
+	}
+	if len(c.Env) != 1 || c.Env[0] != "TOKEN=secret" {
+		t.Errorf("expected the local environment variable to be interpolated, got %v", c.Env)
+	}
+
+	if _, err = newSidecarConfig(fn.Container{
+		Name: "proxy",
+		Envs: []fn.Env{{Name: ptr.String("TOKEN"), Value: ptr.String("{{ env:MISSING_PROXY_TOKEN }}")}},
+	}); err == nil {
+		t.Error("expected an error for a missing local environment variable")
+	}
+}
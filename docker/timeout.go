@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	fn "knative.dev/func"
+)
+
+// requestTimeout of the function, or zero if requests do not time out.
+func requestTimeout(f fn.Function) time.Duration {
+	t := f.Deploy.Options.Timeouts
+	if t == nil || t.Request == nil {
+		return 0
+	}
+	return time.Duration(*t.Request) * time.Second
+}
+
+// newTimeoutProxy forwards requests to the target, failing those which do not
+// complete within the timeout with a 504 Gateway Timeout as does the cluster.
+func newTimeoutProxy(target *url.URL, timeout time.Duration) http.Handler {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		if errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+			http.Error(w, fmt.Sprintf("request timed out after %v", timeout), http.StatusGatewayTimeout)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		proxy.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// startTimeoutProxy on the given port of the host, forwarding requests to the
+// function's container on the target port.  Returned is a function which
+// stops the proxy.
+func startTimeoutProxy(host, port, targetPort string, timeout time.Duration) (stop func(), err error) {
+	lis, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen on port %v: %w", port, err)
+	}
+	target := &url.URL{Scheme: "http", Host: net.JoinHostPort(host, targetPort)}
+	srv := &http.Server{Handler: newTimeoutProxy(target, timeout)}
+	go func() { _ = srv.Serve(lis) }()
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultStopTimeout)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}, nil
+}
@@ -0,0 +1,49 @@
+//go:build !integration
+// +build !integration
+
+package docker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// TestTimeoutProxy ensures that requests to a locally run function which do
+// not complete within the request timeout fail as they would on the cluster.
+func TestTimeoutProxy(t *testing.T) {
+	function := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-time.After(5 * time.Second):
+			case <-r.Context().Done():
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer function.Close()
+
+	target, _ := url.Parse(function.URL)
+	proxy := httptest.NewServer(newTimeoutProxy(target, 100*time.Millisecond))
+	defer proxy.Close()
+
+	res, err := http.Get(proxy.URL + "/fast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected a request within the timeout to succeed, got %v", res.Status)
+	}
+
+	res, err = http.Get(proxy.URL + "/slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("expected a request exceeding the timeout to fail with %v, got %v", http.StatusGatewayTimeout, res.Status)
+	}
+}
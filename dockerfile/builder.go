@@ -0,0 +1,276 @@
+/*
+Package dockerfile implements a builder of functions from a Dockerfile (or
+Containerfile) in their root, through the docker (or podman) daemon.
+*/
+package dockerfile
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	dockerClient "github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"golang.org/x/term"
+
+	fn "knative.dev/func"
+	"knative.dev/func/builders"
+	"knative.dev/func/docker"
+	"knative.dev/func/oci"
+)
+
+// DefaultName when no WithName option is provided to NewBuilder
+const DefaultName = builders.Dockerfile
+
+// DockerClient is subset of dockerClient.CommonAPIClient required by this package
+type DockerClient interface {
+	daemon.Client
+	ImageBuild(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
+}
+
+// Builder of functions from a Dockerfile.
+type Builder struct {
+	name     string
+	verbose  bool
+	cli      DockerClient
+	platform string
+}
+
+type Option func(*Builder)
+
+func WithName(n string) Option {
+	return func(b *Builder) {
+		b.name = n
+	}
+}
+
+// WithVerbose toggles verbose logging.
+func WithVerbose(v bool) Option {
+	return func(b *Builder) {
+		b.verbose = v
+	}
+}
+
+func WithDockerClient(cli DockerClient) Option {
+	return func(b *Builder) {
+		b.cli = cli
+	}
+}
+
+func WithPlatform(platform string) Option {
+	return func(b *Builder) {
+		b.platform = platform
+	}
+}
+
+// NewBuilder creates a new instance of a Builder with static defaults.
+func NewBuilder(options ...Option) *Builder {
+	b := &Builder{name: DefaultName}
+	for _, o := range options {
+		o(b)
+	}
+	return b
+}
+
+// Build the function's image from its Dockerfile, with the function's root,
+// less the paths listed in its .funcignore, as the build context.  Build envs
+// are passed as build args.  When built for several platforms, such as
+// "linux/amd64,linux/arm64", the image built for each is written to the
+// function's image layout as an image index (see oci.BuildVariants).
+func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
+	platforms, err := oci.ParsePlatforms(b.platform)
+	if err != nil {
+		return
+	}
+
+	var client = b.cli
+	if client == nil {
+		var c dockerClient.CommonAPIClient
+		c, _, err = docker.NewClient(dockerClient.DefaultDockerHost)
+		if err != nil {
+			return fmt.Errorf("cannot create docker client: %w", err)
+		}
+		defer c.Close()
+		client = c
+	}
+
+	if len(platforms) <= 1 {
+		return b.build(ctx, client, f, b.platform)
+	}
+	return oci.BuildVariants(ctx, f, client, platforms, func(platform v1.Platform) error {
+		return b.build(ctx, client, f, platform.String())
+	})
+}
+
+// build the function's image for the given platform, or that of the daemon
+// if none, into the daemon.
+func (b *Builder) build(ctx context.Context, client DockerClient, f fn.Function, platform string) (err error) {
+	dockerfile, err := f.Dockerfile()
+	if err != nil {
+		return
+	}
+
+	// Build Envs have local env var references interpolated then are passed
+	// as build args, available to the ARG instructions of the Dockerfile.
+	buildEnvs, err := fn.Interpolate(f.Build.BuildEnvs)
+	if err != nil {
+		return
+	}
+	buildArgs := make(map[string]*string, len(buildEnvs))
+	for k, v := range buildEnvs {
+		v := v
+		buildArgs[k] = &v
+	}
+
+	ignored, err := f.Ignorer()
+	if err != nil {
+		return
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pw)
+		err := writeContext(tw, f.Root, dockerfile, ignored)
+		_ = tw.Close()
+		_ = pw.CloseWithError(err)
+	}()
+
+	opts := types.ImageBuildOptions{
+		Tags:       []string{f.Image},
+		Dockerfile: dockerfile,
+		BuildArgs:  buildArgs,
+		Target:     f.Build.Target,
+		Platform:   platform,
+		PullParent: true,
+		Remove:     true,
+	}
+
+	resp, err := client.ImageBuild(ctx, pr, opts)
+	if err != nil {
+		_ = pr.CloseWithError(err)
+		return fmt.Errorf("cannot build the function image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out io.Writer = io.Discard
+	if b.verbose {
+		out = os.Stderr
+	}
+
+	var isTerminal bool
+	var fd uintptr
+	if outF, ok := out.(*os.File); ok {
+		fd = outF.Fd()
+		isTerminal = term.IsTerminal(int(outF.Fd()))
+	}
+
+	return jsonmessage.DisplayJSONMessagesStream(resp.Body, out, fd, isTerminal, nil)
+}
+
+// writeContext of the build, the function's root, to the given tar writer.
+// Ignored paths are excluded, with the exception of the Dockerfile itself.
+// Symbolic links may not point outside of the root.
+func writeContext(tw *tar.Writer, root, dockerfile string, ignored func(string, bool) bool) error {
+	const up = ".." + string(os.PathSeparator)
+	var written bool // the Dockerfile
+	err := filepath.Walk(root, func(path string, fi fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		p, err := filepath.Rel(root, path)
+		if err != nil {
+			return fmt.Errorf("cannot get relative path: %w", err)
+		}
+		if p == "." {
+			return nil
+		}
+
+		if ignored(p, fi.IsDir()) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		lnk := ""
+		if fi.Mode()&fs.ModeSymlink != 0 {
+			lnk, err = os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("cannot read link: %w", err)
+			}
+			target := lnk
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(path), target)
+			}
+			if target, err = filepath.Rel(root, target); err != nil {
+				return fmt.Errorf("cannot get relative path for symlink: %w", err)
+			}
+			if strings.HasPrefix(target, up) || target == ".." {
+				return fmt.Errorf("link %q points outside source root", p)
+			}
+			if filepath.IsAbs(lnk) {
+				if lnk, err = filepath.Rel(filepath.Dir(path), lnk); err != nil {
+					return fmt.Errorf("cannot get relative path for symlink: %w", err)
+				}
+			}
+		}
+
+		hdr, err := tar.FileInfoHeader(fi, filepath.ToSlash(lnk))
+		if err != nil {
+			return fmt.Errorf("cannot create tar header: %w", err)
+		}
+		hdr.Name = filepath.ToSlash(p)
+
+		if err = tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("cannot write header to tar stream: %w", err)
+		}
+		if fi.Mode().IsRegular() {
+			r, err := os.Open(path)
+			if err != nil {
+				return fmt.Errorf("cannot open source file: %w", err)
+			}
+			defer r.Close()
+
+			if _, err = io.Copy(tw, r); err != nil {
+				return fmt.Errorf("cannot copy file to tar stream: %w", err)
+			}
+		}
+		written = written || hdr.Name == dockerfile
+		return nil
+	})
+	if err != nil || written {
+		return err
+	}
+
+	// The Dockerfile was ignored, or is within an ignored directory.
+	fi, err := os.Stat(filepath.Join(root, dockerfile))
+	if err != nil {
+		return fmt.Errorf("cannot stat the dockerfile: %w", err)
+	}
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return fmt.Errorf("cannot create tar header: %w", err)
+	}
+	hdr.Name = dockerfile
+	if err = tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("cannot write header to tar stream: %w", err)
+	}
+	r, err := os.Open(filepath.Join(root, dockerfile))
+	if err != nil {
+		return fmt.Errorf("cannot open the dockerfile: %w", err)
+	}
+	defer r.Close()
+	if _, err = io.Copy(tw, r); err != nil {
+		return fmt.Errorf("cannot copy file to tar stream: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package dockerfile_test
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	fn "knative.dev/func"
+	"knative.dev/func/dockerfile"
+)
+
+// TestBuild ensures the image is built from the function's Dockerfile with
+// its target, platform and build envs as build args.
+func TestBuild(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "Containerfile"), []byte("FROM scratch"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FUNC_TEST_TOKEN", "secret")
+
+	var opts types.ImageBuildOptions
+	cli := mockDocker{
+		build: func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+			opts = options
+			_, _ = io.Copy(io.Discard, context)
+			return types.ImageBuildResponse{Body: io.NopCloser(strings.NewReader(`{"stream": "OK!"}`))}, nil
+		},
+	}
+
+	f := fn.Function{
+		Root:  root,
+		Image: "example.com/alice/f:latest",
+		Build: fn.BuildSpec{
+			Target: "runtime",
+			BuildEnvs: []fn.Env{
+				{Name: ptr("VERSION"), Value: ptr("1.0")},
+				{Name: ptr("TOKEN"), Value: ptr("{{ env:FUNC_TEST_TOKEN }}")},
+			},
+		},
+	}
+	b := dockerfile.NewBuilder(dockerfile.WithDockerClient(cli), dockerfile.WithPlatform("linux/arm64"))
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+
+	if opts.Dockerfile != "Containerfile" {
+		t.Errorf("expected the Containerfile, got %q", opts.Dockerfile)
+	}
+	if len(opts.Tags) != 1 || opts.Tags[0] != f.Image {
+		t.Errorf("expected the image to be tagged %q, got %v", f.Image, opts.Tags)
+	}
+	if opts.Target != "runtime" || opts.Platform != "linux/arm64" {
+		t.Errorf("unexpected target %q or platform %q", opts.Target, opts.Platform)
+	}
+	if v := opts.BuildArgs["VERSION"]; v == nil || *v != "1.0" {
+		t.Errorf("expected the VERSION build arg, got %v", opts.BuildArgs)
+	}
+	if v := opts.BuildArgs["TOKEN"]; v == nil || *v != "secret" {
+		t.Errorf("expected the interpolated TOKEN build arg, got %v", opts.BuildArgs)
+	}
+}
+
+// TestBuildContextFuncIgnore ensures that the paths listed in the function's
+// .funcignore are not included in the build context, but for the Dockerfile.
+func TestBuildContextFuncIgnore(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		fn.IgnoreFile:                   "node_modules/\nbuild/\n",
+		"index.js":                      "",
+		"node_modules/dep/index.js":     "",
+		"build/Dockerfile":              "FROM scratch",
+		filepath.Join(".func", "built"): "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found := map[string]bool{}
+	cli := mockDocker{
+		build: func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+			tr := tar.NewReader(context)
+			for {
+				hdr, err := tr.Next()
+				if err != nil {
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					return types.ImageBuildResponse{}, err
+				}
+				found[hdr.Name] = true
+			}
+			return types.ImageBuildResponse{Body: io.NopCloser(strings.NewReader(`{"stream": "OK!"}`))}, nil
+		},
+	}
+
+	f := fn.Function{Root: root, Build: fn.BuildSpec{Dockerfile: "build/Dockerfile"}}
+	if err := dockerfile.NewBuilder(dockerfile.WithDockerClient(cli)).Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if !found["index.js"] || !found["build/Dockerfile"] {
+		t.Errorf("build context is missing index.js or the Dockerfile: %v", found)
+	}
+	if found["node_modules"] || found["node_modules/dep/index.js"] || found[".func/built"] {
+		t.Errorf("build context contains ignored paths: %v", found)
+	}
+}
+
+func TestBuildFail(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "Dockerfile"), []byte("FROM scratch"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cli := mockDocker{
+		build: func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+			_, _ = io.Copy(io.Discard, context)
+			return types.ImageBuildResponse{
+				Body: io.NopCloser(strings.NewReader(`{"errorDetail": {"message": "Error: this is expected"}}`)),
+			}, nil
+		},
+	}
+	err := dockerfile.NewBuilder(dockerfile.WithDockerClient(cli)).Build(context.Background(), fn.Function{Root: root})
+	if err == nil || !strings.Contains(err.Error(), "Error: this is expected") {
+		t.Error("didn't get expected error")
+	}
+}
+
+func TestBuildNoDockerfile(t *testing.T) {
+	cli := mockDocker{
+		build: func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+			t.Fatal("unexpected build of a function without a Dockerfile")
+			return types.ImageBuildResponse{}, nil
+		},
+	}
+	err := dockerfile.NewBuilder(dockerfile.WithDockerClient(cli)).Build(context.Background(), fn.Function{Root: t.TempDir()})
+	if err == nil {
+		t.Error("expected an error building a function without a Dockerfile")
+	}
+}
+
+// TestBuildPlatforms ensures that, built for several platforms, the image
+// built for each is written to the function's image layout as an image index.
+func TestBuildPlatforms(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "Dockerfile"), []byte("FROM scratch"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := fn.Function{Root: root, Image: "example.com/alice/f:latest"}
+	ref, err := name.ParseReference(f.Image)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The daemon holds the image built last, which differs per platform.
+	var built []string
+	var img v1.Image
+	cli := mockDocker{
+		build: func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+			_, _ = io.Copy(io.Discard, context)
+			built = append(built, options.Platform)
+			var err error
+			if img, err = random.Image(64, 1); err != nil {
+				return types.ImageBuildResponse{}, err
+			}
+			return types.ImageBuildResponse{Body: io.NopCloser(strings.NewReader(`{"stream": "OK!"}`))}, nil
+		},
+		inspect: func(ctx context.Context, image string) (types.ImageInspect, []byte, error) {
+			id, err := img.ConfigName()
+			return types.ImageInspect{ID: id.String()}, nil, err
+		},
+		save: func(ctx context.Context, images []string) (io.ReadCloser, error) {
+			pr, pw := io.Pipe()
+			go func() {
+				_ = pw.CloseWithError(tarball.Write(ref, img, pw))
+			}()
+			return pr, nil
+		},
+	}
+
+	b := dockerfile.NewBuilder(dockerfile.WithDockerClient(cli), dockerfile.WithPlatform("linux/amd64,linux/arm64"))
+	if err = b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 2 || built[0] != "linux/amd64" || built[1] != "linux/arm64" {
+		t.Fatalf("expected builds for linux/amd64 and linux/arm64, got %v", built)
+	}
+
+	p, err := layout.FromPath(f.ImageLayout())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ii, err := p.ImageIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	im, err := ii.IndexManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(im.Manifests) != 1 || !im.Manifests[0].MediaType.IsIndex() {
+		t.Fatalf("expected the layout to hold an image index, got %v", im.Manifests)
+	}
+	index, err := ii.ImageIndex(im.Manifests[0].Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if im, err = index.IndexManifest(); err != nil {
+		t.Fatal(err)
+	}
+	if len(im.Manifests) != 2 {
+		t.Fatalf("expected an image per platform, got %v", len(im.Manifests))
+	}
+	for i, platform := range built {
+		if im.Manifests[i].Platform == nil || im.Manifests[i].Platform.String() != platform {
+			t.Errorf("expected image %v for %v, got %v", i, platform, im.Manifests[i].Platform)
+		}
+	}
+	if im.Manifests[0].Digest == im.Manifests[1].Digest {
+		t.Error("expected a different image per platform")
+	}
+}
+
+type mockDocker struct {
+	build   func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
+	inspect func(ctx context.Context, image string) (types.ImageInspect, []byte, error)
+	save    func(ctx context.Context, images []string) (io.ReadCloser, error)
+}
+
+func (m mockDocker) ImageBuild(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	return m.build(ctx, context, options)
+}
+
+func (m mockDocker) ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error) {
+	if m.inspect != nil {
+		return m.inspect(ctx, image)
+	}
+	return types.ImageInspect{}, nil, errors.New("not implemented")
+}
+
+func (m mockDocker) ImageSave(ctx context.Context, images []string) (io.ReadCloser, error) {
+	if m.save != nil {
+		return m.save(ctx, images)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m mockDocker) NegotiateAPIVersion(ctx context.Context) {}
+
+func (m mockDocker) ImageLoad(ctx context.Context, input io.Reader, quiet bool) (types.ImageLoadResponse, error) {
+	return types.ImageLoadResponse{}, errors.New("not implemented")
+}
+
+func (m mockDocker) ImageTag(ctx context.Context, source, target string) error {
+	return errors.New("not implemented")
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -0,0 +1,245 @@
+// Package export writes the manifests with which a function would be
+// deployed as a package for delivery by tools such as Argo CD or Flux rather
+// than by 'func deploy'.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "sigs.k8s.io/yaml"
+
+	fn "knative.dev/func"
+)
+
+// Format of an exported package.
+type Format string
+
+const (
+	// Raw manifests, one per file.
+	Raw Format = "raw"
+	// Kustomize base whose image, namespace and environment variables are
+	// overridden by overlays.
+	Kustomize Format = "kustomize"
+	// Helm chart whose image, namespace and environment variables are values.
+	Helm Format = "helm"
+)
+
+// DefaultVersion of an exported package.
+const DefaultVersion = "0.1.0"
+
+// Formats in which a function can be exported.
+func Formats() []Format {
+	return []Format{Raw, Kustomize, Helm}
+}
+
+// Options of an export.
+type Options struct {
+	// Format of the package.  Defaults to Raw.
+	Format Format
+	// Version of the package, which must be a semantic version.  Defaults to
+	// DefaultVersion.
+	Version string
+	// Namespace into which the package is deployed by default.
+	Namespace string
+}
+
+// Write the manifests of the function to dir as a package.  Files of an
+// earlier export to the same directory are overwritten.
+func Write(dir string, f fn.Function, m fn.Manifests, opts Options) (err error) {
+	if opts.Format == "" {
+		opts.Format = Raw
+	}
+	if opts.Version == "" {
+		opts.Version = DefaultVersion
+	}
+	if _, err = semver.NewVersion(opts.Version); err != nil {
+		return fmt.Errorf("package version must be a semantic version: %w", err)
+	}
+	objects, err := toObjects(m)
+	if err != nil {
+		return
+	}
+	switch opts.Format {
+	case Raw:
+		return writeRaw(dir, objects, opts)
+	case Kustomize:
+		return writeKustomize(dir, f, objects, opts)
+	case Helm:
+		return writeHelm(dir, f, objects, opts)
+	default:
+		return fmt.Errorf("export format '%v' is not recognized. Supported formats are %v", opts.Format, Formats())
+	}
+}
+
+// object is a manifest in unstructured form.
+type object struct {
+	*unstructured.Unstructured
+}
+
+// file name to which the object is written.
+func (o object) file() string {
+	return fmt.Sprintf("%v-%v.yaml", strings.ToLower(o.GetKind()), o.GetName())
+}
+
+// marshal the object as YAML.
+func (o object) marshal() ([]byte, error) {
+	return k8syaml.Marshal(o.Object)
+}
+
+// toObjects converts the manifests to unstructured objects, omitting their
+// status and other fields set by the cluster, and the time of the deploy, such
+// that the same function is always exported alike.
+func toObjects(m fn.Manifests) ([]object, error) {
+	objects := make([]object, 0, len(m.Objects))
+	for _, o := range m.Objects {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+		if err != nil {
+			return nil, err
+		}
+		u := &unstructured.Unstructured{Object: content}
+		unstructured.RemoveNestedField(u.Object, "status")
+		unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(u.Object, "spec", "template", "metadata", "creationTimestamp")
+		if err = removeEnv(object{u}, builtEnv); err != nil {
+			return nil, err
+		}
+		objects = append(objects, object{u})
+	}
+	return objects, nil
+}
+
+// builtEnv is the environment variable set by the deployers to the time of
+// each deploy, such that each creates a new revision.
+const builtEnv = "BUILT"
+
+// removeEnv removes the named environment variable from the function's
+// container of the object.
+func removeEnv(o object, name string) error {
+	return updateContainer(o, func(container map[string]interface{}) {
+		envs, _ := container["env"].([]interface{})
+		kept := make([]interface{}, 0, len(envs))
+		for _, e := range envs {
+			if env, ok := e.(map[string]interface{}); ok && env["name"] == name {
+				continue
+			}
+			kept = append(kept, e)
+		}
+		if len(kept) == 0 {
+			delete(container, "env")
+		} else {
+			container["env"] = kept
+		}
+	})
+}
+
+// updateContainer calls update with the function's container of the object,
+// which is the first container of its pod template.  Objects without a pod
+// template are left unchanged.
+func updateContainer(o object, update func(container map[string]interface{})) error {
+	path := []string{"spec", "template", "spec", "containers"}
+	containers, ok, err := unstructured.NestedSlice(o.Object, path...)
+	if err != nil || !ok || len(containers) == 0 {
+		return err
+	}
+	container, ok := containers[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	update(container)
+	return unstructured.SetNestedSlice(o.Object, containers, path...)
+}
+
+// envValue is a literal value of an environment variable of the function.
+type envValue struct {
+	Name  string
+	Value string
+}
+
+// parameterizeEnvs replaces the literal values of the environment variables
+// declared by the function in its container with those returned by param,
+// and returns the values replaced.  Variables whose values are taken from
+// Secrets or ConfigMaps, and those set by the deployer, are left unchanged.
+func parameterizeEnvs(f fn.Function, o object, param func(i int, name string) interface{}) (values []envValue, err error) {
+	declared := map[string]bool{}
+	for _, e := range f.Run.Envs {
+		if e.Name != nil {
+			declared[*e.Name] = true
+		}
+	}
+	err = updateContainer(o, func(container map[string]interface{}) {
+		envs, _ := container["env"].([]interface{})
+		for i, e := range envs {
+			env, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := env["name"].(string)
+			value, ok := env["value"].(string)
+			if !ok || !declared[name] {
+				continue
+			}
+			values = append(values, envValue{Name: name, Value: value})
+			envs[i] = param(len(values)-1, name)
+		}
+	})
+	return
+}
+
+// imageOf the function's container of the object, if any.
+func imageOf(o object) (image string) {
+	_ = updateContainer(o, func(container map[string]interface{}) {
+		image, _ = container["image"].(string)
+	})
+	return
+}
+
+// splitImage into its name, and its tag or digest.
+func splitImage(image string) (name, tag, digest string) {
+	name = image
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		name, digest = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, tag = name[:i], name[i+1:]
+	}
+	return
+}
+
+// writeFile to dir, creating its parent directories.
+func writeFile(dir, file string, data []byte) error {
+	path := filepath.Join(dir, file)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// writeRaw writes each object to its own file, in the default namespace.
+func writeRaw(dir string, objects []object, opts Options) error {
+	for _, o := range objects {
+		if opts.Namespace != "" {
+			o.SetNamespace(opts.Namespace)
+		}
+		bb, err := o.marshal()
+		if err != nil {
+			return err
+		}
+		if err = writeFile(dir, o.file(), bb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quote a string as a YAML scalar.
+func quote(s string) string {
+	bb, _ := json.Marshal(s) // JSON strings are valid YAML
+	return string(bb)
+}
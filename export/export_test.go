@@ -0,0 +1,239 @@
+//go:build !integration
+// +build !integration
+
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/pkg/ptr"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/yaml"
+
+	fn "knative.dev/func"
+)
+
+const testImage = "example.com/alice/myfunc@sha256:0123"
+
+// testFunction and the manifests with which it is deployed.
+func testFunction() (fn.Function, fn.Manifests) {
+	f := fn.Function{
+		Name: "myfunc",
+		Run: fn.RunSpec{
+			Envs: []fn.Env{
+				{Name: ptr.String("GREETING"), Value: ptr.String("hello")},
+				{Name: ptr.String("API_KEY"), Value: ptr.String("{{ secret:credentials:key }}")},
+			},
+		},
+	}
+	service := &v1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "serving.knative.dev/v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "myfunc",
+			Namespace:   "ns",
+			Annotations: map[string]string{"example.com/template": "{{ not an action }}"},
+		},
+	}
+	service.Spec.Template.Spec.Containers = []corev1.Container{{
+		Image: testImage,
+		Env: []corev1.EnvVar{
+			{Name: "BUILT", Value: "20221201T120000"},
+			{Name: "GREETING", Value: "hello"},
+			{Name: "API_KEY", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"}, Key: "key"}}},
+		},
+	}}
+	trigger := &eventingv1.Trigger{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "eventing.knative.dev/v1", Kind: "Trigger"},
+		ObjectMeta: metav1.ObjectMeta{Name: "myfunc-trigger", Namespace: "ns"},
+		Spec:       eventingv1.TriggerSpec{Broker: "default"},
+	}
+	return f, fn.Manifests{Objects: []runtime.Object{service, trigger}}
+}
+
+func readFile(t *testing.T, path ...string) string {
+	t.Helper()
+	bb, err := os.ReadFile(filepath.Join(path...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(bb)
+}
+
+func TestWrite_Raw(t *testing.T) {
+	dir := t.TempDir()
+	f, m := testFunction()
+	if err := Write(dir, f, m, Options{Format: Raw, Namespace: "prod"}); err != nil {
+		t.Fatal(err)
+	}
+
+	service := v1.Service{}
+	if err := yaml.Unmarshal([]byte(readFile(t, dir, "service-myfunc.yaml")), &service); err != nil {
+		t.Fatal(err)
+	}
+	if service.Namespace != "prod" {
+		t.Errorf("expected namespace 'prod', got '%v'", service.Namespace)
+	}
+	if service.Spec.Template.Spec.Containers[0].Image != testImage {
+		t.Errorf("unexpected image %v", service.Spec.Template.Spec.Containers[0].Image)
+	}
+	if s := readFile(t, dir, "service-myfunc.yaml"); strings.Contains(s, "status") || strings.Contains(s, "creationTimestamp") {
+		t.Errorf("expected fields set by the cluster to be omitted, got:\n%v", s)
+	}
+	if s := readFile(t, dir, "service-myfunc.yaml"); strings.Contains(s, "BUILT") {
+		t.Errorf("expected the time of the deploy to be omitted, got:\n%v", s)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "trigger-myfunc-trigger.yaml")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWrite_Kustomize(t *testing.T) {
+	dir := t.TempDir()
+	f, m := testFunction()
+	if err := Write(dir, f, m, Options{Format: Kustomize, Version: "1.2.3", Namespace: "prod"}); err != nil {
+		t.Fatal(err)
+	}
+
+	k := kustomizeFile{}
+	if err := yaml.Unmarshal([]byte(readFile(t, dir, kustomization)), &k); err != nil {
+		t.Fatal(err)
+	}
+	if k.Namespace != "prod" || k.Labels[0].Pairs["app.kubernetes.io/version"] != "1.2.3" {
+		t.Errorf("unexpected namespace or version of %+v", k)
+	}
+	if len(k.Resources) != 2 || k.Resources[0] != "service-myfunc.yaml" || k.Resources[1] != "trigger-myfunc-trigger.yaml" {
+		t.Errorf("unexpected resources %v", k.Resources)
+	}
+	if len(k.Images) != 1 || k.Images[0].Name != "example.com/alice/myfunc" || k.Images[0].Digest != "sha256:0123" {
+		t.Errorf("unexpected images %+v", k.Images)
+	}
+	if len(k.ConfigMapGenerator) != 1 || len(k.ConfigMapGenerator[0].Literals) != 1 || k.ConfigMapGenerator[0].Literals[0] != "GREETING=hello" {
+		t.Errorf("unexpected generated config maps %+v", k.ConfigMapGenerator)
+	}
+	if len(k.Configurations) != 1 {
+		t.Errorf("expected the name reference of the Knative Service to be configured, got %v", k.Configurations)
+	}
+
+	service := v1.Service{}
+	if err := yaml.Unmarshal([]byte(readFile(t, dir, "service-myfunc.yaml")), &service); err != nil {
+		t.Fatal(err)
+	}
+	if service.Namespace != "" {
+		t.Errorf("expected the namespace to be set by the kustomization, got '%v'", service.Namespace)
+	}
+	env := service.Spec.Template.Spec.Containers[0].Env
+	if len(env) != 2 {
+		t.Fatalf("expected the time of the deploy to be omitted, got %+v", env)
+	}
+	if env[0].ValueFrom == nil || env[0].ValueFrom.ConfigMapKeyRef == nil || env[0].ValueFrom.ConfigMapKeyRef.Name != "myfunc-env" {
+		t.Errorf("expected GREETING to be taken from the generated config map, got %+v", env[0])
+	}
+	if env[1].ValueFrom == nil || env[1].ValueFrom.SecretKeyRef == nil {
+		t.Errorf("expected API_KEY to be taken from its secret, got %+v", env[1])
+	}
+}
+
+func TestWrite_Helm(t *testing.T) {
+	dir := t.TempDir()
+	f, m := testFunction()
+	if err := Write(dir, f, m, Options{Format: Helm, Version: "1.2.3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	chart := helmChartFile{}
+	if err := yaml.Unmarshal([]byte(readFile(t, dir, helmChart)), &chart); err != nil {
+		t.Fatal(err)
+	}
+	if chart.Name != "myfunc" || chart.Version != "1.2.3" {
+		t.Errorf("unexpected chart %+v", chart)
+	}
+
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(readFile(t, dir, helmValues)), &values); err != nil {
+		t.Fatal(err)
+	}
+	values["env"].(map[string]interface{})["GREETING"] = "hi"
+	values["image"].(map[string]interface{})["digest"] = "sha256:4567"
+
+	// Render the template as would Helm, with the values overridden
+	funcs := template.FuncMap{
+		"quote": quote,
+		"default": func(d, v interface{}) interface{} {
+			if v == nil || v == "" {
+				return d
+			}
+			return v
+		},
+	}
+	tpl, err := template.New("service").Funcs(funcs).Parse(readFile(t, dir, helmTemplates, "service-myfunc.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := bytes.Buffer{}
+	err = tpl.Execute(&buf, map[string]interface{}{
+		"Values":  values,
+		"Release": map[string]interface{}{"Namespace": "release-ns"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := v1.Service{}
+	if err := yaml.Unmarshal(buf.Bytes(), &service); err != nil {
+		t.Fatalf("%v\n%v", err, buf.String())
+	}
+	if service.Namespace != "release-ns" {
+		t.Errorf("expected the namespace of the release, got '%v'", service.Namespace)
+	}
+	if service.Annotations["example.com/template"] != "{{ not an action }}" {
+		t.Errorf("expected braces to be literal, got '%v'", service.Annotations["example.com/template"])
+	}
+	container := service.Spec.Template.Spec.Containers[0]
+	if container.Image != "example.com/alice/myfunc@sha256:4567" {
+		t.Errorf("unexpected image %v", container.Image)
+	}
+	if len(container.Env) != 2 {
+		t.Fatalf("expected the time of the deploy to be omitted, got %+v", container.Env)
+	}
+	if container.Env[0].Name != "GREETING" || container.Env[0].Value != "hi" {
+		t.Errorf("unexpected environment variable %+v", container.Env[0])
+	}
+}
+
+func TestWrite_Invalid(t *testing.T) {
+	f, m := testFunction()
+	if err := Write(t.TempDir(), f, m, Options{Format: "ksonnet"}); err == nil {
+		t.Error("expected an error with an unrecognized format")
+	}
+	if err := Write(t.TempDir(), f, m, Options{Format: Helm, Version: "latest"}); err == nil {
+		t.Error("expected an error with a version which is not semantic")
+	}
+}
+
+func Test_splitImage(t *testing.T) {
+	tests := []struct {
+		image, name, tag, digest string
+	}{
+		{"example.com/alice/myfunc", "example.com/alice/myfunc", "", ""},
+		{"example.com/alice/myfunc:latest", "example.com/alice/myfunc", "latest", ""},
+		{"localhost:5000/myfunc@sha256:0123", "localhost:5000/myfunc", "", "sha256:0123"},
+		{"localhost:5000/myfunc:v1@sha256:0123", "localhost:5000/myfunc", "v1", "sha256:0123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			name, tag, digest := splitImage(tt.image)
+			if name != tt.name || tag != tt.tag || digest != tt.digest {
+				t.Errorf("splitImage(%v) = %v, %v, %v", tt.image, name, tag, digest)
+			}
+		})
+	}
+}
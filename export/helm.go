@@ -0,0 +1,127 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+
+	fn "knative.dev/func"
+)
+
+const (
+	helmChart     = "Chart.yaml"
+	helmValues    = "values.yaml"
+	helmTemplates = "templates"
+
+	// Placeholders of values in marshaled objects, replaced by the template
+	// actions which render them.  They are plain YAML scalars, and end with
+	// an underscore such that none is a prefix of another.
+	imagePlaceholder     = "FUNC_EXPORT_IMAGE_"
+	namespacePlaceholder = "FUNC_EXPORT_NAMESPACE_"
+	envPlaceholder       = "FUNC_EXPORT_ENV_%d_"
+)
+
+const (
+	imageAction     = `{{ .Values.image.repository }}{{ if .Values.image.digest }}@{{ .Values.image.digest }}{{ else }}:{{ .Values.image.tag }}{{ end }}`
+	namespaceAction = `{{ .Values.namespace | default .Release.Namespace }}`
+	envAction       = `{{ index .Values.env %v | quote }}`
+)
+
+type helmChartFile struct {
+	APIVersion  string `yaml:"apiVersion"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Type        string `yaml:"type"`
+	Version     string `yaml:"version"`
+}
+
+var valuesTemplate = template.Must(template.New("values").Funcs(template.FuncMap{"quote": quote}).Parse(
+	`# Image of the function.  The digest, when given, takes precedence over the tag.
+image:
+  repository: {{ quote .Repository }}
+  tag: {{ quote .Tag }}
+  digest: {{ quote .Digest }}
+
+# Namespace into which the function is deployed.  Defaults to that of the
+# release.
+namespace: {{ quote .Namespace }}
+
+# Values of the function's environment variables.
+{{- if .Envs }}
+env:
+{{- range .Envs }}
+  {{ quote .Name }}: {{ quote .Value }}
+{{- end }}
+{{- else }}
+env: {}
+{{- end }}
+`))
+
+// writeHelm writes the objects as the templates of a Helm chart, whose
+// values are the image, namespace and environment variables of the
+// function.
+func writeHelm(dir string, f fn.Function, objects []object, opts Options) error {
+	values := struct {
+		Repository, Tag, Digest string
+		Namespace               string
+		Envs                    []envValue
+	}{Namespace: opts.Namespace}
+
+	for _, o := range objects {
+		o.SetNamespace(namespacePlaceholder)
+
+		if image := imageOf(o); image != "" {
+			values.Repository, values.Tag, values.Digest = splitImage(image)
+			if err := updateContainer(o, func(container map[string]interface{}) {
+				container["image"] = imagePlaceholder
+			}); err != nil {
+				return err
+			}
+		}
+		envs, err := parameterizeEnvs(f, o, func(i int, name string) interface{} {
+			return map[string]interface{}{"name": name, "value": fmt.Sprintf(envPlaceholder, len(values.Envs)+i)}
+		})
+		if err != nil {
+			return err
+		}
+
+		bb, err := o.marshal()
+		if err != nil {
+			return err
+		}
+		// Braces in the object are literal rather than template actions
+		s := strings.ReplaceAll(string(bb), "{{", `{{ "{{" }}`)
+		s = strings.ReplaceAll(s, imagePlaceholder, imageAction)
+		s = strings.ReplaceAll(s, namespacePlaceholder, namespaceAction)
+		for i, e := range envs {
+			s = strings.ReplaceAll(s, fmt.Sprintf(envPlaceholder, len(values.Envs)+i), fmt.Sprintf(envAction, quote(e.Name)))
+		}
+		values.Envs = append(values.Envs, envs...)
+		if err = writeFile(dir, path.Join(helmTemplates, o.file()), []byte(s)); err != nil {
+			return err
+		}
+	}
+
+	chart, err := yaml.Marshal(helmChartFile{
+		APIVersion:  "v2",
+		Name:        f.Name,
+		Description: fmt.Sprintf("The %v function", f.Name),
+		Type:        "application",
+		Version:     opts.Version,
+	})
+	if err != nil {
+		return err
+	}
+	if err = writeFile(dir, helmChart, chart); err != nil {
+		return err
+	}
+	buf := bytes.Buffer{}
+	if err = valuesTemplate.Execute(&buf, values); err != nil {
+		return err
+	}
+	return writeFile(dir, helmValues, buf.Bytes())
+}
@@ -0,0 +1,136 @@
+package export
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	fn "knative.dev/func"
+)
+
+const (
+	kustomization       = "kustomization.yaml"
+	kustomizationConfig = "kustomizeconfig.yaml"
+)
+
+type kustomizeFile struct {
+	APIVersion         string               `yaml:"apiVersion"`
+	Kind               string               `yaml:"kind"`
+	Namespace          string               `yaml:"namespace,omitempty"`
+	Labels             []kustomizeLabels    `yaml:"labels,omitempty"`
+	Resources          []string             `yaml:"resources"`
+	Images             []kustomizeImage     `yaml:"images,omitempty"`
+	ConfigMapGenerator []kustomizeConfigMap `yaml:"configMapGenerator,omitempty"`
+	Configurations     []string             `yaml:"configurations,omitempty"`
+}
+
+type kustomizeLabels struct {
+	Pairs map[string]string `yaml:"pairs"`
+}
+
+type kustomizeImage struct {
+	Name   string `yaml:"name"`
+	NewTag string `yaml:"newTag,omitempty"`
+	Digest string `yaml:"digest,omitempty"`
+}
+
+type kustomizeConfigMap struct {
+	Name     string   `yaml:"name"`
+	Literals []string `yaml:"literals"`
+}
+
+type kustomizeConfig struct {
+	NameReference []kustomizeNameReference `yaml:"nameReference"`
+}
+
+type kustomizeNameReference struct {
+	Kind       string               `yaml:"kind"`
+	Version    string               `yaml:"version"`
+	FieldSpecs []kustomizeFieldSpec `yaml:"fieldSpecs"`
+}
+
+type kustomizeFieldSpec struct {
+	Kind  string `yaml:"kind"`
+	Group string `yaml:"group"`
+	Path  string `yaml:"path"`
+}
+
+// writeKustomize writes the objects as a Kustomize base.  Overlays override
+// the image using 'images', the namespace using 'namespace', and the values
+// of environment variables by merging into the generated ConfigMap from
+// which they are taken.  As the ConfigMap's name includes a hash of its
+// values, a change to them results in a new revision of the function.
+func writeKustomize(dir string, f fn.Function, objects []object, opts Options) error {
+	k := kustomizeFile{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Namespace:  opts.Namespace,
+		Labels:     []kustomizeLabels{{Pairs: map[string]string{"app.kubernetes.io/version": opts.Version}}},
+	}
+	config := kustomizeConfig{}
+	envs := kustomizeConfigMap{Name: f.Name + "-env"}
+
+	for _, o := range objects {
+		o.SetNamespace("")
+
+		if image := imageOf(o); image != "" {
+			name, tag, digest := splitImage(image)
+			k.Images = append(k.Images, kustomizeImage{Name: name, NewTag: tag, Digest: digest})
+		}
+		values, err := parameterizeEnvs(f, o, func(_ int, name string) interface{} {
+			return map[string]interface{}{
+				"name": name,
+				"valueFrom": map[string]interface{}{
+					"configMapKeyRef": map[string]interface{}{"name": envs.Name, "key": name},
+				},
+			}
+		})
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			envs.Literals = append(envs.Literals, v.Name+"="+v.Value)
+		}
+		// Kustomize updates references to generated ConfigMaps only of the
+		// kinds it knows, such as Deployments, unless configured.
+		if gvk := o.GroupVersionKind(); len(values) > 0 && strings.Contains(gvk.Group, ".") {
+			config.NameReference = append(config.NameReference, kustomizeNameReference{
+				Kind:    "ConfigMap",
+				Version: "v1",
+				FieldSpecs: []kustomizeFieldSpec{{
+					Kind:  gvk.Kind,
+					Group: gvk.Group,
+					Path:  "spec/template/spec/containers/env/valueFrom/configMapKeyRef/name",
+				}},
+			})
+		}
+
+		bb, err := o.marshal()
+		if err != nil {
+			return err
+		}
+		if err = writeFile(dir, o.file(), bb); err != nil {
+			return err
+		}
+		k.Resources = append(k.Resources, o.file())
+	}
+
+	if len(envs.Literals) > 0 {
+		k.ConfigMapGenerator = []kustomizeConfigMap{envs}
+	}
+	if len(config.NameReference) > 0 {
+		bb, err := yaml.Marshal(config)
+		if err != nil {
+			return err
+		}
+		if err = writeFile(dir, kustomizationConfig, bb); err != nil {
+			return err
+		}
+		k.Configurations = []string{kustomizationConfig}
+	}
+	bb, err := yaml.Marshal(k)
+	if err != nil {
+		return err
+	}
+	return writeFile(dir, kustomization, bb)
+}
@@ -0,0 +1,165 @@
+package function
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// fileHashes is the name of the file within the run data directory which
+// caches the content hash of each file of the function, such that the
+// fingerprint of a large tree need only rehash those files which have changed.
+const fileHashes = "filehashes.json"
+
+// fileHash is a cached content hash of a single file, keyed by the file's
+// size and modification time.  Should either differ, the file is rehashed.
+type fileHash struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"modTime"`
+	Hash    string `json:"hash"`
+}
+
+// fingerprint returns a hash of the names and contents of the files within a
+// function's root, along with the build settings which affect the resultant
+// image: the builder, builder image, buildpacks, build environment variables
+// and target platform.  File modification times are not considered, such that
+// rewriting a file with the same contents (for example by a git checkout)
+// does not alter the fingerprint.
+//
+// Content hashes are cached in .func/filehashes.json when the run data
+// directory exists.
+func fingerprint(f Function, platform string) (string, error) {
+	h := sha256.New()
+
+	// Build settings
+	fmt.Fprintf(h, "builder:%v\n", f.Build.Builder)
+	fmt.Fprintf(h, "builderImage:%v\n", f.Build.BuilderImages[f.Build.Builder])
+	for _, bp := range f.Build.Buildpacks {
+		fmt.Fprintf(h, "buildpack:%v\n", bp)
+	}
+	envs, err := Interpolate(f.Build.BuildEnvs)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(envs))
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(h, "buildEnv:%v=%v\n", name, envs[name])
+	}
+	fmt.Fprintf(h, "platform:%v\n", platform)
+
+	// Source files
+	ignored, err := f.Ignorer()
+	if err != nil {
+		return "", err
+	}
+	cache := readFileHashes(f)
+	updated := make(map[string]fileHash, len(cache))
+	started := time.Now()
+
+	err = filepath.Walk(f.Root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// Ignore .func, .git and anything listed in .funcignore
+		rel, err := filepath.Rel(f.Root, path)
+		if err != nil {
+			return err
+		}
+		if rel != "." && ignored(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// Directories are implied by the paths of the files they contain.
+		if info.IsDir() {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		entry, ok := cache[rel]
+		if !ok || entry.Size != info.Size() || entry.ModTime != info.ModTime().UnixNano() {
+			entry = fileHash{Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+			if entry.Hash, err = hashFile(path, info); err != nil {
+				return err
+			}
+		}
+		// A file modified within the filesystem's timestamp granularity of
+		// this walk could be modified again without its timestamp changing,
+		// so its hash is not retained.
+		if started.Sub(info.ModTime()) > time.Second {
+			updated[rel] = entry
+		}
+		fmt.Fprintf(h, "%v:%v\n", rel, entry.Hash)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if err = writeFileHashes(f, updated); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// hashFile returns the hex encoded sha256 of the file's content, or of the
+// link's target in the case of a symbolic link.
+func hashFile(path string, info fs.FileInfo) (string, error) {
+	h := sha256.New()
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "symlink:%v", target)
+		return fmt.Sprintf("%x", h.Sum(nil)), nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err = io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// readFileHashes returns the cached file hashes of the function.  A missing
+// or unreadable cache is treated as empty.
+func readFileHashes(f Function) map[string]fileHash {
+	hashes := map[string]fileHash{}
+	bb, err := os.ReadFile(filepath.Join(f.Root, RunDataDir, fileHashes))
+	if err != nil {
+		return hashes
+	}
+	if err = json.Unmarshal(bb, &hashes); err != nil {
+		return map[string]fileHash{}
+	}
+	return hashes
+}
+
+// writeFileHashes caches the given file hashes in the function's run data
+// directory if it exists.
+func writeFileHashes(f Function, hashes map[string]fileHash) error {
+	dir := filepath.Join(f.Root, RunDataDir)
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	bb, err := json.Marshal(hashes)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, fileHashes), bb, os.ModePerm)
+}
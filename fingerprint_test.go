@@ -0,0 +1,134 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestFingerprint_Content ensures that the fingerprint reflects file contents
+// rather than modification timestamps.
+func TestFingerprint_Content(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "handle.go")
+	if err := os.WriteFile(path, []byte("package function"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := Function{Root: root}
+
+	before, err := fingerprint(f, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rewriting the same content with a new timestamp is not a change
+	later := time.Now().Add(time.Hour)
+	if err := os.WriteFile(path, []byte("package function"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if after, err := fingerprint(f, ""); err != nil {
+		t.Fatal(err)
+	} else if after != before {
+		t.Error("expected a timestamp change alone not to alter the fingerprint")
+	}
+
+	// Different content is
+	if err := os.WriteFile(path, []byte("package changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if after, err := fingerprint(f, ""); err != nil {
+		t.Fatal(err)
+	} else if after == before {
+		t.Error("expected a content change to alter the fingerprint")
+	}
+}
+
+// TestFingerprint_Settings ensures that the fingerprint reflects the build
+// settings and target platform.
+func TestFingerprint_Settings(t *testing.T) {
+	f := Function{Root: t.TempDir(), Build: BuildSpec{Builder: "pack"}}
+	base, err := fingerprint(f, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, value := "EXAMPLE", "value"
+	tests := map[string]func(*Function){
+		"builder":      func(f *Function) { f.Build.Builder = "s2i" },
+		"builderImage": func(f *Function) { f.Build.BuilderImages = map[string]string{"pack": "example.com/builder"} },
+		"buildpacks":   func(f *Function) { f.Build.Buildpacks = []string{"example.com/buildpack"} },
+		"buildEnvs":    func(f *Function) { f.Build.BuildEnvs = []Env{{Name: &name, Value: &value}} },
+	}
+	for setting, edit := range tests {
+		edited := f
+		edit(&edited)
+		if hash, err := fingerprint(edited, ""); err != nil {
+			t.Fatal(err)
+		} else if hash == base {
+			t.Errorf("expected a change of %v to alter the fingerprint", setting)
+		}
+	}
+	if hash, err := fingerprint(f, "linux/arm64"); err != nil {
+		t.Fatal(err)
+	} else if hash == base {
+		t.Error("expected a change of platform to alter the fingerprint")
+	}
+}
+
+// TestFingerprint_Cache ensures that file hashes are cached in the run data
+// directory and reused for files whose size and timestamp are unchanged.
+func TestFingerprint_Cache(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, RunDataDir), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(root, "handle.go")
+	if err := os.WriteFile(path, []byte("aaaa"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	earlier := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, earlier, earlier); err != nil {
+		t.Fatal(err)
+	}
+	f := Function{Root: root}
+
+	before, err := fingerprint(f, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes := readFileHashes(f)
+	if _, ok := hashes["handle.go"]; !ok {
+		t.Fatalf("expected handle.go to be cached, got %v", hashes)
+	}
+
+	// Content of the same size and timestamp is presumed unchanged, which
+	// confirms the cached hash is used rather than rehashing.
+	if err := os.WriteFile(path, []byte("bbbb"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, earlier, earlier); err != nil {
+		t.Fatal(err)
+	}
+	if after, err := fingerprint(f, ""); err != nil {
+		t.Fatal(err)
+	} else if after != before {
+		t.Error("expected the cached file hash to be used")
+	}
+
+	// Removing the cache results in the file being rehashed
+	if err := os.Remove(filepath.Join(root, RunDataDir, fileHashes)); err != nil {
+		t.Fatal(err)
+	}
+	if after, err := fingerprint(f, ""); err != nil {
+		t.Fatal(err)
+	} else if after == before {
+		t.Error("expected the file to be rehashed without a cache")
+	}
+}
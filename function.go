@@ -49,11 +49,18 @@ type Function struct {
 	//   alice/my.function.name
 	// If Image is provided, it overrides the default of concatenating
 	// "Registry+Name:latest" to derive the Image.
+	// Image is only set explicitly; the image produced by a build is recorded
+	// in Built.
 	Image string `yaml:"image"`
 
-	// SHA256 hash of the latest image that has been built
+	// SHA256 hash of an explicitly provided Image, pinning the exact image
+	// to deploy.  The digest of a pushed build is recorded in Built.
 	ImageDigest string `yaml:"imageDigest"`
 
+	// Built is the image produced by the most recent local build.  It is
+	// persisted in .func rather than func.yaml.
+	Built BuiltImage `yaml:"-"`
+
 	// Created time is the moment that creation was successfully completed
 	// according to the client which is in charge of what constitutes being
 	// fully "Created" (aka initialized)
@@ -71,6 +78,10 @@ type Function struct {
 
 	//DeploySpec define the deployment properties for a function
 	Deploy DeploySpec `yaml:"deploy"`
+
+	// Profiles are named sets of overrides (for example "staging" or "prod")
+	// which can be selected when deploying.  See ApplyProfile.
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
 }
 
 // BuildSpec
@@ -90,11 +101,29 @@ type BuildSpec struct {
 	Buildpacks []string `yaml:"buildpacks"`
 
 	// Builder is the name of the subsystem that will complete the underlying
-	// build (pack, s2i, etc)
-	Builder string `yaml:"builder" jsonschema:"enum=pack,enum=s2i"`
+	// build (pack, s2i, dockerfile, host)
+	Builder string `yaml:"builder" jsonschema:"enum=pack,enum=s2i,enum=dockerfile,enum=host"`
+
+	// Dockerfile is the path, relative to the function's root, of the
+	// Dockerfile from which the dockerfile builder builds the function.
+	// Defaults to the Dockerfile or Containerfile in its root.
+	Dockerfile string `yaml:"dockerfile,omitempty"`
+
+	// Target is the stage of a multi-stage Dockerfile to build with the
+	// dockerfile builder.  Defaults to the last.
+	Target string `yaml:"target,omitempty"`
+
+	// SBOM is the format (spdx or cyclonedx) of the software bill of
+	// materials generated of the image built, which is kept in .func and
+	// attached to the image when pushed.  None is generated by default.
+	SBOM string `yaml:"sbom,omitempty" jsonschema:"enum=spdx,enum=cyclonedx"`
 
 	// Build Env variables to be set
 	BuildEnvs []Env `yaml:"buildEnvs"`
+
+	// Platform of a build on the cluster, that of the client building it.
+	// Not persisted.  See WithPlatform.
+	Platform string `yaml:"-"`
 }
 
 // RunSpec
@@ -104,6 +133,11 @@ type RunSpec struct {
 
 	// Env variables to be set
 	Envs []Env `yaml:"envs"`
+
+	// Port on the host on which the function is run locally, rather than an
+	// available port chosen by the runner.  Set for a single run and not
+	// persisted.  See RunWithPort.
+	Port string `yaml:"-"`
 }
 
 // DeploySpec
@@ -115,6 +149,11 @@ type DeploySpec struct {
 	// be triggered in a remote environment rather than run locally.
 	Remote bool `yaml:"remote"`
 
+	// Deployer is the name of the deployer with which the function is
+	// deployed: a Knative Service (the default), or a plain Kubernetes
+	// Deployment and Service for clusters without Knative Serving.
+	Deployer string `yaml:"deployer,omitempty" jsonschema:"enum=knative,enum=kubernetes"`
+
 	// Map containing user-supplied annotations
 	// Example: { "division": "finance" }
 	Annotations map[string]string `yaml:"annotations"`
@@ -127,12 +166,93 @@ type DeploySpec struct {
 
 	// Health endpoints specified by the language pack
 	HealthEndpoints HealthEndpoints `yaml:"healthEndpoints"`
+
+	// Subscriptions of the function to events, each of which is realized as
+	// a Knative Trigger on deploy.
+	Subscriptions []KnativeSubscription `yaml:"subscriptions,omitempty"`
+
+	// Traffic options routing a share of the function's traffic to the
+	// revision being deployed, such as for canary deploys.  By default all
+	// traffic is routed to the revision being deployed.
+	Traffic *TrafficOptions `yaml:"traffic,omitempty"`
+
+	// Visibility of the function: public (the default), or cluster-local
+	// such that it is only reachable from within the cluster.
+	Visibility string `yaml:"visibility,omitempty" jsonschema:"enum=public,enum=cluster-local"`
+
+	// Domains at which the function is served in addition to its default
+	// route.  Only used by the knative deployer.
+	Domains []Domain `yaml:"domains,omitempty"`
+
+	// Ingress exposing the function outside of the cluster.  Only used by
+	// the kubernetes deployer, as Knative Services are exposed by Knative.
+	Ingress *IngressOptions `yaml:"ingress,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount with which the
+	// function runs.  Defaults to that of the namespace.
+	ServiceAccountName string `yaml:"serviceAccountName,omitempty"`
+
+	// ImagePullSecrets are the names of Secrets holding the credentials with
+	// which the function's image is pulled from a private registry.
+	ImagePullSecrets []string `yaml:"imagePullSecrets,omitempty"`
+
+	// NodeSelector restricts the function to nodes with all of these labels.
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
+
+	// Tolerations allow the function to be scheduled on nodes with matching
+	// taints.
+	Tolerations []Toleration `yaml:"tolerations,omitempty"`
+
+	// Affinity constrains the nodes on which the function is scheduled,
+	// relative to node labels or to other pods.
+	Affinity *Affinity `yaml:"affinity,omitempty"`
+
+	// Sidecars are containers run alongside the function in each of its
+	// instances, such as proxies or agents.
+	Sidecars []Container `yaml:"sidecars,omitempty"`
+
+	// InitContainers are run in order, each to completion, before the
+	// function starts, such as to fetch data or run migrations.
+	InitContainers []Container `yaml:"initContainers,omitempty"`
+
+	// Image is the full reference, including the digest when known, of the
+	// image most recently deployed.  Set on deploy.
+	Image string `yaml:"image,omitempty"`
 }
 
-// HealthEndpoints specify the liveness and readiness endpoints for a Runtime
+// HealthEndpoints specify the liveness, readiness and startup endpoints for a
+// Runtime, and the timing of the probes of each.
 type HealthEndpoints struct {
 	Liveness  string `yaml:"liveness,omitempty"`
 	Readiness string `yaml:"readiness,omitempty"`
+	// Startup endpoint, which is probed until it succeeds before the liveness
+	// and readiness endpoints are, such as for runtimes which are slow to
+	// start.  By default there is no startup probe.
+	Startup string `yaml:"startup,omitempty"`
+	// Probes configures the timing of the probe of each endpoint.
+	Probes Probes `yaml:"probes,omitempty"`
+}
+
+// Probes configures the timing of the liveness, readiness and startup probes.
+type Probes struct {
+	Liveness  ProbeOptions `yaml:"liveness,omitempty"`
+	Readiness ProbeOptions `yaml:"readiness,omitempty"`
+	Startup   ProbeOptions `yaml:"startup,omitempty"`
+}
+
+// ProbeOptions are the timings of a probe.  Unset (zero) values default to
+// those of the cluster.
+type ProbeOptions struct {
+	// InitialDelaySeconds after the container starts before it is probed.
+	InitialDelaySeconds int32 `yaml:"initialDelaySeconds,omitempty" jsonschema:"minimum=0"`
+	// PeriodSeconds between probes.
+	PeriodSeconds int32 `yaml:"periodSeconds,omitempty" jsonschema:"minimum=0"`
+	// TimeoutSeconds after which a probe fails.
+	TimeoutSeconds int32 `yaml:"timeoutSeconds,omitempty" jsonschema:"minimum=0"`
+	// FailureThreshold is the number of consecutive failed probes after
+	// which the container is restarted or, for the readiness probe, is no
+	// longer sent requests.
+	FailureThreshold int32 `yaml:"failureThreshold,omitempty" jsonschema:"minimum=0"`
 }
 
 // BuildConfig defines builders and buildpacks
@@ -215,6 +335,16 @@ func NewFunction(path string) (f Function, err error) {
 		errorText += "\n" + "Migration: " + functionMigrationError.Error()
 		return Function{}, errors.New(errorText)
 	}
+
+	// The image of the most recent build is local state in .func which, if
+	// present, supersedes any recovered from func.yaml by migration.
+	built, err := readBuiltImage(path)
+	if err != nil {
+		return
+	}
+	if built.Image != "" {
+		f.Built = built
+	}
 	return
 }
 
@@ -230,9 +360,20 @@ func (f Function) Validate() error {
 		validateVolumes(f.Run.Volumes),
 		ValidateBuildEnvs(f.Build.BuildEnvs),
 		ValidateEnvs(f.Run.Envs),
-		validateOptions(f.Deploy.Options),
+		validateOptions(f.Deploy.Options, f.Deploy.Deployer),
 		ValidateLabels(f.Deploy.Labels),
+		validateSubscriptions(f.Deploy.Subscriptions, f.Deploy.Deployer),
+		validateTraffic(f.Deploy.Traffic, f.Deploy.Deployer),
+		validateIngress(f.Deploy.Ingress),
+		validateDomains(f.Deploy),
+		validateVisibility(f.Deploy),
+		validatePodOptions(f.Deploy),
+		validateContainers(f.Deploy, f.Run.Volumes),
+		validateHealthEndpoints(f.Deploy.HealthEndpoints),
 		validateGit(f.Build.Git),
+		validateDockerfile(f.Build),
+		validateSBOM(f.Build),
+		validateProfiles(f.Profiles),
 	}
 
 	var b strings.Builder
@@ -336,7 +477,14 @@ func (f Function) Write() (err error) {
 	}
 	// TODO: open existing file for writing, such that existing permissions
 	// are preserved.
-	return os.WriteFile(path, bb, 0644)
+	if err = os.WriteFile(path, bb, 0644); err != nil {
+		return
+	}
+	// The built image is written to .func
+	if f.Built.Image != "" {
+		err = f.writeBuiltImage()
+	}
+	return
 }
 
 // Initialized returns if the function has been initialized.
@@ -345,21 +493,18 @@ func (f Function) Initialized() bool {
 	return !f.Created.IsZero()
 }
 
-// Built indicates the function has been built.  Does not guarantee the
-// image indicated actually exists, just that it _should_ exist based off
-// the current state of the Function object, in particular the value of
-// the Image and ImageDigest fields.
+// HasImage indicates the function has an image, either explicitly provided
+// or built.  Does not guarantee the image indicated actually exists, just
+// that it _should_ exist based off the current state of the Function object,
+// in particular the value of the Image, ImageDigest and Built fields.
 func (f Function) HasImage() bool {
-	// If Image (the override) and ImageDigest (the most recent build stamp) are
-	// both empty, the function is considered unbuilt.
-	// TODO: upgrade to a "build complete" timestamp.
-	return f.Image != "" || f.ImageDigest != ""
+	// If Image (the override), ImageDigest and the image of the most recent
+	// build are all empty, the function is considered unbuilt.
+	return f.Image != "" || f.ImageDigest != "" || f.Built.Image != ""
 }
 
 // ImageWithDigest returns the full reference to the image including SHA256 Digest.
 // If Digest is empty, image:tag is returned.
-// TODO: Populate this only on a successful deploy, as this results on a dirty
-// git tree on every build.
 func (f Function) ImageWithDigest() string {
 	// Return image, if Digest is empty
 	if f.ImageDigest == "" {
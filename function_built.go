@@ -0,0 +1,84 @@
+package function
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// builtImageFile is the name of the file within the run data directory which
+// records the image produced by the most recent build.
+const builtImageFile = "built-image.yaml"
+
+// builtLayoutDir is the name of the directory within the run data directory
+// to which daemonless builders write the image built, as an OCI image layout.
+const builtLayoutDir = "image"
+
+// BuiltImage is the image produced by the most recent local build of the
+// function, and its digest once pushed.  It is transient local state kept in
+// .func rather than func.yaml, such that building and pushing a function do
+// not modify its source-controlled configuration.
+type BuiltImage struct {
+	// Image is the full OCI image reference which was built.
+	Image string `yaml:"image"`
+
+	// Digest is the SHA256 hash of the image once pushed.
+	Digest string `yaml:"digest,omitempty"`
+}
+
+// readBuiltImage returns the built image recorded in the function's run data
+// directory, or the zero value if there is none.
+func readBuiltImage(root string) (b BuiltImage, err error) {
+	bb, err := os.ReadFile(filepath.Join(root, RunDataDir, builtImageFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	} else if err != nil {
+		return
+	}
+	err = yaml.Unmarshal(bb, &b)
+	return
+}
+
+// writeBuiltImage records the function's built image in its run data
+// directory.
+func (f Function) writeBuiltImage() (err error) {
+	if err = os.MkdirAll(filepath.Join(f.Root, RunDataDir), os.ModePerm); err != nil {
+		return
+	}
+	bb, err := yaml.Marshal(&f.Built)
+	if err != nil {
+		return
+	}
+	return os.WriteFile(filepath.Join(f.Root, RunDataDir, builtImageFile), bb, os.ModePerm)
+}
+
+// withBuiltImage returns the function with its Image and ImageDigest
+// defaulted to those of its most recent build.  Explicitly configured values
+// take precedence.
+func (f Function) withBuiltImage() Function {
+	if f.Image == "" {
+		f.Image = f.Built.Image
+	}
+	if f.ImageDigest == "" && f.Image == f.Built.Image {
+		f.ImageDigest = f.Built.Digest
+	}
+	return f
+}
+
+// ImageLayout returns the path of the OCI image layout to which daemonless
+// builders write the function's image, in place of a container daemon.
+func (f Function) ImageLayout() string {
+	return filepath.Join(f.Root, RunDataDir, builtLayoutDir)
+}
+
+// HasImageLayout returns true if the function's most recent build wrote its
+// image to an OCI image layout.  See ImageLayout.
+func (f Function) HasImageLayout() bool {
+	if f.Root == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(f.ImageLayout(), "index.json"))
+	return err == nil
+}
@@ -0,0 +1,108 @@
+package function
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Container run alongside the function, either as a sidecar for the
+// lifetime of each instance, or as an init container which runs to
+// completion before the function starts.
+type Container struct {
+	// Name of the container, unique among the function's containers.
+	Name string `yaml:"name"`
+	// Image of the container.
+	Image string `yaml:"image"`
+	// Command overriding the entrypoint of the image.
+	Command []string `yaml:"command,omitempty"`
+	// Args to the entrypoint of the image.
+	Args []string `yaml:"args,omitempty"`
+	// Envs of the container, as for the function.
+	Envs []Env `yaml:"envs,omitempty"`
+	// Volumes mounted in the container, as for the function.  Named emptyDir
+	// volumes are shared with the function and its other containers.
+	Volumes []Volume `yaml:"volumes,omitempty"`
+	// Resources requested by and limiting the container.
+	Resources *ResourcesOptions `yaml:"resources,omitempty"`
+}
+
+// userContainerName is the name of the function's own container.
+const userContainerName = "user-container"
+
+// validateContainers checks that the sidecar and init containers of the
+// deploy spec are uniquely named, have an image, and that their envs,
+// volumes and resources are valid.  Named emptyDir volumes, which are shared
+// with the function's volumes, must be defined alike wherever mounted.
+// Returns array of error messages, empty if no errors are found
+func validateContainers(deploy DeploySpec, volumes []Volume) (errors []string) {
+	emptyDirs := map[string]EmptyDir{}
+	definedBy := map[string]string{}
+	validateEmptyDirs := func(owner string, volumes []Volume) {
+		for _, v := range volumes {
+			if v.EmptyDir == nil || v.EmptyDir.Name == "" {
+				continue
+			}
+			name := v.EmptyDir.Name
+			if first, ok := emptyDirs[name]; !ok {
+				emptyDirs[name], definedBy[name] = *v.EmptyDir, owner
+			} else if !sameEmptyDir(first, *v.EmptyDir) {
+				errors = append(errors, fmt.Sprintf("%s defines the emptyDir '%s' with a medium or sizeLimit other than %s", owner, name, definedBy[name]))
+			}
+		}
+	}
+	validateEmptyDirs("the function", volumes)
+
+	names := map[string]bool{userContainerName: true}
+	for _, c := range []struct {
+		kind       string
+		containers []Container
+	}{{"sidecar", deploy.Sidecars}, {"init container", deploy.InitContainers}} {
+		for i, container := range c.containers {
+			name := fmt.Sprintf("%s '%s'", c.kind, container.Name)
+			if !dns1123Label.MatchString(container.Name) {
+				errors = append(errors, fmt.Sprintf("%s at index %d has an invalid name '%s'", c.kind, i, container.Name))
+			} else if names[container.Name] {
+				errors = append(errors, fmt.Sprintf("%s at index %d has a duplicate name '%s'", c.kind, i, container.Name))
+			}
+			names[container.Name] = true
+
+			if container.Image == "" {
+				errors = append(errors, fmt.Sprintf("%s must specify an image", name))
+			}
+			for _, e := range ValidateEnvs(container.Envs) {
+				errors = append(errors, fmt.Sprintf("%s: %s", name, e))
+			}
+			for _, e := range validateVolumes(container.Volumes) {
+				errors = append(errors, fmt.Sprintf("%s: %s", name, e))
+			}
+			validateEmptyDirs(name, container.Volumes)
+			if r := container.Resources; r != nil {
+				if r.Limits != nil && r.Limits.Concurrency != nil {
+					errors = append(errors, fmt.Sprintf("%s must not specify resources.limits.concurrency, which only applies to the function", name))
+				}
+				for _, e := range validateOptions(Options{Resources: r}, "") {
+					errors = append(errors, fmt.Sprintf("%s: %s", name, e))
+				}
+			}
+		}
+	}
+	return
+}
+
+// sameEmptyDir returns true if the definitions of a named emptyDir volume
+// have the same medium and size limit.
+func sameEmptyDir(a, b EmptyDir) bool {
+	if a.Medium != b.Medium || (a.SizeLimit == nil) != (b.SizeLimit == nil) {
+		return false
+	}
+	if a.SizeLimit == nil {
+		return true
+	}
+	qa, errA := resource.ParseQuantity(*a.SizeLimit)
+	qb, errB := resource.ParseQuantity(*b.SizeLimit)
+	if errA != nil || errB != nil {
+		return *a.SizeLimit == *b.SizeLimit // invalid sizes are reported as such
+	}
+	return qa.Cmp(qb) == 0
+}
@@ -0,0 +1,122 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"testing"
+
+	"knative.dev/pkg/ptr"
+)
+
+func Test_validateContainers(t *testing.T) {
+
+	path := "/models"
+
+	tests := []struct {
+		name    string
+		deploy  DeploySpec
+		volumes []Volume
+		errs    int
+	}{
+		{
+			"correct entry - no containers",
+			DeploySpec{},
+			nil,
+			0,
+		},
+		{
+			"correct entry - sidecar and init container",
+			DeploySpec{
+				Sidecars: []Container{{
+					Name:  "proxy",
+					Image: "gcr.io/cloud-sql-connectors/cloud-sql-proxy:2.1.0",
+					Args:  []string{"--port=5432", "project:region:instance"},
+					Envs:  []Env{{Name: ptr.String("LOG_LEVEL"), Value: ptr.String("debug")}},
+					Resources: &ResourcesOptions{
+						Requests: &ResourcesRequestsOptions{CPU: ptr.String("100m")},
+						Limits:   &ResourcesLimitsOptions{Memory: ptr.String("128Mi")},
+					},
+				}},
+				InitContainers: []Container{{
+					Name:    "fetch-models",
+					Image:   "alpine",
+					Command: []string{"sh", "-c", "wget -O /models/model.bin https://example.com/model.bin"},
+					Volumes: []Volume{{EmptyDir: &EmptyDir{Name: "models"}, Path: &path}},
+				}},
+			},
+			[]Volume{{EmptyDir: &EmptyDir{Name: "models"}, Path: &path}},
+			0,
+		},
+		{
+			"incorrect entry - invalid and duplicate names",
+			DeploySpec{
+				Sidecars: []Container{
+					{Name: "Proxy", Image: "proxy"},
+					{Name: "user-container", Image: "proxy"},
+				},
+				InitContainers: []Container{
+					{Name: "migrate", Image: "migrate"},
+					{Name: "migrate", Image: "migrate"},
+				},
+			},
+			nil,
+			3,
+		},
+		{
+			"incorrect entry - missing image",
+			DeploySpec{Sidecars: []Container{{Name: "proxy"}}},
+			nil,
+			1,
+		},
+		{
+			"incorrect entry - invalid envs, volumes and resources",
+			DeploySpec{Sidecars: []Container{{
+				Name:    "proxy",
+				Image:   "proxy",
+				Envs:    []Env{{Name: ptr.String("LOG_LEVEL")}},
+				Volumes: []Volume{{Path: &path}},
+				Resources: &ResourcesOptions{
+					Limits: &ResourcesLimitsOptions{Memory: ptr.String("lots"), Concurrency: ptr.Int64(10)},
+				},
+			}}},
+			nil,
+			4,
+		},
+		{
+			"correct entry - named emptyDir of the same size",
+			DeploySpec{Sidecars: []Container{{
+				Name:    "cache",
+				Image:   "cache",
+				Volumes: []Volume{{EmptyDir: &EmptyDir{Name: "cache", Medium: "Memory", SizeLimit: ptr.String("1024Mi")}, Path: &path}},
+			}}},
+			[]Volume{{EmptyDir: &EmptyDir{Name: "cache", Medium: "Memory", SizeLimit: ptr.String("1Gi")}, Path: &path}},
+			0,
+		},
+		{
+			"incorrect entry - named emptyDir defined differently",
+			DeploySpec{
+				Sidecars: []Container{{
+					Name:    "cache",
+					Image:   "cache",
+					Volumes: []Volume{{EmptyDir: &EmptyDir{Name: "cache", SizeLimit: ptr.String("2Gi")}, Path: &path}},
+				}},
+				InitContainers: []Container{{
+					Name:    "warm",
+					Image:   "warm",
+					Volumes: []Volume{{EmptyDir: &EmptyDir{Name: "cache", Medium: "Memory", SizeLimit: ptr.String("1Gi")}, Path: &path}},
+				}},
+			},
+			[]Volume{{EmptyDir: &EmptyDir{Name: "cache", Medium: "Memory", SizeLimit: ptr.String("1Gi")}, Path: &path}},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateContainers(tt.deploy, tt.volumes); len(got) != tt.errs {
+				t.Errorf("validateContainers() = %v\n got %d errors but want %d", got, len(got), tt.errs)
+			}
+		})
+	}
+}
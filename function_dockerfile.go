@@ -0,0 +1,52 @@
+package function
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultDockerfiles are the files in the function's root, in order of
+// preference, from which the dockerfile builder builds when the function
+// defines no explicit build.dockerfile.
+var DefaultDockerfiles = []string{"Dockerfile", "Containerfile"}
+
+// buildStage matches the names of the stages of a multi-stage build, which
+// may be targeted by build.target.
+var buildStage = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.-]*$`)
+
+// Dockerfile returns the path, relative to the function's root, of the
+// Dockerfile from which the dockerfile builder builds the function: that set
+// in func.yaml, or the first of DefaultDockerfiles present in its root.
+func (f Function) Dockerfile() (string, error) {
+	if f.Build.Dockerfile != "" {
+		if _, err := os.Stat(filepath.Join(f.Root, f.Build.Dockerfile)); err != nil {
+			return "", fmt.Errorf("dockerfile '%v' not found: %w", f.Build.Dockerfile, err)
+		}
+		return filepath.ToSlash(filepath.Clean(f.Build.Dockerfile)), nil
+	}
+	for _, name := range DefaultDockerfiles {
+		if _, err := os.Stat(filepath.Join(f.Root, name)); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("the function has no %v in its root, and no build.dockerfile is set", strings.Join(DefaultDockerfiles, " or "))
+}
+
+// validateDockerfile checks that the Dockerfile is a path within the
+// function's root and that the target, if any, is a valid stage name.
+// Returns array of error messages, empty if no errors are found
+func validateDockerfile(build BuildSpec) (errors []string) {
+	if p := build.Dockerfile; p != "" {
+		clean := filepath.Clean(filepath.FromSlash(p))
+		if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			errors = append(errors, fmt.Sprintf("dockerfile '%s' must be a path within the function's root", p))
+		}
+	}
+	if build.Target != "" && !buildStage.MatchString(build.Target) {
+		errors = append(errors, fmt.Sprintf("target '%s' is not a valid build stage name", build.Target))
+	}
+	return
+}
@@ -0,0 +1,88 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_validateDockerfile(t *testing.T) {
+	tests := []struct {
+		name  string
+		build BuildSpec
+		errs  int
+	}{
+		{
+			"correct - defaults",
+			BuildSpec{},
+			0,
+		},
+		{
+			"correct - dockerfile and target",
+			BuildSpec{Dockerfile: "build/Dockerfile.prod", Target: "runtime"},
+			0,
+		},
+		{
+			"incorrect - absolute dockerfile",
+			BuildSpec{Dockerfile: "/etc/Dockerfile"},
+			1,
+		},
+		{
+			"incorrect - dockerfile outside of the root",
+			BuildSpec{Dockerfile: "../Dockerfile"},
+			1,
+		},
+		{
+			"incorrect - target",
+			BuildSpec{Target: "-runtime"},
+			1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateDockerfile(tt.build); len(got) != tt.errs {
+				t.Errorf("validateDockerfile() = %v\n got %d errors but want %d", got, len(got), tt.errs)
+			}
+		})
+	}
+}
+
+func TestFunction_Dockerfile(t *testing.T) {
+	root := t.TempDir()
+	f := Function{Root: root}
+
+	if _, err := f.Dockerfile(); err == nil {
+		t.Fatal("expected an error for a function without a Dockerfile")
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "Containerfile"), []byte("FROM scratch"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := f.Dockerfile(); err != nil || p != "Containerfile" {
+		t.Fatalf("expected the Containerfile, got %q (%v)", p, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "Dockerfile"), []byte("FROM scratch"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := f.Dockerfile(); err != nil || p != "Dockerfile" {
+		t.Fatalf("expected the Dockerfile to be preferred, got %q (%v)", p, err)
+	}
+
+	f.Build.Dockerfile = "build/Dockerfile"
+	if _, err := f.Dockerfile(); err == nil {
+		t.Fatal("expected an error for a missing explicit Dockerfile")
+	}
+	if err := os.MkdirAll(filepath.Join(root, "build"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "build", "Dockerfile"), []byte("FROM scratch"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := f.Dockerfile(); err != nil || p != "build/Dockerfile" {
+		t.Fatalf("expected the explicit Dockerfile, got %q (%v)", p, err)
+	}
+}
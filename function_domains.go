@@ -0,0 +1,47 @@
+package function
+
+import (
+	"fmt"
+
+	"knative.dev/func/deployers"
+)
+
+// Domain at which the function is served in addition to its default route,
+// realized as a Knative DomainMapping on deploy:
+//
+//	domains:
+//	- name: api.example.com
+//	  tlsSecret: api-example-com-tls
+type Domain struct {
+	// Name of the domain, such as "api.example.com".  DNS records of the
+	// domain are managed outside of the cluster.
+	Name string `yaml:"name"`
+
+	// TLSSecret is the name of a Secret containing the certificate with
+	// which the domain is served over TLS.  Defaults to that provisioned by
+	// the cluster, if any.
+	TLSSecret string `yaml:"tlsSecret,omitempty"`
+}
+
+// validateDomains checks that the domains are valid and distinct host names
+// naming valid secrets, and that the function is deployed as a Knative
+// Service, to which alone they can be mapped.
+// Returns array of error messages, empty if no errors are found
+func validateDomains(deploy DeploySpec) (errors []string) {
+	if len(deploy.Domains) > 0 && deploy.Deployer == deployers.Kubernetes {
+		errors = append(errors, fmt.Sprintf("domains can not be used with the '%s' deployer, use an ingress instead", deployers.Kubernetes))
+	}
+	names := map[string]bool{}
+	for i, d := range deploy.Domains {
+		if !dns1123Subdomain.MatchString(d.Name) {
+			errors = append(errors, fmt.Sprintf("domain at index %d has an invalid name '%s'", i, d.Name))
+		} else if names[d.Name] {
+			errors = append(errors, fmt.Sprintf("domain at index %d has a duplicate name '%s'", i, d.Name))
+		}
+		names[d.Name] = true
+		if d.TLSSecret != "" && !dns1123Subdomain.MatchString(d.TLSSecret) {
+			errors = append(errors, fmt.Sprintf("domain '%s' has an invalid TLS secret name '%s'", d.Name, d.TLSSecret))
+		}
+	}
+	return
+}
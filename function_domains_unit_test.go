@@ -0,0 +1,64 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"testing"
+)
+
+func Test_validateDomains(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		deploy DeploySpec
+		errs   int
+	}{
+		{
+			"correct entry - no domains",
+			DeploySpec{},
+			0,
+		},
+		{
+			"correct entry - domains",
+			DeploySpec{Domains: []Domain{
+				{Name: "api.example.com"},
+				{Name: "www.example.com", TLSSecret: "www-example-com-tls"},
+			}},
+			0,
+		},
+		{
+			"correct entry - domains with the knative deployer",
+			DeploySpec{Deployer: "knative", Domains: []Domain{{Name: "api.example.com"}}},
+			0,
+		},
+		{
+			"incorrect entry - domains with the kubernetes deployer",
+			DeploySpec{Deployer: "kubernetes", Domains: []Domain{{Name: "api.example.com"}}},
+			1,
+		},
+		{
+			"incorrect entry - invalid and duplicate names",
+			DeploySpec{Domains: []Domain{
+				{Name: "API.example.com"},
+				{Name: ""},
+				{Name: "api.example.com"},
+				{Name: "api.example.com"},
+			}},
+			3,
+		},
+		{
+			"incorrect entry - invalid TLS secret",
+			DeploySpec{Domains: []Domain{{Name: "api.example.com", TLSSecret: "api_tls"}}},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateDomains(tt.deploy); len(got) != tt.errs {
+				t.Errorf("validateDomains() = %v\n got %d errors but want %d", got, len(got), tt.errs)
+			}
+		})
+	}
+}
@@ -4,9 +4,14 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/go-git/go-git/v5"
 	giturls "github.com/whilp/git-urls"
 )
 
+// GitCommitAnnotation is the annotation of a deployed function which records
+// the git commit from which it was deployed.
+const GitCommitAnnotation = "function.knative.dev/git-commit"
+
 type Git struct {
 	URL        string `yaml:"url,omitempty"`
 	Revision   string `yaml:"revision,omitempty"`
@@ -32,3 +37,34 @@ func validateGit(git Git) (errors []string) {
 	}
 	return
 }
+
+// gitCommit returns the hash of the commit checked out in the git repository
+// containing root, or the empty string if root is not within a repository.
+// Uncommitted changes are not reflected.
+func gitCommit(root string) string {
+	repo, err := git.PlainOpenWithOptions(root, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return ""
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Hash().String()
+}
+
+// withGitCommit returns the function with the commit from which it is being
+// deployed, if known, added to its annotations.
+func (f Function) withGitCommit() Function {
+	commit := gitCommit(f.Root)
+	if commit == "" {
+		return f
+	}
+	annotations := make(map[string]string, len(f.Deploy.Annotations)+1)
+	for k, v := range f.Deploy.Annotations {
+		annotations[k] = v
+	}
+	annotations[GitCommitAnnotation] = commit
+	f.Deploy.Annotations = annotations
+	return f
+}
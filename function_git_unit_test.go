@@ -2,6 +2,10 @@ package function
 
 import (
 	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 func Test_validateGit(t *testing.T) {
@@ -72,3 +76,39 @@ func Test_validateGit(t *testing.T) {
 		})
 	}
 }
+
+// Test_withGitCommit ensures that the commit checked out in the repository
+// containing the function is added to its annotations, without modifying
+// those of the original function.
+func Test_withGitCommit(t *testing.T) {
+	root := t.TempDir()
+	f := Function{Root: root, Deploy: DeploySpec{Annotations: map[string]string{"a": "b"}}}
+
+	// Not within a repository
+	if got := f.withGitCommit(); got.Deploy.Annotations[GitCommitAnnotation] != "" {
+		t.Fatalf("expected no commit annotation, got %v", got.Deploy.Annotations)
+	}
+
+	repo, err := git.PlainInit(root, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := wt.Commit("initial", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := f.withGitCommit()
+	if got.Deploy.Annotations[GitCommitAnnotation] != hash.String() || got.Deploy.Annotations["a"] != "b" {
+		t.Fatalf("expected commit %v in annotations, got %v", hash, got.Deploy.Annotations)
+	}
+	if _, ok := f.Deploy.Annotations[GitCommitAnnotation]; ok {
+		t.Fatal("the original function's annotations were modified")
+	}
+}
@@ -0,0 +1,47 @@
+package function
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validateHealthEndpoints checks that the health endpoints are absolute paths
+// and that the timings of their probes are not negative.
+// Returns array of error messages, empty if no errors are found
+func validateHealthEndpoints(h HealthEndpoints) (errors []string) {
+	for _, e := range []struct{ name, path string }{
+		{"liveness", h.Liveness},
+		{"readiness", h.Readiness},
+		{"startup", h.Startup},
+	} {
+		if e.path != "" && !strings.HasPrefix(e.path, "/") {
+			errors = append(errors, fmt.Sprintf("%s endpoint '%s' must be an absolute path", e.name, e.path))
+		}
+	}
+	for _, p := range []struct {
+		name string
+		ProbeOptions
+	}{
+		{"liveness", h.Probes.Liveness},
+		{"readiness", h.Probes.Readiness},
+		{"startup", h.Probes.Startup},
+	} {
+		for _, v := range []struct {
+			field string
+			value int32
+		}{
+			{"initialDelaySeconds", p.InitialDelaySeconds},
+			{"periodSeconds", p.PeriodSeconds},
+			{"timeoutSeconds", p.TimeoutSeconds},
+			{"failureThreshold", p.FailureThreshold},
+		} {
+			if v.value < 0 {
+				errors = append(errors, fmt.Sprintf("%s probe %s must not be negative, got %d", p.name, v.field, v.value))
+			}
+		}
+	}
+	if h.Probes.Startup != (ProbeOptions{}) && h.Startup == "" {
+		errors = append(errors, "startup probe options require a startup endpoint")
+	}
+	return
+}
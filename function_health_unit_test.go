@@ -0,0 +1,63 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"testing"
+)
+
+func Test_validateHealthEndpoints(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		health HealthEndpoints
+		errs   int
+	}{
+		{
+			"correct entry - no health endpoints",
+			HealthEndpoints{},
+			0,
+		},
+		{
+			"correct entry - endpoints and probes",
+			HealthEndpoints{
+				Liveness:  "/health/liveness",
+				Readiness: "/health/readiness",
+				Startup:   "/health/started",
+				Probes: Probes{
+					Liveness: ProbeOptions{InitialDelaySeconds: 5, PeriodSeconds: 10, TimeoutSeconds: 2, FailureThreshold: 3},
+					Startup:  ProbeOptions{PeriodSeconds: 5, FailureThreshold: 60},
+				},
+			},
+			0,
+		},
+		{
+			"incorrect entry - relative endpoint",
+			HealthEndpoints{Liveness: "health/liveness"},
+			1,
+		},
+		{
+			"incorrect entry - negative timings",
+			HealthEndpoints{Probes: Probes{
+				Readiness: ProbeOptions{PeriodSeconds: -1, TimeoutSeconds: -1},
+			}},
+			2,
+		},
+		{
+			"incorrect entry - startup probe options without a startup endpoint",
+			HealthEndpoints{Probes: Probes{
+				Startup: ProbeOptions{FailureThreshold: 60},
+			}},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateHealthEndpoints(tt.health); len(got) != tt.errs {
+				t.Errorf("validateHealthEndpoints() = %v\n got %d errors but want %d", got, len(got), tt.errs)
+			}
+		})
+	}
+}
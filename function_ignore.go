@@ -0,0 +1,72 @@
+package function
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitignore "github.com/sabhiram/go-gitignore"
+)
+
+// IgnoreFile is the name of the file, in the function's root, which lists
+// paths in gitignore syntax to be excluded from the build context, the remote
+// build upload and the build staleness fingerprint.  For example:
+//
+//	node_modules/
+//	target/
+//	*.log
+//	/test/fixtures
+const IgnoreFile = ".funcignore"
+
+// IgnorePatterns returns the patterns listed in the function's .funcignore
+// file, excluding blank lines and comments.  A function without a .funcignore
+// has no patterns.
+func (f Function) IgnorePatterns() (patterns []string, err error) {
+	file, err := os.Open(filepath.Join(f.Root, IgnoreFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot open %v: %w", IgnoreFile, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read %v: %w", IgnoreFile, err)
+	}
+	return
+}
+
+// Ignorer returns a function which reports whether the given path, relative
+// to the function's root, is excluded by the function's .funcignore.  The
+// .func and .git directories are always excluded.
+func (f Function) Ignorer() (func(path string, isDir bool) bool, error) {
+	patterns, err := f.IgnorePatterns()
+	if err != nil {
+		return nil, err
+	}
+	gi := gitignore.CompileIgnoreLines(patterns...)
+	return func(path string, isDir bool) bool {
+		path = filepath.ToSlash(path)
+		if path == RunDataDir || path == ".git" ||
+			strings.HasPrefix(path, RunDataDir+"/") || strings.HasPrefix(path, ".git/") {
+			return true
+		}
+		if gi.MatchesPath(path) {
+			return true
+		}
+		// Directory patterns (those with a trailing slash) only match the
+		// directory itself when it is presented as such.
+		return isDir && gi.MatchesPath(path+"/")
+	}, nil
+}
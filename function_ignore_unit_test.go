@@ -0,0 +1,126 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestIgnorer ensures that paths listed in .funcignore, as well as .func and
+// .git, are reported as ignored.
+func TestIgnorer(t *testing.T) {
+	root := t.TempDir()
+	content := "# dependencies\nnode_modules/\n\ntarget\n*.log\n!keep.log\n/fixtures\n"
+	if err := os.WriteFile(filepath.Join(root, IgnoreFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := Function{Root: root}
+
+	patterns, err := f.IgnorePatterns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"node_modules/", "target", "*.log", "!keep.log", "/fixtures"}
+	if !reflect.DeepEqual(patterns, expected) {
+		t.Fatalf("expected patterns %v, got %v", expected, patterns)
+	}
+
+	ignored, err := f.Ignorer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{".func", true, true},
+		{".func/built", false, true},
+		{".git", true, true},
+		{".gitignore", false, false},
+		{"node_modules", true, true},
+		{"node_modules/dep/index.js", false, true},
+		{"src/node_modules", true, true},
+		{"target", true, true},
+		{"debug.log", false, true},
+		{"keep.log", false, false},
+		{"fixtures", true, true},
+		{"src/fixtures", true, false},
+		{"index.js", false, false},
+	}
+	for _, test := range tests {
+		if ignored(test.path, test.isDir) != test.ignored {
+			t.Errorf("expected %q ignored to be %v", test.path, test.ignored)
+		}
+	}
+}
+
+// TestIgnorer_NoFile ensures that a function without a .funcignore ignores
+// only .func and .git.
+func TestIgnorer_NoFile(t *testing.T) {
+	f := Function{Root: t.TempDir()}
+
+	patterns, err := f.IgnorePatterns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 0 {
+		t.Fatalf("expected no patterns, got %v", patterns)
+	}
+
+	ignored, err := f.Ignorer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ignored(".func", true) || !ignored(".git", true) {
+		t.Error("expected .func and .git to always be ignored")
+	}
+	if ignored("node_modules", true) {
+		t.Error("expected node_modules not to be ignored without a .funcignore")
+	}
+}
+
+// TestFingerprint_FuncIgnore ensures that changes to paths listed in
+// .funcignore do not alter the function's fingerprint.
+func TestFingerprint_FuncIgnore(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, IgnoreFile), []byte("node_modules/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "index.js"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := Function{Root: root}
+
+	before, err := fingerprint(f, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, "node_modules", "dep"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "node_modules", "dep", "index.js"), []byte("dep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	after, err := fingerprint(f, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before != after {
+		t.Error("expected a change to an ignored path not to alter the fingerprint")
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "main.js"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := fingerprint(f, ""); err != nil {
+		t.Fatal(err)
+	} else if changed == before {
+		t.Error("expected a new source file to alter the fingerprint")
+	}
+}
@@ -0,0 +1,51 @@
+package function
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// IngressOptions of a function deployed by the kubernetes deployer, which
+// expose it outside of the cluster at a host:
+//
+//	ingress:
+//	  host: myfunc.example.com
+//	  className: nginx
+//	  tlsSecret: myfunc-tls
+type IngressOptions struct {
+	// Host at which the function is exposed.
+	Host string `yaml:"host"`
+
+	// ClassName of the ingress controller which implements the Ingress.
+	// Defaults to that of the cluster.
+	ClassName string `yaml:"className,omitempty"`
+
+	// TLSSecret is the name of a Secret containing the certificate with
+	// which the host is served over TLS.
+	TLSSecret string `yaml:"tlsSecret,omitempty"`
+}
+
+// dns1123Subdomain is a host name, or the name of a Kubernetes resource such
+// as an ingress class or a secret
+var dns1123Subdomain = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// validateIngress checks that the ingress options name a valid host, ingress
+// class and secret.
+// Returns array of error messages, empty if no errors are found
+func validateIngress(ingress *IngressOptions) (errors []string) {
+	if ingress == nil {
+		return
+	}
+	if ingress.Host == "" {
+		errors = append(errors, "ingress must specify a host")
+	} else if !dns1123Subdomain.MatchString(ingress.Host) {
+		errors = append(errors, fmt.Sprintf("ingress has an invalid host '%s'", ingress.Host))
+	}
+	if ingress.ClassName != "" && !dns1123Subdomain.MatchString(ingress.ClassName) {
+		errors = append(errors, fmt.Sprintf("ingress has an invalid class name '%s'", ingress.ClassName))
+	}
+	if ingress.TLSSecret != "" && !dns1123Subdomain.MatchString(ingress.TLSSecret) {
+		errors = append(errors, fmt.Sprintf("ingress has an invalid TLS secret name '%s'", ingress.TLSSecret))
+	}
+	return
+}
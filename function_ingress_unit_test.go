@@ -0,0 +1,51 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"testing"
+)
+
+func Test_validateIngress(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		ingress *IngressOptions
+		errs    int
+	}{
+		{
+			"correct entry - no ingress",
+			nil,
+			0,
+		},
+		{
+			"correct entry - host, class and secret",
+			&IngressOptions{Host: "myfunc.example.com", ClassName: "nginx", TLSSecret: "myfunc-tls"},
+			0,
+		},
+		{
+			"incorrect entry - missing host",
+			&IngressOptions{ClassName: "nginx"},
+			1,
+		},
+		{
+			"incorrect entry - invalid host",
+			&IngressOptions{Host: "https://myfunc.example.com"},
+			1,
+		},
+		{
+			"incorrect entry - invalid class and secret",
+			&IngressOptions{Host: "myfunc.example.com", ClassName: "Nginx", TLSSecret: "myfunc_tls"},
+			2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateIngress(tt.ingress); len(got) != tt.errs {
+				t.Errorf("validateIngress() = %v\n got %d errors but want %d", got, len(got), tt.errs)
+			}
+		})
+	}
+}
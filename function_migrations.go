@@ -82,6 +82,7 @@ var migrations = []migration{
 	{"0.25.0", migrateToSpecVersion},
 	{"0.34.0", migrateToSpecsStructure},
 	{"0.35.0", migrateFromInvokeStructure},
+	{"0.36.0", migrateToBuiltImage},
 	// New Migrations Here.
 }
 
@@ -309,6 +310,29 @@ func migrateFromInvokeStructure(f1 Function, m migration) (Function, error) {
 	return f1, nil
 }
 
+// migrateToBuiltImage migrates functions prior 0.36.0, in which each build
+// wrote the resultant image name and each push its digest to func.yaml.  These
+// build outputs are now local state in .func (see Function.Built), leaving
+// the image and imageDigest fields of func.yaml for explicitly provided
+// values.  An image equal to that which would be derived from the registry
+// and name is considered to have been written by a build; any other is
+// retained as explicit.  The built image is moved to .func the next time the
+// function is written.
+func migrateToBuiltImage(f Function, m migration) (Function, error) {
+	derived, _ := f.ImageName()
+	if f.ImageDigest != "" || (f.Image != "" && f.Image == derived) {
+		f.Built = BuiltImage{Image: f.Image, Digest: f.ImageDigest}
+		f.ImageDigest = ""
+		if f.Image == derived {
+			f.Image = ""
+		}
+	}
+
+	// Flag f as having had the migration applied
+	f.SpecVersion = m.version
+	return f, nil
+}
+
 // The pertinent aspects of the Function's schema prior the 1.0.0 version migrations
 type migrateToSpecs_previousFunction struct {
 
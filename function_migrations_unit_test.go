@@ -197,3 +197,36 @@ func TestMigrateFromInvokeStructure(t *testing.T) {
 		t.Fatalf("migrated Function expected Invoke '%v', got '%v'", expectedInvoke, f0.Invoke)
 	}
 }
+
+// TestMigrateToBuiltImage ensures that the image and digest written to
+// func.yaml by builds of functions prior to 0.36.0 are migrated to the
+// function's built image, retaining an image which was explicitly provided.
+func TestMigrateToBuiltImage(t *testing.T) {
+	const digest = "sha256:42a6c7ba5b7e2a7b4e36e2b4c9a4b8f6ab9c6b0d0c5e4f3e2d1c0b9a8f7e6d5c"
+
+	// An image derived from the registry and name was written by a build
+	f, err := NewFunction("testdata/migrations/v0.36.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := BuiltImage{Image: "example.com/alice/testfunc:latest", Digest: digest}
+	if f.Built != expected {
+		t.Fatalf("migrated Function expected built image '%v', got '%v'", expected, f.Built)
+	}
+	if f.Image != "" || f.ImageDigest != "" {
+		t.Fatalf("migrated Function expected no image or digest, got '%v' and '%v'", f.Image, f.ImageDigest)
+	}
+
+	// Any other image was explicitly provided
+	f, err = NewFunction("testdata/migrations/v0.36.0-explicit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = BuiltImage{Image: "quay.io/bob/custom:v1", Digest: digest}
+	if f.Built != expected {
+		t.Fatalf("migrated Function expected built image '%v', got '%v'", expected, f.Built)
+	}
+	if f.Image != "quay.io/bob/custom:v1" || f.ImageDigest != "" {
+		t.Fatalf("migrated Function expected explicit image and no digest, got '%v' and '%v'", f.Image, f.ImageDigest)
+	}
+}
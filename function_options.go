@@ -4,11 +4,14 @@ import (
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/resource"
+
+	"knative.dev/func/deployers"
 )
 
 type Options struct {
 	Scale     *ScaleOptions     `yaml:"scale,omitempty"`
 	Resources *ResourcesOptions `yaml:"resources,omitempty"`
+	Timeouts  *TimeoutsOptions  `yaml:"timeouts,omitempty"`
 }
 
 type ScaleOptions struct {
@@ -35,9 +38,24 @@ type ResourcesRequestsOptions struct {
 	Memory *string `yaml:"memory,omitempty" jsonschema:"pattern=^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$"`
 }
 
-// validateOptions checks that input Options are correctly set.
+// TimeoutsOptions of requests to the function, in seconds.  Unset values
+// default to those of the cluster.
+type TimeoutsOptions struct {
+	// Request is the maximum duration of a request, from when it is received
+	// until the response is complete.
+	Request *int64 `yaml:"request,omitempty" json:"request,omitempty" jsonschema_extras:"minimum=0"`
+	// ResponseStart is the maximum duration from when a request is received
+	// until the function starts responding.
+	ResponseStart *int64 `yaml:"responseStart,omitempty" json:"responseStart,omitempty" jsonschema_extras:"minimum=0"`
+	// Idle is the maximum duration a request may go without any bytes being
+	// sent or received.
+	Idle *int64 `yaml:"idle,omitempty" json:"idle,omitempty" jsonschema_extras:"minimum=0"`
+}
+
+// validateOptions checks that input Options are correctly set, and that
+// timeouts, which only Knative applies, are not set for the deployer given.
 // Returns array of error messages, empty if no errors are found
-func validateOptions(options Options) (errors []string) {
+func validateOptions(options Options, deployer string) (errors []string) {
 
 	// options.scale
 	if options.Scale != nil {
@@ -135,5 +153,34 @@ func validateOptions(options Options) (errors []string) {
 		}
 	}
 
+	// options.timeouts
+	if options.Timeouts != nil {
+		if deployer == deployers.Kubernetes {
+			errors = append(errors, fmt.Sprintf("options field \"timeouts\" can not be used with the '%s' deployer", deployers.Kubernetes))
+		}
+		for _, t := range []struct {
+			field string
+			value *int64
+		}{
+			{"timeouts.request", options.Timeouts.Request},
+			{"timeouts.responseStart", options.Timeouts.ResponseStart},
+			{"timeouts.idle", options.Timeouts.Idle},
+		} {
+			if t.value != nil && *t.value < 0 {
+				errors = append(errors, fmt.Sprintf("options field \"%s\" has value set to \"%d\", but it must not be less than 0",
+					t.field, *t.value))
+			}
+		}
+
+		if r := options.Timeouts.Request; r != nil && *r > 0 {
+			if s := options.Timeouts.ResponseStart; s != nil && *s > *r {
+				errors = append(errors, "options field \"timeouts.responseStart\" value must not be greater than \"timeouts.request\"")
+			}
+			if i := options.Timeouts.Idle; i != nil && *i > *r {
+				errors = append(errors, "options field \"timeouts.idle\" value must not be greater than \"timeouts.request\"")
+			}
+		}
+	}
+
 	return
 }
@@ -6,6 +6,7 @@ package function
 import (
 	"testing"
 
+	"knative.dev/func/deployers"
 	"knative.dev/pkg/ptr"
 )
 
@@ -313,14 +314,58 @@ func Test_validateOptions(t *testing.T) {
 			},
 			10,
 		},
+		{
+			"correct 'timeouts'",
+			Options{
+				Timeouts: &TimeoutsOptions{
+					Request:       ptr.Int64(600),
+					ResponseStart: ptr.Int64(60),
+					Idle:          ptr.Int64(120),
+				},
+			},
+			0,
+		},
+		{
+			"incorrect 'timeouts' - negative",
+			Options{
+				Timeouts: &TimeoutsOptions{
+					Request: ptr.Int64(-1),
+					Idle:    ptr.Int64(-1),
+				},
+			},
+			2,
+		},
+		{
+			"incorrect 'timeouts' - greater than the request timeout",
+			Options{
+				Timeouts: &TimeoutsOptions{
+					Request:       ptr.Int64(60),
+					ResponseStart: ptr.Int64(120),
+					Idle:          ptr.Int64(120),
+				},
+			},
+			2,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := validateOptions(tt.options); len(got) != tt.errs {
+			if got := validateOptions(tt.options, ""); len(got) != tt.errs {
 				t.Errorf("validateOptions() = %v\n got %d errors but want %d", got, len(got), tt.errs)
 			}
 		})
 	}
 
 }
+
+// Test_validateOptions_Kubernetes ensures that timeouts are rejected for the
+// kubernetes deployer, which does not apply them.
+func Test_validateOptions_Kubernetes(t *testing.T) {
+	options := Options{Timeouts: &TimeoutsOptions{Request: ptr.Int64(600)}}
+	if errs := validateOptions(options, deployers.Kubernetes); len(errs) != 1 {
+		t.Errorf("expected one error for the kubernetes deployer, got %v", errs)
+	}
+	if errs := validateOptions(options, deployers.Knative); len(errs) != 0 {
+		t.Errorf("expected no errors for the knative deployer, got %v", errs)
+	}
+}
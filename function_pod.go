@@ -0,0 +1,218 @@
+package function
+
+import (
+	"fmt"
+
+	"knative.dev/func/utils"
+)
+
+// Toleration of a node taint, as in Kubernetes.
+type Toleration struct {
+	// Key of the taint.  Empty matches all taints, with the Exists operator.
+	Key string `yaml:"key,omitempty"`
+	// Operator relating the key to the value.  Defaults to Equal.
+	Operator string `yaml:"operator,omitempty" jsonschema:"enum=Exists,enum=Equal"`
+	// Value of the taint, when the operator is Equal.
+	Value string `yaml:"value,omitempty"`
+	// Effect of the taint to match.  Empty matches all effects.
+	Effect string `yaml:"effect,omitempty" jsonschema:"enum=NoSchedule,enum=PreferNoSchedule,enum=NoExecute"`
+	// TolerationSeconds the function tolerates a NoExecute taint before it
+	// is evicted.  Defaults to forever.
+	TolerationSeconds *int64 `yaml:"tolerationSeconds,omitempty"`
+}
+
+// Affinity of the function, as in Kubernetes.
+type Affinity struct {
+	NodeAffinity    *NodeAffinity `yaml:"nodeAffinity,omitempty"`
+	PodAffinity     *PodAffinity  `yaml:"podAffinity,omitempty"`
+	PodAntiAffinity *PodAffinity  `yaml:"podAntiAffinity,omitempty"`
+}
+
+// NodeAffinity schedules the function on nodes matching any of the required
+// terms, preferring those matching the heaviest preferred terms.
+type NodeAffinity struct {
+	Required  []NodeSelectorTerm        `yaml:"requiredDuringSchedulingIgnoredDuringExecution,omitempty"`
+	Preferred []PreferredSchedulingTerm `yaml:"preferredDuringSchedulingIgnoredDuringExecution,omitempty"`
+}
+
+// NodeSelectorTerm matches nodes matching all of its expressions.
+type NodeSelectorTerm struct {
+	MatchExpressions []SelectorRequirement `yaml:"matchExpressions"`
+}
+
+// PreferredSchedulingTerm is a node selector term with a weight from 1 to
+// 100.
+type PreferredSchedulingTerm struct {
+	Weight     int32            `yaml:"weight" jsonschema:"minimum=1,maximum=100"`
+	Preference NodeSelectorTerm `yaml:"preference"`
+}
+
+// PodAffinity schedules the function (or, as anti-affinity, avoids doing so)
+// in the same topology domain, such as a node or zone, as pods matching the
+// terms.
+type PodAffinity struct {
+	Required  []PodAffinityTerm         `yaml:"requiredDuringSchedulingIgnoredDuringExecution,omitempty"`
+	Preferred []WeightedPodAffinityTerm `yaml:"preferredDuringSchedulingIgnoredDuringExecution,omitempty"`
+}
+
+// PodAffinityTerm matches pods with the labels and expressions of its label
+// selector, in the function's namespace unless namespaces are given.
+type PodAffinityTerm struct {
+	LabelSelector LabelSelector `yaml:"labelSelector"`
+	Namespaces    []string      `yaml:"namespaces,omitempty"`
+	TopologyKey   string        `yaml:"topologyKey"`
+}
+
+// WeightedPodAffinityTerm is a pod affinity term with a weight from 1 to 100.
+type WeightedPodAffinityTerm struct {
+	Weight          int32           `yaml:"weight" jsonschema:"minimum=1,maximum=100"`
+	PodAffinityTerm PodAffinityTerm `yaml:"podAffinityTerm"`
+}
+
+// LabelSelector matches resources with all of its labels and expressions.
+type LabelSelector struct {
+	MatchLabels      map[string]string     `yaml:"matchLabels,omitempty"`
+	MatchExpressions []SelectorRequirement `yaml:"matchExpressions,omitempty"`
+}
+
+// SelectorRequirement relates the value of a label to a set of values.  The
+// operators Gt and Lt are only valid for node selectors.
+type SelectorRequirement struct {
+	Key      string   `yaml:"key"`
+	Operator string   `yaml:"operator" jsonschema:"enum=In,enum=NotIn,enum=Exists,enum=DoesNotExist,enum=Gt,enum=Lt"`
+	Values   []string `yaml:"values,omitempty"`
+}
+
+// validatePodOptions checks that the pod-level settings of the deploy spec
+// name valid resources and labels, and that tolerations and affinity terms
+// are well formed.
+// Returns array of error messages, empty if no errors are found
+func validatePodOptions(deploy DeploySpec) (errors []string) {
+	if deploy.ServiceAccountName != "" && !dns1123Subdomain.MatchString(deploy.ServiceAccountName) {
+		errors = append(errors, fmt.Sprintf("invalid service account name '%s'", deploy.ServiceAccountName))
+	}
+	for _, s := range deploy.ImagePullSecrets {
+		if !dns1123Subdomain.MatchString(s) {
+			errors = append(errors, fmt.Sprintf("invalid image pull secret name '%s'", s))
+		}
+	}
+	for k, v := range deploy.NodeSelector {
+		if err := utils.ValidateLabelKey(k); err != nil {
+			errors = append(errors, fmt.Sprintf("nodeSelector has an invalid key '%s': %s", k, err))
+		} else if err := utils.ValidateLabelValue(v); err != nil {
+			errors = append(errors, fmt.Sprintf("nodeSelector has an invalid value '%s' for key '%s': %s", v, k, err))
+		}
+	}
+	for i, t := range deploy.Tolerations {
+		errors = append(errors, validateToleration(i, t)...)
+	}
+	if deploy.Affinity != nil {
+		errors = append(errors, validateAffinity(*deploy.Affinity)...)
+	}
+	return
+}
+
+func validateToleration(i int, t Toleration) (errors []string) {
+	switch t.Operator {
+	case "", "Equal":
+		if t.Key == "" {
+			errors = append(errors, fmt.Sprintf("toleration at index %d must specify a key unless its operator is 'Exists'", i))
+		}
+	case "Exists":
+		if t.Value != "" {
+			errors = append(errors, fmt.Sprintf("toleration at index %d must not specify a value with the operator 'Exists'", i))
+		}
+	default:
+		errors = append(errors, fmt.Sprintf("toleration at index %d has an invalid operator '%s', must be 'Exists' or 'Equal'", i, t.Operator))
+	}
+	switch t.Effect {
+	case "", "NoSchedule", "PreferNoSchedule", "NoExecute":
+	default:
+		errors = append(errors, fmt.Sprintf("toleration at index %d has an invalid effect '%s', must be 'NoSchedule', 'PreferNoSchedule' or 'NoExecute'", i, t.Effect))
+	}
+	if t.TolerationSeconds != nil && t.Effect != "NoExecute" {
+		errors = append(errors, fmt.Sprintf("toleration at index %d may only specify tolerationSeconds with the effect 'NoExecute'", i))
+	}
+	return
+}
+
+func validateAffinity(a Affinity) (errors []string) {
+	if n := a.NodeAffinity; n != nil {
+		for i, term := range n.Required {
+			errors = append(errors, validateSelectorRequirements(fmt.Sprintf("required node affinity term at index %d", i), term.MatchExpressions, true)...)
+		}
+		for i, term := range n.Preferred {
+			name := fmt.Sprintf("preferred node affinity term at index %d", i)
+			errors = append(errors, validateWeight(name, term.Weight)...)
+			errors = append(errors, validateSelectorRequirements(name, term.Preference.MatchExpressions, true)...)
+		}
+	}
+	for _, p := range []struct {
+		kind string
+		*PodAffinity
+	}{{"pod affinity", a.PodAffinity}, {"pod anti-affinity", a.PodAntiAffinity}} {
+		if p.PodAffinity == nil {
+			continue
+		}
+		kind := p.kind
+		for i, term := range p.Required {
+			errors = append(errors, validatePodAffinityTerm(fmt.Sprintf("required %s term at index %d", kind, i), term)...)
+		}
+		for i, term := range p.Preferred {
+			name := fmt.Sprintf("preferred %s term at index %d", kind, i)
+			errors = append(errors, validateWeight(name, term.Weight)...)
+			errors = append(errors, validatePodAffinityTerm(name, term.PodAffinityTerm)...)
+		}
+	}
+	return
+}
+
+func validateWeight(name string, weight int32) (errors []string) {
+	if weight < 1 || weight > 100 {
+		errors = append(errors, fmt.Sprintf("%s has an invalid weight %d, must be from 1 to 100", name, weight))
+	}
+	return
+}
+
+func validatePodAffinityTerm(name string, term PodAffinityTerm) (errors []string) {
+	if term.TopologyKey == "" {
+		errors = append(errors, fmt.Sprintf("%s must specify a topologyKey", name))
+	}
+	for k, v := range term.LabelSelector.MatchLabels {
+		if err := utils.ValidateLabelKey(k); err != nil {
+			errors = append(errors, fmt.Sprintf("%s has an invalid label key '%s': %s", name, k, err))
+		} else if err := utils.ValidateLabelValue(v); err != nil {
+			errors = append(errors, fmt.Sprintf("%s has an invalid value '%s' for label key '%s': %s", name, v, k, err))
+		}
+	}
+	return append(errors, validateSelectorRequirements(name, term.LabelSelector.MatchExpressions, false)...)
+}
+
+// validateSelectorRequirements of a node selector term, which additionally
+// allows the operators Gt and Lt, or of a label selector.
+func validateSelectorRequirements(name string, requirements []SelectorRequirement, node bool) (errors []string) {
+	for _, r := range requirements {
+		if err := utils.ValidateLabelKey(r.Key); err != nil {
+			errors = append(errors, fmt.Sprintf("%s has an invalid key '%s': %s", name, r.Key, err))
+		}
+		switch r.Operator {
+		case "In", "NotIn":
+			if len(r.Values) == 0 {
+				errors = append(errors, fmt.Sprintf("%s must specify values for key '%s' with the operator '%s'", name, r.Key, r.Operator))
+			}
+		case "Exists", "DoesNotExist":
+			if len(r.Values) > 0 {
+				errors = append(errors, fmt.Sprintf("%s must not specify values for key '%s' with the operator '%s'", name, r.Key, r.Operator))
+			}
+		case "Gt", "Lt":
+			if !node {
+				errors = append(errors, fmt.Sprintf("%s has an invalid operator '%s' for key '%s', must be 'In', 'NotIn', 'Exists' or 'DoesNotExist'", name, r.Operator, r.Key))
+			} else if len(r.Values) != 1 {
+				errors = append(errors, fmt.Sprintf("%s must specify a single value for key '%s' with the operator '%s'", name, r.Key, r.Operator))
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("%s has an invalid operator '%s' for key '%s'", name, r.Operator, r.Key))
+		}
+	}
+	return
+}
@@ -0,0 +1,131 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"testing"
+
+	"knative.dev/pkg/ptr"
+)
+
+func Test_validatePodOptions(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		deploy DeploySpec
+		errs   int
+	}{
+		{
+			"correct entry - no pod options",
+			DeploySpec{},
+			0,
+		},
+		{
+			"correct entry - service account, pull secrets and node selector",
+			DeploySpec{
+				ServiceAccountName: "myfunc",
+				ImagePullSecrets:   []string{"myregistry"},
+				NodeSelector:       map[string]string{"cloud.google.com/gke-accelerator": "nvidia-tesla-t4"},
+			},
+			0,
+		},
+		{
+			"incorrect entry - invalid service account and pull secret",
+			DeploySpec{
+				ServiceAccountName: "My_Func",
+				ImagePullSecrets:   []string{"my registry"},
+			},
+			2,
+		},
+		{
+			"incorrect entry - invalid node selector",
+			DeploySpec{NodeSelector: map[string]string{"-accelerator": "gpu"}},
+			1,
+		},
+		{
+			"correct entry - tolerations",
+			DeploySpec{Tolerations: []Toleration{
+				{Key: "nvidia.com/gpu", Operator: "Exists", Effect: "NoSchedule"},
+				{Key: "dedicated", Value: "functions"},
+				{Operator: "Exists"},
+				{Key: "node.kubernetes.io/unreachable", Operator: "Exists", Effect: "NoExecute", TolerationSeconds: ptr.Int64(30)},
+			}},
+			0,
+		},
+		{
+			"incorrect entry - tolerations",
+			DeploySpec{Tolerations: []Toleration{
+				{Operator: "Equal", Value: "functions"},
+				{Key: "dedicated", Operator: "Exists", Value: "functions"},
+				{Key: "dedicated", Operator: "Is", Effect: "NoRun"},
+				{Key: "dedicated", Effect: "NoSchedule", TolerationSeconds: ptr.Int64(30)},
+			}},
+			5,
+		},
+		{
+			"correct entry - affinity",
+			DeploySpec{Affinity: &Affinity{
+				NodeAffinity: &NodeAffinity{
+					Required: []NodeSelectorTerm{{MatchExpressions: []SelectorRequirement{
+						{Key: "kubernetes.io/arch", Operator: "In", Values: []string{"amd64"}},
+						{Key: "gpu-count", Operator: "Gt", Values: []string{"1"}},
+					}}},
+					Preferred: []PreferredSchedulingTerm{{Weight: 50, Preference: NodeSelectorTerm{MatchExpressions: []SelectorRequirement{
+						{Key: "spot", Operator: "DoesNotExist"},
+					}}}},
+				},
+				PodAntiAffinity: &PodAffinity{
+					Preferred: []WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: PodAffinityTerm{
+						LabelSelector: LabelSelector{MatchLabels: map[string]string{"function.knative.dev/name": "myfunc"}},
+						TopologyKey:   "kubernetes.io/hostname",
+					}}},
+				},
+			}},
+			0,
+		},
+		{
+			"incorrect entry - node affinity",
+			DeploySpec{Affinity: &Affinity{
+				NodeAffinity: &NodeAffinity{
+					Required: []NodeSelectorTerm{{MatchExpressions: []SelectorRequirement{
+						{Key: "kubernetes.io/arch", Operator: "In"},
+						{Key: "gpu-count", Operator: "Gt", Values: []string{"1", "2"}},
+						{Key: "spot", Operator: "Exists", Values: []string{"true"}},
+					}}},
+					Preferred: []PreferredSchedulingTerm{{Weight: 0, Preference: NodeSelectorTerm{MatchExpressions: []SelectorRequirement{
+						{Key: "spot", Operator: "Is"},
+					}}}},
+				},
+			}},
+			5,
+		},
+		{
+			"incorrect entry - pod affinity",
+			DeploySpec{Affinity: &Affinity{
+				PodAffinity: &PodAffinity{
+					Required: []PodAffinityTerm{{
+						LabelSelector: LabelSelector{MatchExpressions: []SelectorRequirement{
+							{Key: "app", Operator: "Gt", Values: []string{"1"}},
+						}},
+					}},
+				},
+				PodAntiAffinity: &PodAffinity{
+					Preferred: []WeightedPodAffinityTerm{{Weight: 101, PodAffinityTerm: PodAffinityTerm{
+						LabelSelector: LabelSelector{MatchLabels: map[string]string{"app": "-myfunc"}},
+						TopologyKey:   "kubernetes.io/hostname",
+					}}},
+				},
+			}},
+			4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validatePodOptions(tt.deploy); len(got) != tt.errs {
+				t.Errorf("validatePodOptions() = %v\n got %d errors but want %d", got, len(got), tt.errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package function
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// ErrProfileNotFound indicates a requested profile is not defined by the
+// function.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// Profile is a named set of overrides which are overlaid onto the base
+// function configuration when the profile is selected.  For example:
+//
+//	profiles:
+//	  staging:
+//	    namespace: staging
+//	    registry: quay.io/alice-staging
+//	    envs:
+//	    - name: LOG_LEVEL
+//	      value: debug
+//	  prod:
+//	    namespace: prod
+//	    options:
+//	      scale:
+//	        min: 2
+//
+// Fields left unset inherit the value from the base configuration.
+type Profile struct {
+	// Registry overrides the function's registry.
+	Registry string `yaml:"registry,omitempty"`
+
+	// Namespace overrides deploy.namespace.
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Envs are merged into run.envs.  An env of the same name replaces the
+	// base value, others are appended.
+	Envs []Env `yaml:"envs,omitempty"`
+
+	// Options overrides deploy.options.  Scale, Resources and Timeouts are each
+	// replaced in their entirety when set.
+	Options *Options `yaml:"options,omitempty"`
+
+	// Labels are merged into deploy.labels by key.
+	Labels []Label `yaml:"labels,omitempty"`
+
+	// Annotations are merged into deploy.annotations.
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// ProfileNames returns the sorted names of all profiles defined by the
+// function.
+func (f Function) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile returns a copy of the function with the named profile overlaid
+// onto its base configuration.  The empty name is the base configuration
+// itself.  The function on which it is invoked is not modified, and the
+// result is intended to be used in-memory only (not written).
+func (f Function) ApplyProfile(name string) (Function, error) {
+	if name == "" {
+		return f, nil
+	}
+	p, ok := f.Profiles[name]
+	if !ok {
+		return f, fmt.Errorf("%w: '%v'", ErrProfileNotFound, name)
+	}
+
+	if p.Registry != "" {
+		f.Registry = p.Registry
+	}
+	if p.Namespace != "" {
+		f.Deploy.Namespace = p.Namespace
+	}
+	f.Run.Envs = mergeProfileEnvs(f.Run.Envs, p.Envs)
+	f.Deploy.Labels = mergeProfileLabels(f.Deploy.Labels, p.Labels)
+
+	if len(p.Annotations) > 0 {
+		annotations := make(map[string]string, len(f.Deploy.Annotations)+len(p.Annotations))
+		for k, v := range f.Deploy.Annotations {
+			annotations[k] = v
+		}
+		for k, v := range p.Annotations {
+			annotations[k] = v
+		}
+		f.Deploy.Annotations = annotations
+	}
+
+	if p.Options != nil {
+		if p.Options.Scale != nil {
+			f.Deploy.Options.Scale = p.Options.Scale
+		}
+		if p.Options.Resources != nil {
+			f.Deploy.Options.Resources = p.Options.Resources
+		}
+		if p.Options.Timeouts != nil {
+			f.Deploy.Options.Timeouts = p.Options.Timeouts
+		}
+	}
+	return f, nil
+}
+
+// mergeProfileEnvs returns a new slice of envs consisting of base with any
+// named envs in overrides replacing those of the same name, and the remainder
+// appended.
+func mergeProfileEnvs(base, overrides []Env) []Env {
+	if len(overrides) == 0 {
+		return base
+	}
+	merged := make([]Env, len(base))
+	copy(merged, base)
+	for _, o := range overrides {
+		replaced := false
+		if o.Name != nil {
+			for i, e := range merged {
+				if e.Name != nil && *e.Name == *o.Name {
+					merged[i] = o
+					replaced = true
+					break
+				}
+			}
+		}
+		if !replaced {
+			merged = append(merged, o)
+		}
+	}
+	return merged
+}
+
+// mergeProfileLabels returns a new slice of labels consisting of base with
+// any labels in overrides replacing those of the same key, and the remainder
+// appended.
+func mergeProfileLabels(base, overrides []Label) []Label {
+	if len(overrides) == 0 {
+		return base
+	}
+	merged := make([]Label, len(base))
+	copy(merged, base)
+	for _, o := range overrides {
+		replaced := false
+		if o.Key != nil {
+			for i, l := range merged {
+				if l.Key != nil && *l.Key == *o.Key {
+					merged[i] = o
+					replaced = true
+					break
+				}
+			}
+		}
+		if !replaced {
+			merged = append(merged, o)
+		}
+	}
+	return merged
+}
+
+var regProfileName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// validateProfiles checks that the profile names are valid and that the
+// overrides each contains are themselves valid.
+// Returns array of error messages, empty if no errors are found
+func validateProfiles(profiles map[string]Profile) (errors []string) {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !regProfileName.MatchString(name) {
+			errors = append(errors, fmt.Sprintf("profile name '%v' is invalid, it must consist of lower case alphanumeric characters or '-', and start and end with an alphanumeric character", name))
+		}
+		p := profiles[name]
+		var ee []string
+		ee = append(ee, ValidateEnvs(p.Envs)...)
+		ee = append(ee, ValidateLabels(p.Labels)...)
+		if p.Options != nil {
+			ee = append(ee, validateOptions(*p.Options, "")...)
+		}
+		for _, e := range ee {
+			errors = append(errors, fmt.Sprintf("profile '%v': %v", name, e))
+		}
+	}
+	return
+}
@@ -0,0 +1,139 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"knative.dev/pkg/ptr"
+)
+
+// TestApplyProfile ensures that the values of a profile are overlaid onto
+// the base function, that unset values are inherited, and that the base
+// function is not mutated.
+func TestApplyProfile(t *testing.T) {
+	f := Function{
+		Registry: "example.com/alice",
+		Run: RunSpec{
+			Envs: []Env{
+				{Name: ptr.String("A"), Value: ptr.String("base")},
+				{Name: ptr.String("B"), Value: ptr.String("base")},
+			},
+		},
+		Deploy: DeploySpec{
+			Namespace:   "dev",
+			Annotations: map[string]string{"a": "base", "b": "base"},
+			Labels:      []Label{{Key: ptr.String("tier"), Value: ptr.String("base")}},
+			Options:     Options{Scale: &ScaleOptions{Min: ptr.Int64(0)}},
+		},
+		Profiles: map[string]Profile{
+			"staging": {
+				Namespace:   "staging",
+				Envs:        []Env{{Name: ptr.String("B"), Value: ptr.String("staging")}, {Name: ptr.String("C"), Value: ptr.String("staging")}},
+				Annotations: map[string]string{"b": "staging"},
+				Labels:      []Label{{Key: ptr.String("tier"), Value: ptr.String("staging")}},
+				Options:     &Options{Scale: &ScaleOptions{Min: ptr.Int64(2)}, Timeouts: &TimeoutsOptions{Request: ptr.Int64(300), Idle: ptr.Int64(60)}},
+			},
+		},
+	}
+
+	pf, err := f.ApplyProfile("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pf.Deploy.Namespace != "staging" {
+		t.Fatalf("expected namespace 'staging', got '%v'", pf.Deploy.Namespace)
+	}
+	if pf.Registry != "example.com/alice" {
+		t.Fatalf("expected registry to be inherited, got '%v'", pf.Registry)
+	}
+	expectedEnvs := []Env{
+		{Name: ptr.String("A"), Value: ptr.String("base")},
+		{Name: ptr.String("B"), Value: ptr.String("staging")},
+		{Name: ptr.String("C"), Value: ptr.String("staging")},
+	}
+	if !reflect.DeepEqual(pf.Run.Envs, expectedEnvs) {
+		t.Fatalf("unexpected envs: %v", pf.Run.Envs)
+	}
+	expectedAnnotations := map[string]string{"a": "base", "b": "staging"}
+	if !reflect.DeepEqual(pf.Deploy.Annotations, expectedAnnotations) {
+		t.Fatalf("unexpected annotations: %v", pf.Deploy.Annotations)
+	}
+	if len(pf.Deploy.Labels) != 1 || *pf.Deploy.Labels[0].Value != "staging" {
+		t.Fatalf("unexpected labels: %v", pf.Deploy.Labels)
+	}
+	if *pf.Deploy.Options.Scale.Min != 2 {
+		t.Fatalf("expected scale.min 2, got %v", *pf.Deploy.Options.Scale.Min)
+	}
+	if pf.Deploy.Options.Timeouts == nil || *pf.Deploy.Options.Timeouts.Request != 300 || *pf.Deploy.Options.Timeouts.Idle != 60 {
+		t.Fatalf("expected the profile's timeouts, got %+v", pf.Deploy.Options.Timeouts)
+	}
+
+	// The base function should be unchanged
+	if f.Deploy.Namespace != "dev" || *f.Run.Envs[1].Value != "base" ||
+		f.Deploy.Annotations["b"] != "base" || *f.Deploy.Labels[0].Value != "base" ||
+		*f.Deploy.Options.Scale.Min != 0 || f.Deploy.Options.Timeouts != nil || len(f.Run.Envs) != 2 {
+		t.Fatalf("base function was mutated by applying a profile: %+v", f)
+	}
+
+	// The empty profile name is the base function
+	if pf, err = f.ApplyProfile(""); err != nil {
+		t.Fatal(err)
+	}
+	if pf.Deploy.Namespace != "dev" {
+		t.Fatalf("expected base namespace 'dev', got '%v'", pf.Deploy.Namespace)
+	}
+
+	// An undefined profile is an error
+	if _, err = f.ApplyProfile("prod"); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound, got %v", err)
+	}
+}
+
+func Test_validateProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles map[string]Profile
+		errs     int
+	}{
+		{
+			"correct entry - no profiles",
+			nil,
+			0,
+		},
+		{
+			"correct entry - valid profile",
+			map[string]Profile{"staging": {Namespace: "staging", Envs: []Env{{Name: ptr.String("A"), Value: ptr.String("a")}}}},
+			0,
+		},
+		{
+			"incorrect entry - invalid profile name",
+			map[string]Profile{"Staging_1": {}},
+			1,
+		},
+		{
+			"incorrect entry - invalid env",
+			map[string]Profile{"staging": {Envs: []Env{{Name: ptr.String("A")}}}},
+			1,
+		},
+		{
+			"incorrect entry - invalid label and options",
+			map[string]Profile{"prod": {
+				Labels:  []Label{{Value: ptr.String("v")}},
+				Options: &Options{Scale: &ScaleOptions{Min: ptr.Int64(-1)}},
+			}},
+			2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateProfiles(tt.profiles); len(got) != tt.errs {
+				t.Errorf("validateProfiles() = %v\n got %d errors but want %d", got, len(got), tt.errs)
+			}
+		})
+	}
+}
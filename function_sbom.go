@@ -0,0 +1,51 @@
+package function
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Formats of the software bill of materials (SBOM) of the image built, as
+// set by build.sbom.
+const (
+	SBOMFormatSPDX      = "spdx"
+	SBOMFormatCycloneDX = "cyclonedx"
+)
+
+// sbomDir is the name of the directory within the run data directory to
+// which the SBOM of the image built is written.
+const sbomDir = "sbom"
+
+// sbomFormats are the file name and media type of the SBOM document of each
+// format.
+var sbomFormats = map[string]struct{ file, mediaType string }{
+	SBOMFormatSPDX:      {"image.spdx.json", "application/spdx+json"},
+	SBOMFormatCycloneDX: {"image.cdx.json", "application/vnd.cyclonedx+json"},
+}
+
+// SBOMDir returns the path of the directory holding the SBOM of the image
+// most recently built, and any recorded by the buildpacks which built it.
+func (f Function) SBOMDir() string {
+	return filepath.Join(f.Root, RunDataDir, sbomDir)
+}
+
+// SBOMFile returns the path and media type of the SBOM document of the image
+// built in the format set by build.sbom, or empty strings if none is set.
+func (f Function) SBOMFile() (path, mediaType string) {
+	format, ok := sbomFormats[f.Build.SBOM]
+	if !ok {
+		return
+	}
+	return filepath.Join(f.SBOMDir(), format.file), format.mediaType
+}
+
+// validateSBOM checks that the SBOM format, if any, is supported.
+// Returns array of error messages, empty if no errors are found
+func validateSBOM(build BuildSpec) (errors []string) {
+	if _, ok := sbomFormats[build.SBOM]; build.SBOM != "" && !ok {
+		errors = append(errors, fmt.Sprintf("sbom format '%s' is not supported, expected one of: %s",
+			build.SBOM, strings.Join([]string{SBOMFormatSPDX, SBOMFormatCycloneDX}, ", ")))
+	}
+	return
+}
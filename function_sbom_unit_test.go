@@ -0,0 +1,57 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func Test_validateSBOM(t *testing.T) {
+	tests := []struct {
+		name  string
+		build BuildSpec
+		errs  int
+	}{
+		{
+			"correct - none",
+			BuildSpec{},
+			0,
+		},
+		{
+			"correct - spdx",
+			BuildSpec{SBOM: SBOMFormatSPDX},
+			0,
+		},
+		{
+			"correct - cyclonedx",
+			BuildSpec{SBOM: SBOMFormatCycloneDX},
+			0,
+		},
+		{
+			"incorrect - format",
+			BuildSpec{SBOM: "syft"},
+			1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateSBOM(tt.build); len(got) != tt.errs {
+				t.Errorf("validateSBOM() = %v\n got %d errors but want %d", got, len(got), tt.errs)
+			}
+		})
+	}
+}
+
+func TestFunction_SBOMFile(t *testing.T) {
+	f := Function{Root: "/func"}
+	if path, mediaType := f.SBOMFile(); path != "" || mediaType != "" {
+		t.Fatalf("expected no SBOM file by default, got %v (%v)", path, mediaType)
+	}
+	f.Build.SBOM = SBOMFormatCycloneDX
+	path, mediaType := f.SBOMFile()
+	if path != filepath.Join(f.SBOMDir(), "image.cdx.json") || mediaType != "application/vnd.cyclonedx+json" {
+		t.Fatalf("unexpected CycloneDX SBOM file %v (%v)", path, mediaType)
+	}
+}
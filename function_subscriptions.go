@@ -0,0 +1,112 @@
+package function
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"knative.dev/func/deployers"
+)
+
+// DefaultBroker is the broker from which a subscription receives events when
+// none is specified.
+const DefaultBroker = "default"
+
+// KnativeSubscription is a subscription of the function to the events of a
+// broker, which is realized as a Knative Trigger on deploy.  For example:
+//
+//	subscriptions:
+//	- broker: default
+//	  filters:
+//	    type: com.example.order.created
+//	  delivery:
+//	    retry: 3
+//	    backoffPolicy: exponential
+//	    backoffDelay: PT0.5S
+//	    deadLetterSink: order-failures
+type KnativeSubscription struct {
+	// Broker from which events are received.  Defaults to "default".
+	Broker string `yaml:"broker,omitempty"`
+
+	// Filters are CloudEvent attributes and the values to which they must be
+	// exactly equal for an event to be delivered.  All events of the broker
+	// are delivered if there are none.
+	Filters map[string]string `yaml:"filters,omitempty"`
+
+	// Delivery options for events of the subscription.
+	Delivery *SubscriptionDelivery `yaml:"delivery,omitempty"`
+}
+
+// SubscriptionDelivery options of a subscription.  Options left unset default
+// to those of the broker.
+type SubscriptionDelivery struct {
+	// Retry is the minimum number of retries of a failed delivery before the
+	// event is sent to the dead letter sink.
+	Retry *int32 `yaml:"retry,omitempty"`
+
+	// BackoffPolicy for retries: linear or exponential.
+	BackoffPolicy string `yaml:"backoffPolicy,omitempty" jsonschema:"enum=linear,enum=exponential"`
+
+	// BackoffDelay is the delay before retrying, as an ISO 8601 duration such
+	// as PT1S.
+	BackoffDelay string `yaml:"backoffDelay,omitempty"`
+
+	// DeadLetterSink to which events are sent which could not be delivered.
+	// Either an absolute URI or the name of a Knative Service in the function's
+	// namespace.
+	DeadLetterSink string `yaml:"deadLetterSink,omitempty"`
+}
+
+var (
+	// dns1123Label is a name of a Kubernetes resource such as a broker
+	dns1123Label = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	// attributeName is a CloudEvent context attribute name
+	attributeName = regexp.MustCompile(`^[a-z0-9]+$`)
+	// isoDuration is an ISO 8601 duration, or the empty duration "P"
+	isoDuration = regexp.MustCompile(`^P(\d+Y)?(\d+M)?(\d+W)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
+)
+
+// isISODuration returns true if the value is a non-empty ISO 8601 duration.
+func isISODuration(s string) bool {
+	return isoDuration.MatchString(s) && s != "P" && !strings.HasSuffix(s, "T")
+}
+
+// validateSubscriptions checks that the subscriptions name valid brokers,
+// filter attributes and delivery options, and that the deployer given, by
+// which Triggers are created, supports them.
+// Returns array of error messages, empty if no errors are found
+func validateSubscriptions(subscriptions []KnativeSubscription, deployer string) (errors []string) {
+	if len(subscriptions) > 0 && deployer == deployers.Kubernetes {
+		errors = append(errors, fmt.Sprintf("subscriptions can not be used with the '%s' deployer", deployers.Kubernetes))
+	}
+	for i, s := range subscriptions {
+		if s.Broker != "" && !dns1123Label.MatchString(s.Broker) {
+			errors = append(errors, fmt.Sprintf("subscription entry #%d has an invalid broker name '%s'", i, s.Broker))
+		}
+		for name := range s.Filters {
+			if !attributeName.MatchString(name) {
+				errors = append(errors, fmt.Sprintf("subscription entry #%d has an invalid filter attribute '%s', attribute names consist of lowercase letters and digits", i, name))
+			}
+		}
+		if s.Delivery == nil {
+			continue
+		}
+		d := s.Delivery
+		if d.Retry != nil && *d.Retry < 0 {
+			errors = append(errors, fmt.Sprintf("subscription entry #%d retry must not be negative", i))
+		}
+		if d.BackoffPolicy != "" && d.BackoffPolicy != "linear" && d.BackoffPolicy != "exponential" {
+			errors = append(errors, fmt.Sprintf("subscription entry #%d has an invalid backoffPolicy '%s', allowed are 'linear' and 'exponential'", i, d.BackoffPolicy))
+		}
+		if d.BackoffDelay != "" && !isISODuration(d.BackoffDelay) {
+			errors = append(errors, fmt.Sprintf("subscription entry #%d has an invalid backoffDelay '%s', expected an ISO 8601 duration such as PT1S", i, d.BackoffDelay))
+		}
+		if d.DeadLetterSink != "" {
+			if u, err := url.Parse(d.DeadLetterSink); (err != nil || !u.IsAbs()) && !dns1123Label.MatchString(d.DeadLetterSink) {
+				errors = append(errors, fmt.Sprintf("subscription entry #%d has an invalid deadLetterSink '%s', expected an absolute URI or a service name", i, d.DeadLetterSink))
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,100 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"testing"
+
+	"knative.dev/func/deployers"
+)
+
+func Test_validateSubscriptions(t *testing.T) {
+
+	retry := int32(3)
+	negative := int32(-1)
+
+	tests := []struct {
+		name          string
+		subscriptions []KnativeSubscription
+		errs          int
+	}{
+		{
+			"correct entry - default broker without filters",
+			[]KnativeSubscription{{}},
+			0,
+		},
+		{
+			"correct entry - broker, filters and delivery",
+			[]KnativeSubscription{
+				{
+					Broker:  "orders",
+					Filters: map[string]string{"type": "com.example.order.created", "source": "/orders"},
+					Delivery: &SubscriptionDelivery{
+						Retry:          &retry,
+						BackoffPolicy:  "exponential",
+						BackoffDelay:   "PT0.5S",
+						DeadLetterSink: "order-failures",
+					},
+				},
+			},
+			0,
+		},
+		{
+			"correct entry - dead letter sink URI",
+			[]KnativeSubscription{
+				{Delivery: &SubscriptionDelivery{DeadLetterSink: "http://failures.example.com/dls"}},
+			},
+			0,
+		},
+		{
+			"incorrect entry - invalid broker name",
+			[]KnativeSubscription{{Broker: "Orders_Broker"}},
+			1,
+		},
+		{
+			"incorrect entry - invalid filter attribute",
+			[]KnativeSubscription{{Filters: map[string]string{"event-type": "x"}}},
+			1,
+		},
+		{
+			"incorrect entry - invalid delivery options",
+			[]KnativeSubscription{
+				{
+					Delivery: &SubscriptionDelivery{
+						Retry:          &negative,
+						BackoffPolicy:  "random",
+						BackoffDelay:   "1s",
+						DeadLetterSink: "not a sink",
+					},
+				},
+			},
+			4,
+		},
+		{
+			"incorrect entry - empty backoff delay duration",
+			[]KnativeSubscription{{Delivery: &SubscriptionDelivery{BackoffDelay: "PT"}}},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateSubscriptions(tt.subscriptions, ""); len(got) != tt.errs {
+				t.Errorf("validateSubscriptions() = %v\n got %d errors but want %d", got, len(got), tt.errs)
+			}
+		})
+	}
+}
+
+// Test_validateSubscriptions_Kubernetes ensures that subscriptions are
+// rejected for the kubernetes deployer, which creates no Triggers.
+func Test_validateSubscriptions_Kubernetes(t *testing.T) {
+	subscriptions := []KnativeSubscription{{Filters: map[string]string{"type": "order.created"}}}
+	if errs := validateSubscriptions(subscriptions, deployers.Kubernetes); len(errs) != 1 {
+		t.Errorf("expected one error for the kubernetes deployer, got %v", errs)
+	}
+	if errs := validateSubscriptions(subscriptions, deployers.Knative); len(errs) != 0 {
+		t.Errorf("expected no errors for the knative deployer, got %v", errs)
+	}
+}
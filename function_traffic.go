@@ -0,0 +1,47 @@
+package function
+
+import (
+	"fmt"
+
+	"knative.dev/func/deployers"
+)
+
+// TrafficOptions of a deploy, which route a share of the function's traffic
+// to the revision being deployed, for example as a canary:
+//
+//	traffic:
+//	  tag: canary
+//	  percent: 10
+//
+// The remainder of the traffic stays with the revisions serving at the time
+// of the deploy, in proportion to their current shares.
+type TrafficOptions struct {
+	// Tag of the revision being deployed.  A tagged revision is reachable at
+	// its own URL regardless of its share of traffic.  A tag is moved to the
+	// newly deployed revision from the revision which previously had it.
+	Tag string `yaml:"tag,omitempty"`
+
+	// Percent of traffic routed to the revision being deployed.  Defaults to
+	// 100.
+	Percent *int64 `yaml:"percent,omitempty" jsonschema:"minimum=0,maximum=100"`
+}
+
+// validateTraffic checks that the traffic options name a valid tag and
+// percentage, and that the deployer given, which must split traffic between
+// revisions, supports them.
+// Returns array of error messages, empty if no errors are found
+func validateTraffic(traffic *TrafficOptions, deployer string) (errors []string) {
+	if traffic == nil {
+		return
+	}
+	if deployer == deployers.Kubernetes {
+		errors = append(errors, fmt.Sprintf("traffic can not be used with the '%s' deployer, which has no revisions", deployers.Kubernetes))
+	}
+	if traffic.Tag != "" && !dns1123Label.MatchString(traffic.Tag) {
+		errors = append(errors, fmt.Sprintf("traffic has an invalid tag '%s', tags consist of lowercase letters, digits and '-'", traffic.Tag))
+	}
+	if traffic.Percent != nil && (*traffic.Percent < 0 || *traffic.Percent > 100) {
+		errors = append(errors, fmt.Sprintf("traffic percent must be between 0 and 100, got %d", *traffic.Percent))
+	}
+	return
+}
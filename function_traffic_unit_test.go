@@ -0,0 +1,62 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"testing"
+
+	"knative.dev/func/deployers"
+	"knative.dev/pkg/ptr"
+)
+
+func Test_validateTraffic(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		traffic *TrafficOptions
+		errs    int
+	}{
+		{
+			"correct entry - no traffic options",
+			nil,
+			0,
+		},
+		{
+			"correct entry - tag and percent",
+			&TrafficOptions{Tag: "canary", Percent: ptr.Int64(10)},
+			0,
+		},
+		{
+			"incorrect entry - invalid tag",
+			&TrafficOptions{Tag: "Canary_1"},
+			1,
+		},
+		{
+			"incorrect entry - percent out of range",
+			&TrafficOptions{Percent: ptr.Int64(101)},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateTraffic(tt.traffic, ""); len(got) != tt.errs {
+				t.Errorf("validateTraffic() = %v\n got %d errors but want %d", got, len(got), tt.errs)
+			}
+		})
+	}
+}
+
+// Test_validateTraffic_Kubernetes ensures that traffic options are rejected
+// for the kubernetes deployer, which has no revisions between which to split
+// traffic.
+func Test_validateTraffic_Kubernetes(t *testing.T) {
+	traffic := &TrafficOptions{Tag: "canary", Percent: ptr.Int64(10)}
+	if errs := validateTraffic(traffic, deployers.Kubernetes); len(errs) != 1 {
+		t.Errorf("expected one error for the kubernetes deployer, got %v", errs)
+	}
+	if errs := validateTraffic(traffic, deployers.Knative); len(errs) != 0 {
+		t.Errorf("expected no errors for the knative deployer, got %v", errs)
+	}
+}
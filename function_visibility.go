@@ -0,0 +1,39 @@
+package function
+
+import (
+	"fmt"
+
+	"knative.dev/func/deployers"
+)
+
+const (
+	// VisibilityPublic functions are reachable from outside of the cluster.
+	// This is the default.
+	VisibilityPublic = "public"
+
+	// VisibilityClusterLocal functions are only reachable from within the
+	// cluster, such as those which only consume events.
+	VisibilityClusterLocal = "cluster-local"
+)
+
+// validateVisibility checks that the visibility is known, that a
+// cluster-local function is not also exposed by an ingress, and that it is not
+// set for the kubernetes deployer, whose functions are exposed by an ingress
+// alone.
+// Returns array of error messages, empty if no errors are found
+func validateVisibility(deploy DeploySpec) (errors []string) {
+	if deploy.Visibility != "" && deploy.Deployer == deployers.Kubernetes {
+		errors = append(errors, fmt.Sprintf("visibility can not be used with the '%s' deployer, whose functions are cluster-local unless an ingress is set", deployers.Kubernetes))
+		return
+	}
+	switch deploy.Visibility {
+	case "", VisibilityPublic:
+	case VisibilityClusterLocal:
+		if deploy.Ingress != nil {
+			errors = append(errors, fmt.Sprintf("visibility '%s' can not be used with an ingress, which exposes the function outside of the cluster", VisibilityClusterLocal))
+		}
+	default:
+		errors = append(errors, fmt.Sprintf("invalid visibility '%s', must be '%s' or '%s'", deploy.Visibility, VisibilityPublic, VisibilityClusterLocal))
+	}
+	return
+}
@@ -0,0 +1,68 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"testing"
+
+	"knative.dev/func/deployers"
+)
+
+func Test_validateVisibility(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		deploy DeploySpec
+		errs   int
+	}{
+		{
+			"correct entry - default visibility",
+			DeploySpec{},
+			0,
+		},
+		{
+			"correct entry - public with an ingress",
+			DeploySpec{Visibility: VisibilityPublic, Ingress: &IngressOptions{Host: "myfunc.example.com"}},
+			0,
+		},
+		{
+			"correct entry - cluster-local",
+			DeploySpec{Visibility: VisibilityClusterLocal},
+			0,
+		},
+		{
+			"incorrect entry - unknown visibility",
+			DeploySpec{Visibility: "private"},
+			1,
+		},
+		{
+			"incorrect entry - cluster-local with an ingress",
+			DeploySpec{Visibility: VisibilityClusterLocal, Ingress: &IngressOptions{Host: "myfunc.example.com"}},
+			1,
+		},
+		{
+			"incorrect entry - kubernetes deployer",
+			DeploySpec{Visibility: VisibilityClusterLocal, Deployer: deployers.Kubernetes},
+			1,
+		},
+		{
+			"incorrect entry - kubernetes deployer with an ingress",
+			DeploySpec{Visibility: VisibilityPublic, Deployer: deployers.Kubernetes, Ingress: &IngressOptions{Host: "myfunc.example.com"}},
+			1,
+		},
+		{
+			"correct entry - kubernetes deployer with default visibility",
+			DeploySpec{Deployer: deployers.Kubernetes},
+			0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateVisibility(tt.deploy); len(got) != tt.errs {
+				t.Errorf("validateVisibility() = %v\n got %d errors but want %d", got, len(got), tt.errs)
+			}
+		})
+	}
+}
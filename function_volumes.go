@@ -1,11 +1,54 @@
 package function
 
-import "fmt"
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
 
 type Volume struct {
-	Secret    *string `yaml:"secret,omitempty" jsonschema:"oneof_required=secret"`
-	ConfigMap *string `yaml:"configMap,omitempty" jsonschema:"oneof_required=configmap"`
-	Path      *string `yaml:"path"`
+	Secret                *string                `yaml:"secret,omitempty" jsonschema:"oneof_required=secret"`
+	ConfigMap             *string                `yaml:"configMap,omitempty" jsonschema:"oneof_required=configmap"`
+	PersistentVolumeClaim *PersistentVolumeClaim `yaml:"persistentVolumeClaim,omitempty" jsonschema:"oneof_required=persistentVolumeClaim"`
+	EmptyDir              *EmptyDir              `yaml:"emptyDir,omitempty" jsonschema:"oneof_required=emptyDir"`
+	ServiceAccountToken   *ServiceAccountToken   `yaml:"serviceAccountToken,omitempty" jsonschema:"oneof_required=serviceAccountToken"`
+	Path                  *string                `yaml:"path"`
+}
+
+// PersistentVolumeClaim mounted as a volume, such as for files shared among
+// the instances of a function.
+type PersistentVolumeClaim struct {
+	// ClaimName is the name of the PersistentVolumeClaim in the function's
+	// namespace.
+	ClaimName *string `yaml:"claimName,omitempty"`
+	// ReadOnly mounts the volume read-only.
+	ReadOnly bool `yaml:"readOnly,omitempty"`
+}
+
+// EmptyDir is a volume which is empty when an instance of the function
+// starts, and is removed when it stops, such as for scratch space.
+type EmptyDir struct {
+	// Name shares the volume among the function and its sidecar and init
+	// containers, each of which mounts the entry of the same name.  Unnamed
+	// entries are distinct volumes.
+	Name string `yaml:"name,omitempty"`
+	// Medium backing the volume.  Defaults to that of the node, and can be
+	// set to "Memory" for a tmpfs.
+	Medium string `yaml:"medium,omitempty" jsonschema:"enum=,enum=Memory"`
+	// SizeLimit of the volume, such as "1Gi".
+	SizeLimit *string `yaml:"sizeLimit,omitempty" jsonschema:"pattern=^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$"`
+}
+
+// ServiceAccountToken is a projected volume containing a token of the
+// function's service account, such as for authenticating to other services.
+type ServiceAccountToken struct {
+	// Audience of the token.  Defaults to that of the cluster's API server.
+	Audience string `yaml:"audience,omitempty"`
+	// ExpirationSeconds after which the token expires.  The token is
+	// refreshed before it expires.  Defaults to an hour.
+	ExpirationSeconds *int64 `yaml:"expirationSeconds,omitempty" jsonschema_extras:"minimum=600"`
+	// File name of the token within the volume.  Defaults to "token".
+	File string `yaml:"file,omitempty"`
 }
 
 func (v Volume) String() string {
@@ -13,11 +56,41 @@ func (v Volume) String() string {
 		return fmt.Sprintf("ConfigMap \"%s\" mounted at path: \"%s\"", *v.ConfigMap, *v.Path)
 	} else if v.Secret != nil {
 		return fmt.Sprintf("Secret \"%s\" mounted at path: \"%s\"", *v.Secret, *v.Path)
+	} else if v.PersistentVolumeClaim != nil && v.PersistentVolumeClaim.ClaimName != nil {
+		readOnly := ""
+		if v.PersistentVolumeClaim.ReadOnly {
+			readOnly = " (read-only)"
+		}
+		return fmt.Sprintf("PersistentVolumeClaim \"%s\" mounted at path: \"%s\"%s", *v.PersistentVolumeClaim.ClaimName, *v.Path, readOnly)
+	} else if v.EmptyDir != nil {
+		return fmt.Sprintf("EmptyDir mounted at path: \"%s\"", *v.Path)
+	} else if v.ServiceAccountToken != nil {
+		return fmt.Sprintf("ServiceAccountToken mounted at path: \"%s\"", *v.Path)
 	}
 
 	return ""
 }
 
+// sources of the volume which are set, as names for use in messages.
+func (v Volume) sources() (sources []string) {
+	if v.Secret != nil {
+		sources = append(sources, fmt.Sprintf("secret '%s'", *v.Secret))
+	}
+	if v.ConfigMap != nil {
+		sources = append(sources, fmt.Sprintf("configMap '%s'", *v.ConfigMap))
+	}
+	if v.PersistentVolumeClaim != nil {
+		sources = append(sources, "persistentVolumeClaim")
+	}
+	if v.EmptyDir != nil {
+		sources = append(sources, "emptyDir")
+	}
+	if v.ServiceAccountToken != nil {
+		sources = append(sources, "serviceAccountToken")
+	}
+	return
+}
+
 // validateVolumes checks that input Volumes are correct and contain all necessary fields.
 // Returns array of error messages, empty if no errors are found
 //
@@ -26,24 +99,55 @@ func (v Volume) String() string {
 //     path: /etc/secret-volume
 //   - configMap: example-configMap              	# mount ConfigMap as Volume
 //     path: /etc/configMap-volume
+//   - persistentVolumeClaim:                      # mount PersistentVolumeClaim as Volume
+//     claimName: example-pvc
+//     readOnly: true
+//     path: /etc/pvc-volume
+//   - emptyDir:                                   # mount an empty directory as Volume
+//     medium: Memory
+//     sizeLimit: 1Gi
+//     path: /tmp/scratch
+//   - serviceAccountToken:                        # mount a service account token as Volume
+//     audience: example.com
+//     expirationSeconds: 3600
+//     path: /var/run/secrets/tokens
 func validateVolumes(volumes []Volume) (errors []string) {
 
 	for i, vol := range volumes {
-		if vol.Secret != nil && vol.ConfigMap != nil {
-			errors = append(errors, fmt.Sprintf("volume entry #%d is not properly set, both secret '%s' and configMap '%s' can not be set at the same time",
-				i, *vol.Secret, *vol.ConfigMap))
-		} else if vol.Path == nil && vol.Secret == nil && vol.ConfigMap == nil {
+		sources := vol.sources()
+		if len(sources) > 1 {
+			errors = append(errors, fmt.Sprintf("volume entry #%d is not properly set, only one of %v can be set", i, sources))
+		} else if vol.Path == nil && len(sources) == 0 {
 			errors = append(errors, fmt.Sprintf("volume entry #%d is not properly set", i))
 		} else if vol.Path == nil {
-			if vol.Secret != nil {
-				errors = append(errors, fmt.Sprintf("volume entry #%d is missing path field, only secret '%s' is set", i, *vol.Secret))
-			} else if vol.ConfigMap != nil {
-				errors = append(errors, fmt.Sprintf("volume entry #%d is missing path field, only configMap '%s' is set", i, *vol.ConfigMap))
+			errors = append(errors, fmt.Sprintf("volume entry #%d is missing path field, only %s is set", i, sources[0]))
+		} else if len(sources) == 0 {
+			errors = append(errors, fmt.Sprintf("volume entry #%d is missing secret, configMap, persistentVolumeClaim, emptyDir or serviceAccountToken field, only path '%s' is set", i, *vol.Path))
+		} else if vol.PersistentVolumeClaim != nil && (vol.PersistentVolumeClaim.ClaimName == nil || *vol.PersistentVolumeClaim.ClaimName == "") {
+			errors = append(errors, fmt.Sprintf("volume entry #%d is missing claimName field of the persistentVolumeClaim", i))
+		} else if vol.EmptyDir != nil {
+			errors = append(errors, validateEmptyDir(i, *vol.EmptyDir)...)
+		} else if vol.ServiceAccountToken != nil {
+			if e := vol.ServiceAccountToken.ExpirationSeconds; e != nil && *e < 600 {
+				errors = append(errors, fmt.Sprintf("volume entry #%d has invalid serviceAccountToken expirationSeconds: %d, the value must be at least \"600\"", i, *e))
 			}
-		} else if vol.Path != nil && vol.Secret == nil && vol.ConfigMap == nil {
-			errors = append(errors, fmt.Sprintf("volume entry #%d is missing secret or configMap field, only path '%s' is set", i, *vol.Path))
 		}
 	}
 
 	return
 }
+
+func validateEmptyDir(i int, emptyDir EmptyDir) (errors []string) {
+	if emptyDir.Name != "" && !dns1123Label.MatchString(emptyDir.Name) {
+		errors = append(errors, fmt.Sprintf("volume entry #%d has invalid emptyDir name: \"%s\"", i, emptyDir.Name))
+	}
+	if emptyDir.Medium != "" && emptyDir.Medium != "Memory" {
+		errors = append(errors, fmt.Sprintf("volume entry #%d has invalid emptyDir medium: \"%s\", the value must be \"Memory\" or unset", i, emptyDir.Medium))
+	}
+	if emptyDir.SizeLimit != nil {
+		if _, err := resource.ParseQuantity(*emptyDir.SizeLimit); err != nil {
+			errors = append(errors, fmt.Sprintf("volume entry #%d has invalid emptyDir sizeLimit: \"%s\"", i, *emptyDir.SizeLimit))
+		}
+	}
+	return
+}
@@ -14,6 +14,12 @@ func Test_validateVolumes(t *testing.T) {
 	secret2 := "secret2"
 	path2 := "path2"
 	cm := "configMap"
+	pvc := "pvc"
+	medium := "Memory"
+	sizeLimit := "1Gi"
+	invalidSizeLimit := "a lot"
+	expiration := int64(3600)
+	shortExpiration := int64(60)
 
 	tests := []struct {
 		name    string
@@ -77,6 +83,75 @@ func Test_validateVolumes(t *testing.T) {
 			},
 			0,
 		},
+		{
+			"correct entry - volumes with persistentVolumeClaim, emptyDir and serviceAccountToken",
+			[]Volume{
+				{
+					PersistentVolumeClaim: &PersistentVolumeClaim{ClaimName: &pvc, ReadOnly: true},
+					Path:                  &path,
+				},
+				{
+					EmptyDir: &EmptyDir{Medium: medium, SizeLimit: &sizeLimit},
+					Path:     &path2,
+				},
+				{
+					ServiceAccountToken: &ServiceAccountToken{Audience: "example.com", ExpirationSeconds: &expiration},
+					Path:                &path2,
+				},
+			},
+			0,
+		},
+		{
+			"incorrect entry - both secret and emptyDir",
+			[]Volume{
+				{
+					Secret:   &secret,
+					EmptyDir: &EmptyDir{},
+					Path:     &path,
+				},
+			},
+			1,
+		},
+		{
+			"incorrect entry - persistentVolumeClaim without claimName",
+			[]Volume{
+				{
+					PersistentVolumeClaim: &PersistentVolumeClaim{},
+					Path:                  &path,
+				},
+			},
+			1,
+		},
+		{
+			"incorrect entry - emptyDir with invalid medium and sizeLimit",
+			[]Volume{
+				{
+					EmptyDir: &EmptyDir{Medium: "Disk", SizeLimit: &invalidSizeLimit},
+					Path:     &path,
+				},
+			},
+			2,
+		},
+		{
+			"incorrect entry - emptyDir with invalid name",
+			[]Volume{
+				{
+					EmptyDir: &EmptyDir{Name: "Models"},
+					Path:     &path,
+				},
+			},
+			1,
+		},
+		{
+			"incorrect entry - serviceAccountToken expiring too soon",
+			[]Volume{
+				{
+					ServiceAccountToken: &ServiceAccountToken{ExpirationSeconds: &shortExpiration},
+					Path:                &path,
+				},
+			},
+			1,
+		},
 		{
 			"missing secret/configMap - single volume",
 			[]Volume{
@@ -157,6 +232,7 @@ func Test_validateVolumesString(t *testing.T) {
 	path := "path"
 
 	cm := "configMap"
+	pvc := "pvc"
 
 	tests := []struct {
 		key    string
@@ -179,6 +255,22 @@ func Test_validateVolumesString(t *testing.T) {
 			},
 			"ConfigMap \"configMap\" mounted at path: \"path\"",
 		},
+		{
+			"volume with read-only persistentVolumeClaim and path",
+			Volume{
+				PersistentVolumeClaim: &PersistentVolumeClaim{ClaimName: &pvc, ReadOnly: true},
+				Path:                  &path,
+			},
+			"PersistentVolumeClaim \"pvc\" mounted at path: \"path\" (read-only)",
+		},
+		{
+			"volume with emptyDir and path",
+			Volume{
+				EmptyDir: &EmptyDir{},
+				Path:     &path,
+			},
+			"EmptyDir mounted at path: \"path\"",
+		},
 		{
 			//@TODO:this is and edge case that we are not covering
 			"volume with no configMap and no secret but with path",
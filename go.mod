@@ -17,6 +17,7 @@ require (
 	github.com/docker/docker v20.10.18+incompatible
 	github.com/docker/docker-credential-helpers v0.6.4
 	github.com/docker/go-connections v0.4.0
+	github.com/fsnotify/fsnotify v1.6.0
 	github.com/go-git/go-billy/v5 v5.3.1
 	github.com/go-git/go-git/v5 v5.4.2
 	github.com/google/go-cmp v0.5.9
@@ -51,9 +52,13 @@ require (
 	knative.dev/hack v0.0.0-20221104013908-8f3c7050408b
 	knative.dev/pkg v0.0.0-20221107171117-0243d641354d
 	knative.dev/serving v0.35.1-0.20221114131921-874ccebb8063
+	sigs.k8s.io/yaml v1.3.0
 )
 
-require github.com/sabhiram/go-gitignore v0.0.0-20210923224102-525f6e181f06
+require (
+	github.com/sabhiram/go-gitignore v0.0.0-20210923224102-525f6e181f06
+	knative.dev/networking v0.0.0-20221104155004-0d4e93709170
+)
 
 require (
 	cloud.google.com/go/compute v1.10.0 // indirect
@@ -114,7 +119,6 @@ require (
 	github.com/evanphx/json-patch v5.6.0+incompatible // indirect
 	github.com/evanphx/json-patch/v5 v5.6.0 // indirect
 	github.com/fatih/color v1.13.0 // indirect
-	github.com/fsnotify/fsnotify v1.6.0 // indirect
 	github.com/gdamore/encoding v1.0.0 // indirect
 	github.com/gdamore/tcell/v2 v2.5.1 // indirect
 	github.com/go-errors/errors v1.4.2 // indirect
@@ -232,12 +236,10 @@ require (
 	k8s.io/cli-runtime v0.25.2 // indirect
 	k8s.io/klog/v2 v2.70.2-0.20220707122935-0990e81f1a8f // indirect
 	k8s.io/kube-openapi v0.0.0-20220803162953-67bda5d908f1 // indirect
-	knative.dev/networking v0.0.0-20221104155004-0d4e93709170 // indirect
 	sigs.k8s.io/json v0.0.0-20220713155537-f223a00ba0e2 // indirect
 	sigs.k8s.io/kustomize/api v0.12.1 // indirect
 	sigs.k8s.io/kustomize/kyaml v0.13.9 // indirect
 	sigs.k8s.io/structured-merge-diff/v4 v4.2.3 // indirect
-	sigs.k8s.io/yaml v1.3.0 // indirect
 )
 
 replace (
@@ -99,6 +99,8 @@ func invoke(ctx context.Context, c *Client, f Function, target string, m InvokeM
 // 'local': local environment; locally running function (error if not running)
 // 'remote': remote environment; first available instance (error if none)
 // '<environment>': A valid alternate target which contains instances.
+// '<tag>': The tagged revision of the remote instance, such as a canary, if
+// one is tagged as such.
 // '<url>': An explicit URL
 // ”: Default if no target is passed is to first use local, then remote.
 //
@@ -141,11 +143,32 @@ func invocationRoute(ctx context.Context, c *Client, f Function, target string)
 			return "", err // unexpected error
 		}
 		return instance.Route, nil
+	} else if route, ok := taggedRoute(ctx, c, f, target); ok {
+		return route, nil
 	} else { // treat an unrecognized target as an ad-hoc verbatim endpoint
 		return target, nil
 	}
 }
 
+// taggedRoute returns the route to the revision of the remote instance whose
+// traffic is tagged with target, if any.  A target which tags none, such as a
+// bare host name, is not a tag.
+func taggedRoute(ctx context.Context, c *Client, f Function, target string) (string, bool) {
+	if !dns1123Label.MatchString(target) {
+		return "", false
+	}
+	instance, err := c.Instances().Get(ctx, f, EnvironmentRemote)
+	if err != nil {
+		return "", false
+	}
+	for _, t := range instance.Traffic {
+		if t.Tag == target && t.URL != "" {
+			return t.URL, true
+		}
+	}
+	return "", false
+}
+
 // sendEvent to the route populated with data in the invoke message.
 func sendEvent(ctx context.Context, route string, m InvokeMessage, t http.RoundTripper, verbose bool) (resp string, err error) {
 	event := cloudevents.NewEvent()
@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fn "knative.dev/func"
+)
+
+// ProcessContainers generates the sidecar and init containers of the deploy
+// spec, returning them along with the volumes of the pod: those given, of the
+// function's container, and those mounted by the containers.  Volumes shared
+// among containers, such as those of the same Secret or of a named emptyDir,
+// are included once.
+func ProcessContainers(deploy fn.DeploySpec, volumes []corev1.Volume, referencedSecrets, referencedConfigMaps *sets.String) (sidecars, initContainers []corev1.Container, podVolumes []corev1.Volume, err error) {
+	podVolumes = volumes
+	if sidecars, podVolumes, err = processContainers(deploy.Sidecars, podVolumes, referencedSecrets, referencedConfigMaps); err != nil {
+		return
+	}
+	initContainers, podVolumes, err = processContainers(deploy.InitContainers, podVolumes, referencedSecrets, referencedConfigMaps)
+	return
+}
+
+func processContainers(containers []fn.Container, volumes []corev1.Volume, referencedSecrets, referencedConfigMaps *sets.String) ([]corev1.Container, []corev1.Volume, error) {
+	var result []corev1.Container
+	for _, c := range containers {
+		container := corev1.Container{
+			Name:    c.Name,
+			Image:   c.Image,
+			Command: c.Command,
+			Args:    c.Args,
+		}
+
+		var err error
+		container.Env, container.EnvFrom, err = ProcessEnvs(c.Envs, referencedSecrets, referencedConfigMaps)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var containerVolumes []corev1.Volume
+		containerVolumes, container.VolumeMounts, err = processVolumes(c.Volumes, c.Name+"-", referencedSecrets, referencedConfigMaps)
+		if err != nil {
+			return nil, nil, err
+		}
+		volumes = appendVolumes(volumes, containerVolumes)
+
+		if container.Resources, err = ResourceRequirements(c.Resources); err != nil {
+			return nil, nil, err
+		}
+		result = append(result, container)
+	}
+	return result, volumes, nil
+}
+
+// appendVolumes appends those volumes not already among the given volumes.
+func appendVolumes(volumes, more []corev1.Volume) []corev1.Volume {
+	names := sets.NewString()
+	for _, v := range volumes {
+		names.Insert(v.Name)
+	}
+	for _, v := range more {
+		if !names.Has(v.Name) {
+			volumes = append(volumes, v)
+			names.Insert(v.Name)
+		}
+	}
+	return volumes
+}
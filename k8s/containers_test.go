@@ -0,0 +1,88 @@
+//go:build !integration
+// +build !integration
+
+package k8s
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/ptr"
+
+	fn "knative.dev/func"
+)
+
+func TestProcessContainers(t *testing.T) {
+	deploy := fn.DeploySpec{
+		Sidecars: []fn.Container{{
+			Name:  "proxy",
+			Image: "proxy:1.0",
+			Args:  []string{"--port=5432"},
+			Envs:  []fn.Env{{Name: ptr.String("TOKEN"), Value: ptr.String("{{ secret:proxy:token }}")}},
+			Volumes: []fn.Volume{
+				{Secret: ptr.String("proxy"), Path: ptr.String("/etc/proxy")},
+				{EmptyDir: &fn.EmptyDir{}, Path: ptr.String("/tmp")},
+			},
+			Resources: &fn.ResourcesOptions{Limits: &fn.ResourcesLimitsOptions{Memory: ptr.String("64Mi")}},
+		}},
+		InitContainers: []fn.Container{{
+			Name:    "fetch",
+			Image:   "alpine",
+			Command: []string{"sh", "-c", "wget -O /models/model.bin https://example.com/model.bin"},
+			Volumes: []fn.Volume{{EmptyDir: &fn.EmptyDir{Name: "models"}, Path: ptr.String("/models")}},
+		}},
+	}
+	secrets, configMaps := sets.NewString(), sets.NewString()
+
+	// The function's container mounts the shared emptyDir and its own.
+	volumes, _, err := ProcessVolumes([]fn.Volume{
+		{EmptyDir: &fn.EmptyDir{Name: "models"}, Path: ptr.String("/models")},
+		{EmptyDir: &fn.EmptyDir{}, Path: ptr.String("/tmp")},
+	}, &secrets, &configMaps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sidecars, initContainers, volumes, err := ProcessContainers(deploy, volumes, &secrets, &configMaps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sidecars) != 1 || sidecars[0].Name != "proxy" || sidecars[0].Image != "proxy:1.0" || sidecars[0].Args[0] != "--port=5432" {
+		t.Fatalf("unexpected sidecars %+v", sidecars)
+	}
+	var token bool
+	for _, e := range sidecars[0].Env {
+		token = token || (e.Name == "TOKEN" && e.ValueFrom.SecretKeyRef.Name == "proxy")
+	}
+	if !token {
+		t.Errorf("expected the sidecar env from the secret, got %+v", sidecars[0].Env)
+	}
+	if sidecars[0].Resources.Limits.Memory().String() != "64Mi" {
+		t.Errorf("unexpected sidecar resources %+v", sidecars[0].Resources)
+	}
+	if len(initContainers) != 1 || initContainers[0].Command[0] != "sh" {
+		t.Fatalf("unexpected init containers %+v", initContainers)
+	}
+	if m := initContainers[0].VolumeMounts; len(m) != 1 || m[0].Name != "empty-dir-models" {
+		t.Errorf("expected the init container to mount the shared emptyDir, got %+v", m)
+	}
+
+	var names []string
+	for _, v := range volumes {
+		names = append(names, v.Name)
+	}
+	expected := []string{"empty-dir-models", "empty-dir-1", "secret-proxy", "proxy-empty-dir-1"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected volumes %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("expected volumes %v, got %v", expected, names)
+		}
+	}
+
+	if !secrets.Has("proxy") {
+		t.Errorf("expected the secret of the sidecar to be referenced")
+	}
+}
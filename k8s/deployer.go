@@ -0,0 +1,874 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s/labels"
+)
+
+const (
+	DefaultLivenessEndpoint  = "/health/liveness"
+	DefaultReadinessEndpoint = "/health/readiness"
+
+	// DefaultWaitingTimeout for the Deployment of a function to become ready.
+	DefaultWaitingTimeout = 120 * time.Second
+
+	// DeployerName is the value of the deployer label of the resources of
+	// functions deployed by the Deployer.
+	DeployerName = "kubernetes"
+
+	// containerPort on which functions listen for requests.
+	containerPort = 8080
+
+	// defaultUtilization is the CPU utilization targeted when autoscaling,
+	// unless set by the function's scale options.
+	defaultUtilization = 80
+)
+
+type DeployerOpt func(*Deployer)
+
+// Deployer of functions as a plain Kubernetes Deployment and Service, for
+// clusters without Knative Serving.  The function is optionally exposed by
+// an Ingress, and autoscaled by a HorizontalPodAutoscaler when its scale
+// options allow more than its minimum number of replicas.
+type Deployer struct {
+	// Namespace with which to override that set on the default configuration (such as the ~/.kube/config).
+	// If left blank, deployment will commence to the configured namespace.
+	Namespace string
+	// verbose logging enablement flag.
+	verbose bool
+}
+
+func NewDeployer(opts ...DeployerOpt) *Deployer {
+	d := &Deployer{}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+func WithDeployerNamespace(namespace string) DeployerOpt {
+	return func(d *Deployer) {
+		d.Namespace = namespace
+	}
+}
+
+func WithDeployerVerbose(verbose bool) DeployerOpt {
+	return func(d *Deployer) {
+		d.verbose = verbose
+	}
+}
+
+func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResult, error) {
+	var err error
+	if d.Namespace == "" {
+		d.Namespace, err = GetNamespace(d.Namespace)
+		if err != nil {
+			return fn.DeploymentResult{}, err
+		}
+	}
+
+	client, err := NewKubernetesClientset()
+	if err != nil {
+		return fn.DeploymentResult{}, err
+	}
+
+	objects, err := generateObjects(f, d.Namespace)
+	if err != nil {
+		return fn.DeploymentResult{}, fmt.Errorf("kubernetes deployer failed to generate the Deployment: %v", err)
+	}
+
+	err = CheckSecretsConfigMapsArePresent(ctx, d.Namespace, &objects.secrets, &objects.configMaps)
+	if err != nil {
+		return fn.DeploymentResult{}, fmt.Errorf("kubernetes deployer failed to generate the Deployment: %v", err)
+	}
+
+	status, err := applyDeployment(ctx, client, objects.deployment, objects.autoscaler != nil)
+	if err != nil {
+		return fn.DeploymentResult{}, fmt.Errorf("kubernetes deployer failed to deploy the Deployment: %v", err)
+	}
+	if err = applyService(ctx, client, objects.service); err != nil {
+		return fn.DeploymentResult{}, fmt.Errorf("kubernetes deployer failed to deploy the Service: %v", err)
+	}
+	if err = applyIngress(ctx, client, f.Name, d.Namespace, objects.ingress); err != nil {
+		return fn.DeploymentResult{}, fmt.Errorf("kubernetes deployer failed to deploy the Ingress: %v", err)
+	}
+	if err = applyAutoscaler(ctx, client, f.Name, d.Namespace, objects.autoscaler); err != nil {
+		return fn.DeploymentResult{}, fmt.Errorf("kubernetes deployer failed to deploy the HorizontalPodAutoscaler: %v", err)
+	}
+
+	if d.verbose {
+		fmt.Println("Waiting for Deployment to become ready")
+	}
+	err = wait.PollImmediateWithContext(ctx, time.Second, DefaultWaitingTimeout, func(ctx context.Context) (bool, error) {
+		deployment, err := client.AppsV1().Deployments(d.Namespace).Get(ctx, f.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return deploymentReady(deployment), nil
+	})
+	if err != nil {
+		return fn.DeploymentResult{}, fmt.Errorf("kubernetes deployer failed to wait for the Deployment to become ready: %v", err)
+	}
+
+	url := serviceURL(f.Name, d.Namespace, f.Deploy.Ingress)
+	if d.verbose {
+		fmt.Printf("Function deployed in namespace %q and exposed at URL:\n%s\n", d.Namespace, url)
+	}
+	return fn.DeploymentResult{
+		Status:    status,
+		URL:       url,
+		Namespace: d.Namespace,
+	}, nil
+}
+
+// applyDeployment creates or updates the Deployment.  When the Deployment is
+// autoscaled, the number of replicas of an existing Deployment is left to
+// the autoscaler.
+func applyDeployment(ctx context.Context, client kubernetes.Interface, deployment *appsv1.Deployment, autoscaled bool) (fn.Status, error) {
+	deployments := client.AppsV1().Deployments(deployment.Namespace)
+	existing, err := deployments.Get(ctx, deployment.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = deployments.Create(ctx, deployment, metav1.CreateOptions{})
+		return fn.Deployed, err
+	} else if err != nil {
+		return fn.Failed, err
+	}
+	deployment.ResourceVersion = existing.ResourceVersion
+	if autoscaled {
+		deployment.Spec.Replicas = existing.Spec.Replicas
+	}
+	_, err = deployments.Update(ctx, deployment, metav1.UpdateOptions{})
+	return fn.Updated, err
+}
+
+// applyService creates or updates the Service, retaining the cluster IP
+// allocated to an existing Service.
+func applyService(ctx context.Context, client kubernetes.Interface, service *corev1.Service) error {
+	services := client.CoreV1().Services(service.Namespace)
+	existing, err := services.Get(ctx, service.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = services.Create(ctx, service, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+	service.ResourceVersion = existing.ResourceVersion
+	service.Spec.ClusterIP = existing.Spec.ClusterIP
+	service.Spec.ClusterIPs = existing.Spec.ClusterIPs
+	_, err = services.Update(ctx, service, metav1.UpdateOptions{})
+	return err
+}
+
+// applyIngress creates or updates the Ingress of the function, or deletes an
+// existing Ingress if none is wanted.
+func applyIngress(ctx context.Context, client kubernetes.Interface, name, namespace string, ingress *networkingv1.Ingress) error {
+	ingresses := client.NetworkingV1().Ingresses(namespace)
+	existing, err := ingresses.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err) && ingress == nil:
+		return nil
+	case errors.IsNotFound(err):
+		_, err = ingresses.Create(ctx, ingress, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	case ingress == nil:
+		return ignoreNotFound(ingresses.Delete(ctx, name, metav1.DeleteOptions{}))
+	}
+	ingress.ResourceVersion = existing.ResourceVersion
+	_, err = ingresses.Update(ctx, ingress, metav1.UpdateOptions{})
+	return err
+}
+
+// applyAutoscaler creates or updates the HorizontalPodAutoscaler of the
+// function, or deletes an existing autoscaler if none is wanted.
+func applyAutoscaler(ctx context.Context, client kubernetes.Interface, name, namespace string, autoscaler *autoscalingv2.HorizontalPodAutoscaler) error {
+	autoscalers := client.AutoscalingV2().HorizontalPodAutoscalers(namespace)
+	existing, err := autoscalers.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err) && autoscaler == nil:
+		return nil
+	case errors.IsNotFound(err):
+		_, err = autoscalers.Create(ctx, autoscaler, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	case autoscaler == nil:
+		return ignoreNotFound(autoscalers.Delete(ctx, name, metav1.DeleteOptions{}))
+	}
+	autoscaler.ResourceVersion = existing.ResourceVersion
+	_, err = autoscalers.Update(ctx, autoscaler, metav1.UpdateOptions{})
+	return err
+}
+
+func ignoreNotFound(err error) error {
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// deploymentReady returns true when the current generation of the
+// Deployment has been rolled out and all of its replicas are available.
+func deploymentReady(d *appsv1.Deployment) bool {
+	if d.Generation > d.Status.ObservedGeneration {
+		return false
+	}
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.UpdatedReplicas >= replicas &&
+		d.Status.Replicas == d.Status.UpdatedReplicas &&
+		d.Status.AvailableReplicas >= replicas
+}
+
+// serviceURL at which the function is reachable: its ingress host if it
+// has one, otherwise the cluster-local address of its Service.
+func serviceURL(name, namespace string, ingress *fn.IngressOptions) string {
+	if ingress != nil && ingress.Host != "" {
+		if ingress.TLSSecret != "" {
+			return "https://" + ingress.Host
+		}
+		return "http://" + ingress.Host
+	}
+	return fmt.Sprintf("http://%v.%v.svc.cluster.local", name, namespace)
+}
+
+// objects with which a function is deployed, and the Secrets and ConfigMaps
+// which they reference.
+type objects struct {
+	deployment *appsv1.Deployment
+	service    *corev1.Service
+	ingress    *networkingv1.Ingress
+	autoscaler *autoscalingv2.HorizontalPodAutoscaler
+	secrets    sets.String
+	configMaps sets.String
+}
+
+// generateObjects with which the function is deployed to the namespace.
+func generateObjects(f fn.Function, namespace string) (o objects, err error) {
+	o.secrets = sets.NewString()
+	o.configMaps = sets.NewString()
+
+	container := corev1.Container{
+		Name:  "user-container",
+		Image: f.ImageWithDigest(),
+		Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: containerPort, Protocol: corev1.ProtocolTCP}},
+	}
+	container.LivenessProbe = probeFor(f.Deploy.HealthEndpoints.Liveness, DefaultLivenessEndpoint)
+	SetProbeOptions(container.LivenessProbe, f.Deploy.HealthEndpoints.Probes.Liveness)
+	container.ReadinessProbe = probeFor(f.Deploy.HealthEndpoints.Readiness, DefaultReadinessEndpoint)
+	SetProbeOptions(container.ReadinessProbe, f.Deploy.HealthEndpoints.Probes.Readiness)
+	if f.Deploy.HealthEndpoints.Startup != "" {
+		container.StartupProbe = probeFor(f.Deploy.HealthEndpoints.Startup, "")
+		SetProbeOptions(container.StartupProbe, f.Deploy.HealthEndpoints.Probes.Startup)
+	}
+
+	container.Env, container.EnvFrom, err = ProcessEnvs(f.Run.Envs, &o.secrets, &o.configMaps)
+	if err != nil {
+		return
+	}
+	volumes, volumeMounts, err := ProcessVolumes(f.Run.Volumes, &o.secrets, &o.configMaps)
+	if err != nil {
+		return
+	}
+	container.VolumeMounts = volumeMounts
+	if container.Resources, err = ResourceRequirements(f.Deploy.Options.Resources); err != nil {
+		return
+	}
+	sidecars, initContainers, volumes, err := ProcessContainers(f.Deploy, volumes, &o.secrets, &o.configMaps)
+	if err != nil {
+		return
+	}
+
+	functionLabels, err := f.LabelsMap()
+	if err != nil {
+		return
+	}
+	functionLabels[labels.DeployerKey] = DeployerName
+	selector := map[string]string{
+		labels.FunctionNameKey: f.Name,
+		labels.DeployerKey:     DeployerName,
+	}
+	meta := metav1.ObjectMeta{
+		Name:        f.Name,
+		Namespace:   namespace,
+		Labels:      functionLabels,
+		Annotations: f.Deploy.Annotations,
+	}
+
+	minReplicas, maxReplicas, utilization := scaleOf(f.Deploy.Options.Scale)
+	o.deployment = &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: appsv1.SchemeGroupVersion.String(), Kind: "Deployment"},
+		ObjectMeta: meta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &minReplicas,
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      functionLabels,
+					Annotations: f.Deploy.Annotations,
+				},
+				Spec: corev1.PodSpec{
+					Containers:     append([]corev1.Container{container}, sidecars...),
+					InitContainers: initContainers,
+					Volumes:        volumes,
+				},
+			},
+		},
+	}
+	SetPodOptions(&o.deployment.Spec.Template.Spec, f.Deploy)
+
+	o.service = &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "Service"},
+		ObjectMeta: meta,
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       80,
+				TargetPort: intstr.FromInt(containerPort),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+
+	if ingress := f.Deploy.Ingress; ingress != nil {
+		pathType := networkingv1.PathTypePrefix
+		o.ingress = &networkingv1.Ingress{
+			TypeMeta:   metav1.TypeMeta{APIVersion: networkingv1.SchemeGroupVersion.String(), Kind: "Ingress"},
+			ObjectMeta: meta,
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{{
+					Host: ingress.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: &pathType,
+							Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+								Name: f.Name,
+								Port: networkingv1.ServiceBackendPort{Name: "http"},
+							}},
+						}},
+					}},
+				}},
+			},
+		}
+		if ingress.ClassName != "" {
+			o.ingress.Spec.IngressClassName = &ingress.ClassName
+		}
+		if ingress.TLSSecret != "" {
+			o.ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{ingress.Host}, SecretName: ingress.TLSSecret}}
+		}
+	}
+
+	if maxReplicas > minReplicas {
+		o.autoscaler = &autoscalingv2.HorizontalPodAutoscaler{
+			TypeMeta:   metav1.TypeMeta{APIVersion: autoscalingv2.SchemeGroupVersion.String(), Kind: "HorizontalPodAutoscaler"},
+			ObjectMeta: meta,
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+					APIVersion: appsv1.SchemeGroupVersion.String(),
+					Kind:       "Deployment",
+					Name:       f.Name,
+				},
+				MinReplicas: &minReplicas,
+				MaxReplicas: maxReplicas,
+				Metrics: []autoscalingv2.MetricSpec{{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name: corev1.ResourceCPU,
+						Target: autoscalingv2.MetricTarget{
+							Type:               autoscalingv2.UtilizationMetricType,
+							AverageUtilization: &utilization,
+						},
+					},
+				}},
+			},
+		}
+	}
+	return
+}
+
+// scaleOf a function as the minimum and maximum number of replicas of its
+// Deployment, and the CPU utilization targeted when autoscaling between them.
+// As a Deployment can not scale to zero, it has at least one replica.  The
+// scale metric and target are specific to Knative, and are not used.
+func scaleOf(scale *fn.ScaleOptions) (min, max, utilization int32) {
+	min, max, utilization = 1, 1, defaultUtilization
+	if scale == nil {
+		return
+	}
+	if scale.Min != nil && *scale.Min > 1 {
+		min = int32(*scale.Min)
+	}
+	max = min
+	if scale.Max != nil && int32(*scale.Max) > min {
+		max = int32(*scale.Max)
+	}
+	if scale.Utilization != nil {
+		utilization = int32(*scale.Utilization)
+	}
+	return
+}
+
+// probeFor the endpoint, or the default endpoint if none is given, on the
+// port on which the function listens.
+func probeFor(endpoint, defaultEndpoint string) *corev1.Probe {
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: endpoint,
+				Port: intstr.FromInt(containerPort),
+			},
+		},
+	}
+}
+
+// SetProbeOptions sets the timings of the probe which are set in the options,
+// leaving the others to default to those of the cluster.
+func SetProbeOptions(p *corev1.Probe, o fn.ProbeOptions) {
+	p.InitialDelaySeconds = o.InitialDelaySeconds
+	p.PeriodSeconds = o.PeriodSeconds
+	p.TimeoutSeconds = o.TimeoutSeconds
+	p.FailureThreshold = o.FailureThreshold
+}
+
+// ResourceRequirements of a container from the function's resource options.
+// The concurrency limit is specific to Knative, and is not used.
+func ResourceRequirements(options *fn.ResourcesOptions) (r corev1.ResourceRequirements, err error) {
+	if options == nil {
+		return
+	}
+	if options.Requests != nil {
+		if r.Requests, err = resourceList(options.Requests.CPU, options.Requests.Memory); err != nil {
+			return
+		}
+	}
+	if options.Limits != nil {
+		if r.Limits, err = resourceList(options.Limits.CPU, options.Limits.Memory); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func resourceList(cpu, memory *string) (corev1.ResourceList, error) {
+	list := corev1.ResourceList{}
+	if cpu != nil {
+		value, err := resource.ParseQuantity(*cpu)
+		if err != nil {
+			return nil, err
+		}
+		list[corev1.ResourceCPU] = value
+	}
+	if memory != nil {
+		value, err := resource.ParseQuantity(*memory)
+		if err != nil {
+			return nil, err
+		}
+		list[corev1.ResourceMemory] = value
+	}
+	return list, nil
+}
+
+// ProcessEnvs generates array of EnvVars and EnvFromSources from a function config
+// envs:
+//   - name: EXAMPLE1                            # ENV directly from a value
+//     value: value1
+//   - name: EXAMPLE2                            # ENV from the local ENV var
+//     value: {{ env:MY_ENV }}
+//   - name: EXAMPLE3
+//     value: {{ secret:example-secret:key }}    # ENV from a key in Secret
+//   - value: {{ secret:example-secret }}        # all ENVs from Secret
+//   - name: EXAMPLE4
+//     value: {{ configMap:configMapName:key }}  # ENV from a key in ConfigMap
+//   - value: {{ configMap:configMapName }}      # all key-pair values from ConfigMap are set as ENV
+func ProcessEnvs(envs []fn.Env, referencedSecrets, referencedConfigMaps *sets.String) ([]corev1.EnvVar, []corev1.EnvFromSource, error) {
+
+	envVars := []corev1.EnvVar{{Name: "BUILT", Value: time.Now().Format("20060102T150405")}}
+	envFrom := []corev1.EnvFromSource{}
+
+	for _, env := range envs {
+		if env.Name == nil && env.Value != nil {
+			// all key-pair values from secret/configMap are set as ENV, eg. {{ secret:secretName }} or {{ configMap:configMapName }}
+			if strings.HasPrefix(*env.Value, "{{") {
+				envFromSource, err := createEnvFromSource(*env.Value, referencedSecrets, referencedConfigMaps)
+				if err != nil {
+					return nil, nil, err
+				}
+				envFrom = append(envFrom, *envFromSource)
+				continue
+			}
+		} else if env.Name != nil && env.Value != nil {
+			if strings.HasPrefix(*env.Value, "{{") {
+				slices := strings.Split(strings.Trim(*env.Value, "{} "), ":")
+				if len(slices) == 3 {
+					// ENV from a key in secret/configMap, eg. FOO={{ secret:secretName:key }} FOO={{ configMap:configMapName.key }}
+					valueFrom, err := createEnvVarSource(slices, referencedSecrets, referencedConfigMaps)
+					envVars = append(envVars, corev1.EnvVar{Name: *env.Name, ValueFrom: valueFrom})
+					if err != nil {
+						return nil, nil, err
+					}
+					continue
+				} else if len(slices) == 2 {
+					// ENV from the local ENV var, eg. FOO={{ env:LOCAL_ENV }}
+					localValue, err := processLocalEnvValue(*env.Value)
+					if err != nil {
+						return nil, nil, err
+					}
+					envVars = append(envVars, corev1.EnvVar{Name: *env.Name, Value: localValue})
+					continue
+				}
+			} else {
+				// a standard ENV with key and value, eg. FOO=bar
+				envVars = append(envVars, corev1.EnvVar{Name: *env.Name, Value: *env.Value})
+				continue
+			}
+		}
+		return nil, nil, fmt.Errorf("unsupported env source entry \"%v\"", env)
+	}
+
+	return envVars, envFrom, nil
+}
+
+func createEnvFromSource(value string, referencedSecrets, referencedConfigMaps *sets.String) (*corev1.EnvFromSource, error) {
+	slices := strings.Split(strings.Trim(value, "{} "), ":")
+	if len(slices) != 2 {
+		return nil, fmt.Errorf("env requires a value in form \"resourceType:name\" where \"resourceType\" can be one of \"configMap\" or \"secret\"; got %q", slices)
+	}
+
+	envVarSource := corev1.EnvFromSource{}
+
+	typeString := strings.TrimSpace(slices[0])
+	sourceName := strings.TrimSpace(slices[1])
+
+	var sourceType string
+
+	switch typeString {
+	case "configMap":
+		sourceType = "ConfigMap"
+		envVarSource.ConfigMapRef = &corev1.ConfigMapEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: sourceName,
+			}}
+
+		if !referencedConfigMaps.Has(sourceName) {
+			referencedConfigMaps.Insert(sourceName)
+		}
+	case "secret":
+		sourceType = "Secret"
+		envVarSource.SecretRef = &corev1.SecretEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: sourceName,
+			}}
+		if !referencedSecrets.Has(sourceName) {
+			referencedSecrets.Insert(sourceName)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported env source type %q; supported source types are \"configMap\" or \"secret\"", slices[0])
+	}
+
+	if len(sourceName) == 0 {
+		return nil, fmt.Errorf("the name of %s cannot be an empty string", sourceType)
+	}
+
+	return &envVarSource, nil
+}
+
+func createEnvVarSource(slices []string, referencedSecrets, referencedConfigMaps *sets.String) (*corev1.EnvVarSource, error) {
+
+	if len(slices) != 3 {
+		return nil, fmt.Errorf("env requires a value in form \"resourceType:name:key\" where \"resourceType\" can be one of \"configMap\" or \"secret\"; got %q", slices)
+	}
+
+	envVarSource := corev1.EnvVarSource{}
+
+	typeString := strings.TrimSpace(slices[0])
+	sourceName := strings.TrimSpace(slices[1])
+	sourceKey := strings.TrimSpace(slices[2])
+
+	var sourceType string
+
+	switch typeString {
+	case "configMap":
+		sourceType = "ConfigMap"
+		envVarSource.ConfigMapKeyRef = &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: sourceName,
+			},
+			Key: sourceKey}
+
+		if !referencedConfigMaps.Has(sourceName) {
+			referencedConfigMaps.Insert(sourceName)
+		}
+	case "secret":
+		sourceType = "Secret"
+		envVarSource.SecretKeyRef = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: sourceName,
+			},
+			Key: sourceKey}
+
+		if !referencedSecrets.Has(sourceName) {
+			referencedSecrets.Insert(sourceName)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported env source type %q; supported source types are \"configMap\" or \"secret\"", slices[0])
+	}
+
+	if len(sourceName) == 0 {
+		return nil, fmt.Errorf("the name of %s cannot be an empty string", sourceType)
+	}
+
+	if len(sourceKey) == 0 {
+		return nil, fmt.Errorf("the key referenced by resource %s %q cannot be an empty string", sourceType, sourceName)
+	}
+
+	return &envVarSource, nil
+}
+
+var evRegex = regexp.MustCompile(`^{{\s*(\w+)\s*:(\w+)\s*}}$`)
+
+const (
+	ctxIdx = 1
+	valIdx = 2
+)
+
+func processLocalEnvValue(val string) (string, error) {
+	match := evRegex.FindStringSubmatch(val)
+	if len(match) > valIdx {
+		if match[ctxIdx] != "env" {
+			return "", fmt.Errorf("allowed env value entry is \"{{ env:LOCAL_VALUE }}\"; got: %q", match[ctxIdx])
+		}
+		if v, ok := os.LookupEnv(match[valIdx]); ok {
+			return v, nil
+		} else {
+			return "", fmt.Errorf("required local environment variable %q is not set", match[valIdx])
+		}
+	} else {
+		return val, nil
+	}
+}
+
+// ProcessVolumes generates Volumes and VolumeMounts from a function config
+// volumes:
+//   - secret: example-secret               # mount Secret as Volume
+//     path: /etc/secret-volume
+//   - configMap: example-cm                # mount ConfigMap as Volume
+//     path: /etc/cm-volume
+//   - persistentVolumeClaim:               # mount PersistentVolumeClaim as Volume
+//     claimName: example-pvc
+//     path: /etc/pvc-volume
+//   - emptyDir: {}                         # mount an empty directory as Volume
+//     path: /tmp/scratch
+//   - serviceAccountToken: {}              # mount a projected service account token as Volume
+//     path: /var/run/secrets/tokens
+func ProcessVolumes(volumes []fn.Volume, referencedSecrets, referencedConfigMaps *sets.String) ([]corev1.Volume, []corev1.VolumeMount, error) {
+	return processVolumes(volumes, "", referencedSecrets, referencedConfigMaps)
+}
+
+// ReferencedClaims returns the sorted names of the PersistentVolumeClaims
+// mounted by the function and its sidecars and init containers, which, as are
+// its Secrets and ConfigMaps, are expected to exist at the time of deployment.
+func ReferencedClaims(f fn.Function) []string {
+	claims := sets.NewString()
+	insert := func(volumes []fn.Volume) {
+		for _, vol := range volumes {
+			if vol.PersistentVolumeClaim != nil && vol.PersistentVolumeClaim.ClaimName != nil {
+				claims.Insert(*vol.PersistentVolumeClaim.ClaimName)
+			}
+		}
+	}
+	insert(f.Run.Volumes)
+	for _, c := range append(append([]fn.Container{}, f.Deploy.Sidecars...), f.Deploy.InitContainers...) {
+		insert(c.Volumes)
+	}
+	return claims.List()
+}
+
+// processVolumes generates Volumes and VolumeMounts of a container, with the
+// names of the volumes which are specific to the container, such as unnamed
+// emptyDir volumes, prefixed such that they are unique within the pod.
+func processVolumes(volumes []fn.Volume, prefix string, referencedSecrets, referencedConfigMaps *sets.String) ([]corev1.Volume, []corev1.VolumeMount, error) {
+
+	createdVolumes := sets.NewString()
+	usedPaths := sets.NewString()
+
+	newVolumes := []corev1.Volume{}
+	newVolumeMounts := []corev1.VolumeMount{}
+
+	for i, vol := range volumes {
+
+		volumeName := ""
+
+		if vol.Secret != nil {
+			volumeName = "secret-" + *vol.Secret
+
+			if !createdVolumes.Has(volumeName) {
+				newVolumes = append(newVolumes, corev1.Volume{
+					Name: volumeName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: *vol.Secret,
+						},
+					},
+				})
+				createdVolumes.Insert(volumeName)
+
+				if !referencedSecrets.Has(*vol.Secret) {
+					referencedSecrets.Insert(*vol.Secret)
+				}
+			}
+		} else if vol.ConfigMap != nil {
+			volumeName = "config-map-" + *vol.ConfigMap
+
+			if !createdVolumes.Has(volumeName) {
+				newVolumes = append(newVolumes, corev1.Volume{
+					Name: volumeName,
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: *vol.ConfigMap,
+							},
+						},
+					},
+				})
+				createdVolumes.Insert(volumeName)
+
+				if !referencedConfigMaps.Has(*vol.ConfigMap) {
+					referencedConfigMaps.Insert(*vol.ConfigMap)
+				}
+			}
+		} else if vol.PersistentVolumeClaim != nil && vol.PersistentVolumeClaim.ClaimName != nil {
+			volumeName = "pvc-" + *vol.PersistentVolumeClaim.ClaimName
+			if vol.PersistentVolumeClaim.ReadOnly {
+				volumeName += "-ro"
+			}
+
+			if !createdVolumes.Has(volumeName) {
+				newVolumes = append(newVolumes, corev1.Volume{
+					Name: volumeName,
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: *vol.PersistentVolumeClaim.ClaimName,
+							ReadOnly:  vol.PersistentVolumeClaim.ReadOnly,
+						},
+					},
+				})
+				createdVolumes.Insert(volumeName)
+			}
+		} else if vol.EmptyDir != nil {
+			// Each unnamed entry is a distinct volume, named by its position
+			// such that the name is the same on each deploy.
+			volumeName = fmt.Sprintf("%sempty-dir-%d", prefix, i)
+			if vol.EmptyDir.Name != "" {
+				volumeName = "empty-dir-" + vol.EmptyDir.Name
+			}
+
+			emptyDir := &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMedium(vol.EmptyDir.Medium)}
+			if vol.EmptyDir.SizeLimit != nil {
+				sizeLimit, err := resource.ParseQuantity(*vol.EmptyDir.SizeLimit)
+				if err != nil {
+					return nil, nil, fmt.Errorf("invalid emptyDir sizeLimit %q: %w", *vol.EmptyDir.SizeLimit, err)
+				}
+				emptyDir.SizeLimit = &sizeLimit
+			}
+			if !createdVolumes.Has(volumeName) {
+				newVolumes = append(newVolumes, corev1.Volume{
+					Name:         volumeName,
+					VolumeSource: corev1.VolumeSource{EmptyDir: emptyDir},
+				})
+				createdVolumes.Insert(volumeName)
+			}
+		} else if vol.ServiceAccountToken != nil {
+			volumeName = fmt.Sprintf("%ssa-token-%d", prefix, i)
+
+			file := vol.ServiceAccountToken.File
+			if file == "" {
+				file = "token"
+			}
+			newVolumes = append(newVolumes, corev1.Volume{
+				Name: volumeName,
+				VolumeSource: corev1.VolumeSource{
+					Projected: &corev1.ProjectedVolumeSource{
+						Sources: []corev1.VolumeProjection{{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								Audience:          vol.ServiceAccountToken.Audience,
+								ExpirationSeconds: vol.ServiceAccountToken.ExpirationSeconds,
+								Path:              file,
+							},
+						}},
+					},
+				},
+			})
+			createdVolumes.Insert(volumeName)
+		}
+
+		if volumeName != "" {
+			if !usedPaths.Has(*vol.Path) {
+				newVolumeMounts = append(newVolumeMounts, corev1.VolumeMount{
+					Name:      volumeName,
+					MountPath: *vol.Path,
+					ReadOnly:  vol.PersistentVolumeClaim != nil && vol.PersistentVolumeClaim.ReadOnly,
+				})
+				usedPaths.Insert(*vol.Path)
+			} else {
+				return nil, nil, fmt.Errorf("mount path %s is defined multiple times", *vol.Path)
+			}
+		}
+	}
+
+	return newVolumes, newVolumeMounts, nil
+}
+
+// CheckSecretsConfigMapsArePresent returns error if Secrets or ConfigMaps
+// referenced in input sets are not deployed on the cluster in the specified namespace
+func CheckSecretsConfigMapsArePresent(ctx context.Context, namespace string, referencedSecrets, referencedConfigMaps *sets.String) error {
+
+	errMsg := ""
+	for s := range *referencedSecrets {
+		_, err := GetSecret(ctx, s, namespace)
+		if err != nil {
+			errMsg += fmt.Sprintf("  referenced Secret \"%s\" is not present in namespace \"%s\"\n", s, namespace)
+		}
+	}
+
+	for cm := range *referencedConfigMaps {
+		_, err := GetConfigMap(ctx, cm, namespace)
+		if err != nil {
+			errMsg += fmt.Sprintf("  referenced ConfigMap \"%s\" is not present in namespace \"%s\"\n", cm, namespace)
+		}
+	}
+
+	if errMsg != "" {
+		return fmt.Errorf("\n" + errMsg)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+//go:build !integration
+// +build !integration
+
+package k8s
+
+import (
+	"os"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/ptr"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s/labels"
+)
+
+func Test_processValue(t *testing.T) {
+	testEnvVarOld, testEnvVarOldExists := os.LookupEnv("TEST_K8S_DEPLOYER")
+	os.Setenv("TEST_K8S_DEPLOYER", "VALUE_FOR_TEST_K8S_DEPLOYER")
+	defer func() {
+		if testEnvVarOldExists {
+			os.Setenv("TEST_K8S_DEPLOYER", testEnvVarOld)
+		} else {
+			os.Unsetenv("TEST_K8S_DEPLOYER")
+		}
+	}()
+
+	unsetVarOld, unsetVarOldExists := os.LookupEnv("UNSET_VAR")
+	os.Unsetenv("UNSET_VAR")
+	defer func() {
+		if unsetVarOldExists {
+			os.Setenv("UNSET_VAR", unsetVarOld)
+		}
+	}()
+
+	tests := []struct {
+		name    string
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{name: "simple value", arg: "A_VALUE", want: "A_VALUE", wantErr: false},
+		{name: "using envvar value", arg: "{{ env:TEST_K8S_DEPLOYER }}", want: "VALUE_FOR_TEST_K8S_DEPLOYER", wantErr: false},
+		{name: "bad context", arg: "{{secret:S}}", want: "", wantErr: true},
+		{name: "unset envvar", arg: "{{env:SOME_UNSET_VAR}}", want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processLocalEnvValue(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("processValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("processValue() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_generateObjects(t *testing.T) {
+	f := fn.Function{
+		Name:    "myfunc",
+		Runtime: "go",
+		Image:   "example.com/alice/myfunc@sha256:0123",
+		Deploy: fn.DeploySpec{
+			Options: fn.Options{
+				Scale: &fn.ScaleOptions{Min: ptr.Int64(2), Max: ptr.Int64(5), Utilization: ptr.Float64(50)},
+				Resources: &fn.ResourcesOptions{
+					Requests: &fn.ResourcesRequestsOptions{CPU: ptr.String("100m")},
+					Limits:   &fn.ResourcesLimitsOptions{Memory: ptr.String("256Mi"), Concurrency: ptr.Int64(10)},
+				},
+			},
+			Ingress: &fn.IngressOptions{Host: "myfunc.example.com", ClassName: "nginx", TLSSecret: "myfunc-tls"},
+			HealthEndpoints: fn.HealthEndpoints{
+				Startup: "/started",
+				Probes:  fn.Probes{Startup: fn.ProbeOptions{PeriodSeconds: 5, FailureThreshold: 60}},
+			},
+		},
+		Run: fn.RunSpec{
+			Envs: []fn.Env{{Name: ptr.String("API_KEY"), Value: ptr.String("{{ secret:credentials:key }}")}},
+		},
+	}
+
+	o, err := generateObjects(f, "ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := o.deployment
+	if d.Namespace != "ns" || d.Labels[labels.DeployerKey] != DeployerName {
+		t.Errorf("unexpected metadata of the Deployment %+v", d.ObjectMeta)
+	}
+	if *d.Spec.Replicas != 2 {
+		t.Errorf("expected 2 replicas, got %v", *d.Spec.Replicas)
+	}
+	for k, v := range d.Spec.Selector.MatchLabels {
+		if d.Spec.Template.Labels[k] != v || o.service.Spec.Selector[k] != v {
+			t.Errorf("expected the pods and Service to be selected by %v=%v", k, v)
+		}
+	}
+	c := d.Spec.Template.Spec.Containers[0]
+	if c.Image != f.Image {
+		t.Errorf("unexpected image %v", c.Image)
+	}
+	if c.ReadinessProbe.HTTPGet.Path != DefaultReadinessEndpoint || c.ReadinessProbe.HTTPGet.Port.IntValue() != containerPort {
+		t.Errorf("unexpected readiness probe %+v", c.ReadinessProbe.HTTPGet)
+	}
+	if c.StartupProbe == nil || c.StartupProbe.HTTPGet.Path != "/started" || c.StartupProbe.FailureThreshold != 60 {
+		t.Errorf("unexpected startup probe %+v", c.StartupProbe)
+	}
+	if !c.Resources.Requests.Cpu().Equal(resource.MustParse("100m")) || !c.Resources.Limits.Memory().Equal(resource.MustParse("256Mi")) {
+		t.Errorf("unexpected resources %+v", c.Resources)
+	}
+	if !o.secrets.Has("credentials") {
+		t.Errorf("expected the Secret 'credentials' to be referenced, got %v", o.secrets.List())
+	}
+
+	if o.service.Spec.Ports[0].TargetPort.IntValue() != containerPort {
+		t.Errorf("unexpected Service ports %+v", o.service.Spec.Ports)
+	}
+
+	if o.ingress == nil {
+		t.Fatal("expected an Ingress")
+	}
+	if *o.ingress.Spec.IngressClassName != "nginx" || o.ingress.Spec.Rules[0].Host != "myfunc.example.com" || o.ingress.Spec.TLS[0].SecretName != "myfunc-tls" {
+		t.Errorf("unexpected Ingress %+v", o.ingress.Spec)
+	}
+
+	if o.autoscaler == nil {
+		t.Fatal("expected a HorizontalPodAutoscaler")
+	}
+	if *o.autoscaler.Spec.MinReplicas != 2 || o.autoscaler.Spec.MaxReplicas != 5 || *o.autoscaler.Spec.Metrics[0].Resource.Target.AverageUtilization != 50 {
+		t.Errorf("unexpected HorizontalPodAutoscaler %+v", o.autoscaler.Spec)
+	}
+}
+
+func Test_generateObjects_Defaults(t *testing.T) {
+	o, err := generateObjects(fn.Function{Name: "myfunc", Image: "example.com/alice/myfunc"}, "ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *o.deployment.Spec.Replicas != 1 {
+		t.Errorf("expected a single replica, got %v", *o.deployment.Spec.Replicas)
+	}
+	if o.ingress != nil || o.autoscaler != nil {
+		t.Errorf("expected neither an Ingress nor a HorizontalPodAutoscaler")
+	}
+	if o.deployment.Spec.Template.Spec.Containers[0].StartupProbe != nil {
+		t.Errorf("expected no startup probe")
+	}
+	if url := serviceURL("myfunc", "ns", nil); url != "http://myfunc.ns.svc.cluster.local" {
+		t.Errorf("unexpected URL %v", url)
+	}
+}
+
+func Test_deploymentReady(t *testing.T) {
+	tests := []struct {
+		name   string
+		status appsv1.DeploymentStatus
+		ready  bool
+	}{
+		{"not observed", appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 2, Replicas: 2, AvailableReplicas: 2}, false},
+		{"rolling out", appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 1, Replicas: 3, AvailableReplicas: 2}, false},
+		{"unavailable", appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, Replicas: 2, AvailableReplicas: 1}, false},
+		{"ready", appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, Replicas: 2, AvailableReplicas: 2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &appsv1.Deployment{Status: tt.status}
+			d.Generation = 2
+			d.Spec.Replicas = ptr.Int32(2)
+			if got := deploymentReady(d); got != tt.ready {
+				t.Errorf("deploymentReady() = %v, want %v", got, tt.ready)
+			}
+		})
+	}
+}
+
+func Test_ProcessVolumes(t *testing.T) {
+	volumes := []fn.Volume{
+		{PersistentVolumeClaim: &fn.PersistentVolumeClaim{ClaimName: ptr.String("data"), ReadOnly: true}, Path: ptr.String("/data")},
+		{EmptyDir: &fn.EmptyDir{Medium: "Memory", SizeLimit: ptr.String("64Mi")}, Path: ptr.String("/tmp/scratch")},
+		{ServiceAccountToken: &fn.ServiceAccountToken{Audience: "vault", ExpirationSeconds: ptr.Int64(3600)}, Path: ptr.String("/var/run/secrets/tokens")},
+	}
+	secrets, configMaps := sets.NewString(), sets.NewString()
+
+	vv, mm, err := ProcessVolumes(volumes, &secrets, &configMaps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vv) != 3 || len(mm) != 3 {
+		t.Fatalf("expected 3 volumes and mounts, got %d and %d", len(vv), len(mm))
+	}
+
+	pvc := vv[0].PersistentVolumeClaim
+	if pvc == nil || pvc.ClaimName != "data" || !pvc.ReadOnly {
+		t.Errorf("unexpected persistentVolumeClaim source: %+v", vv[0].VolumeSource)
+	}
+	if !mm[0].ReadOnly || mm[0].MountPath != "/data" {
+		t.Errorf("unexpected persistentVolumeClaim mount: %+v", mm[0])
+	}
+
+	emptyDir := vv[1].EmptyDir
+	if emptyDir == nil || emptyDir.Medium != corev1.StorageMediumMemory || emptyDir.SizeLimit.Cmp(resource.MustParse("64Mi")) != 0 {
+		t.Errorf("unexpected emptyDir source: %+v", vv[1].VolumeSource)
+	}
+	if mm[1].ReadOnly || mm[1].Name != vv[1].Name {
+		t.Errorf("unexpected emptyDir mount: %+v", mm[1])
+	}
+
+	if vv[2].Projected == nil || len(vv[2].Projected.Sources) != 1 {
+		t.Fatalf("expected a projected volume, got: %+v", vv[2].VolumeSource)
+	}
+	token := vv[2].Projected.Sources[0].ServiceAccountToken
+	if token == nil || token.Audience != "vault" || *token.ExpirationSeconds != 3600 || token.Path != "token" {
+		t.Errorf("unexpected serviceAccountToken projection: %+v", token)
+	}
+
+	if secrets.Len() != 0 || configMaps.Len() != 0 {
+		t.Errorf("expected no referenced secrets or config maps")
+	}
+}
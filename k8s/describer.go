@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s/labels"
+)
+
+// Describer of functions deployed by the Deployer.
+type Describer struct {
+	namespace string
+	verbose   bool
+}
+
+func NewDescriber(namespaceOverride string, verbose bool) *Describer {
+	return &Describer{
+		namespace: namespaceOverride,
+		verbose:   verbose,
+	}
+}
+
+// Describe the function of the given name.  Its primary route is that of its
+// Ingress if it has one, otherwise the cluster-local address of its Service.
+func (d *Describer) Describe(ctx context.Context, name string) (description fn.Instance, err error) {
+	client, namespace, err := NewClientAndResolvedNamespace(d.namespace)
+	if err != nil {
+		return
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+	if deployment.Labels[labels.DeployerKey] != DeployerName {
+		// A Deployment of a Knative Service of the same name
+		return description, errors.NewNotFound(appsv1.Resource("deployments"), name)
+	}
+
+	ingress, err := ingressOf(ctx, client, name, namespace)
+	if err != nil {
+		return
+	}
+	routes := []string{serviceURL(name, namespace, nil)}
+	if ingress != nil {
+		routes = append([]string{serviceURL(name, namespace, ingress)}, routes...)
+	}
+
+	description.Name = name
+	description.Namespace = namespace
+	description.Route = routes[0]
+	description.Routes = routes
+	if containers := deployment.Spec.Template.Spec.Containers; len(containers) > 0 {
+		description.Image = containers[0].Image
+	}
+	return
+}
+
+// ingressOf the named function, as options from which its URL is derived,
+// or nil if it has no Ingress.
+func ingressOf(ctx context.Context, client kubernetes.Interface, name, namespace string) (*fn.IngressOptions, error) {
+	ingress, err := client.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, ignoreNotFound(err)
+	}
+	return ingressOptions(ingress), nil
+}
+
+func ingressOptions(ingress *networkingv1.Ingress) *fn.IngressOptions {
+	options := &fn.IngressOptions{}
+	if len(ingress.Spec.Rules) > 0 {
+		options.Host = ingress.Spec.Rules[0].Host
+	}
+	if len(ingress.Spec.TLS) > 0 {
+		options.TLSSecret = ingress.Spec.TLS[0].SecretName
+	}
+	return options
+}
@@ -5,6 +5,7 @@ const (
 	FunctionValue      = "true"
 	FunctionRuntimeKey = "function.knative.dev/runtime"
 	FunctionNameKey    = "function.knative.dev/name"
+	DeployerKey        = "function.knative.dev/deployer"
 
 	// --- handle usage of deprecated labels
 	DeprecatedFunctionKey        = "boson.dev/function"
@@ -0,0 +1,64 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s/labels"
+)
+
+// functionSelector selects the resources of functions deployed by the
+// Deployer, as distinct from those created by Knative for functions
+// deployed as Knative Services, which carry the same function labels.
+var functionSelector = k8slabels.SelectorFromSet(k8slabels.Set{
+	labels.FunctionKey: labels.FunctionValue,
+	labels.DeployerKey: DeployerName,
+}).String()
+
+// Lister of functions deployed by the Deployer.
+type Lister struct {
+	Namespace string
+	verbose   bool
+}
+
+func NewLister(namespaceOverride string, verbose bool) *Lister {
+	return &Lister{
+		Namespace: namespaceOverride,
+		verbose:   verbose,
+	}
+}
+
+func (l *Lister) List(ctx context.Context) (items []fn.ListItem, err error) {
+	client, namespace, err := NewClientAndResolvedNamespace(l.Namespace)
+	if err != nil {
+		return
+	}
+
+	lst, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: functionSelector})
+	if err != nil {
+		return
+	}
+
+	for _, d := range lst.Items {
+		ready := corev1.ConditionFalse
+		if deploymentReady(&d) {
+			ready = corev1.ConditionTrue
+		}
+		ingress, err := ingressOf(ctx, client, d.Name, d.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, fn.ListItem{
+			Name:      d.Name,
+			Namespace: d.Namespace,
+			Runtime:   d.Labels[labels.FunctionRuntimeKey],
+			URL:       serviceURL(d.Name, d.Namespace, ingress),
+			Ready:     string(ready),
+		})
+	}
+	return
+}
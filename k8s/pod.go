@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fn "knative.dev/func"
+)
+
+// SetPodOptions sets the pod-level settings of the deploy spec, such as the
+// service account and scheduling constraints, on the pod spec.  Settings
+// absent from the deploy spec are cleared, such that they are removed from
+// an existing pod spec when removed from func.yaml.
+func SetPodOptions(spec *corev1.PodSpec, deploy fn.DeploySpec) {
+	spec.ServiceAccountName = deploy.ServiceAccountName
+
+	spec.ImagePullSecrets = nil
+	for _, s := range deploy.ImagePullSecrets {
+		spec.ImagePullSecrets = append(spec.ImagePullSecrets, corev1.LocalObjectReference{Name: s})
+	}
+
+	spec.NodeSelector = nil
+	if len(deploy.NodeSelector) > 0 {
+		spec.NodeSelector = make(map[string]string, len(deploy.NodeSelector))
+		for k, v := range deploy.NodeSelector {
+			spec.NodeSelector[k] = v
+		}
+	}
+
+	spec.Tolerations = nil
+	for _, t := range deploy.Tolerations {
+		spec.Tolerations = append(spec.Tolerations, corev1.Toleration{
+			Key:               t.Key,
+			Operator:          corev1.TolerationOperator(t.Operator),
+			Value:             t.Value,
+			Effect:            corev1.TaintEffect(t.Effect),
+			TolerationSeconds: t.TolerationSeconds,
+		})
+	}
+
+	spec.Affinity = affinity(deploy.Affinity)
+}
+
+func affinity(a *fn.Affinity) *corev1.Affinity {
+	if a == nil {
+		return nil
+	}
+	affinity := &corev1.Affinity{
+		PodAffinity:     (*corev1.PodAffinity)(podAffinity(a.PodAffinity)),
+		PodAntiAffinity: (*corev1.PodAntiAffinity)(podAffinity(a.PodAntiAffinity)),
+	}
+	if n := a.NodeAffinity; n != nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+		if len(n.Required) > 0 {
+			affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+			for _, term := range n.Required {
+				affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = append(
+					affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, nodeSelectorTerm(term))
+			}
+		}
+		for _, term := range n.Preferred {
+			affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+				affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, corev1.PreferredSchedulingTerm{
+					Weight:     term.Weight,
+					Preference: nodeSelectorTerm(term.Preference),
+				})
+		}
+	}
+	return affinity
+}
+
+func nodeSelectorTerm(term fn.NodeSelectorTerm) (t corev1.NodeSelectorTerm) {
+	for _, r := range term.MatchExpressions {
+		t.MatchExpressions = append(t.MatchExpressions, corev1.NodeSelectorRequirement{
+			Key:      r.Key,
+			Operator: corev1.NodeSelectorOperator(r.Operator),
+			Values:   r.Values,
+		})
+	}
+	return
+}
+
+// podAffinity returns the pod affinity, which has the same structure as pod
+// anti-affinity.
+func podAffinity(a *fn.PodAffinity) *corev1.PodAffinity {
+	if a == nil {
+		return nil
+	}
+	affinity := &corev1.PodAffinity{}
+	for _, term := range a.Required {
+		affinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.RequiredDuringSchedulingIgnoredDuringExecution, podAffinityTerm(term))
+	}
+	for _, term := range a.Preferred {
+		affinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PreferredDuringSchedulingIgnoredDuringExecution, corev1.WeightedPodAffinityTerm{
+				Weight:          term.Weight,
+				PodAffinityTerm: podAffinityTerm(term.PodAffinityTerm),
+			})
+	}
+	return affinity
+}
+
+func podAffinityTerm(term fn.PodAffinityTerm) corev1.PodAffinityTerm {
+	selector := &metav1.LabelSelector{MatchLabels: term.LabelSelector.MatchLabels}
+	for _, r := range term.LabelSelector.MatchExpressions {
+		selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      r.Key,
+			Operator: metav1.LabelSelectorOperator(r.Operator),
+			Values:   r.Values,
+		})
+	}
+	return corev1.PodAffinityTerm{
+		LabelSelector: selector,
+		Namespaces:    term.Namespaces,
+		TopologyKey:   term.TopologyKey,
+	}
+}
@@ -0,0 +1,75 @@
+//go:build !integration
+// +build !integration
+
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/ptr"
+
+	fn "knative.dev/func"
+)
+
+func TestSetPodOptions(t *testing.T) {
+	deploy := fn.DeploySpec{
+		ServiceAccountName: "myfunc",
+		ImagePullSecrets:   []string{"myregistry"},
+		NodeSelector:       map[string]string{"accelerator": "gpu"},
+		Tolerations: []fn.Toleration{
+			{Key: "nvidia.com/gpu", Operator: "Exists", Effect: "NoExecute", TolerationSeconds: ptr.Int64(30)},
+		},
+		Affinity: &fn.Affinity{
+			NodeAffinity: &fn.NodeAffinity{
+				Required: []fn.NodeSelectorTerm{{MatchExpressions: []fn.SelectorRequirement{
+					{Key: "kubernetes.io/arch", Operator: "In", Values: []string{"amd64"}},
+				}}},
+			},
+			PodAntiAffinity: &fn.PodAffinity{
+				Preferred: []fn.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: fn.PodAffinityTerm{
+					LabelSelector: fn.LabelSelector{MatchExpressions: []fn.SelectorRequirement{
+						{Key: "app", Operator: "In", Values: []string{"myfunc"}},
+					}},
+					TopologyKey: "kubernetes.io/hostname",
+				}}},
+			},
+		},
+	}
+
+	spec := corev1.PodSpec{}
+	SetPodOptions(&spec, deploy)
+
+	if spec.ServiceAccountName != "myfunc" {
+		t.Errorf("unexpected service account name %q", spec.ServiceAccountName)
+	}
+	if len(spec.ImagePullSecrets) != 1 || spec.ImagePullSecrets[0].Name != "myregistry" {
+		t.Errorf("unexpected image pull secrets %v", spec.ImagePullSecrets)
+	}
+	if spec.NodeSelector["accelerator"] != "gpu" {
+		t.Errorf("unexpected node selector %v", spec.NodeSelector)
+	}
+	if len(spec.Tolerations) != 1 || spec.Tolerations[0].Operator != corev1.TolerationOpExists ||
+		spec.Tolerations[0].Effect != corev1.TaintEffectNoExecute || *spec.Tolerations[0].TolerationSeconds != 30 {
+		t.Errorf("unexpected tolerations %v", spec.Tolerations)
+	}
+	if spec.Affinity == nil || spec.Affinity.PodAffinity != nil {
+		t.Fatalf("unexpected affinity %v", spec.Affinity)
+	}
+	terms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 || terms[0].MatchExpressions[0].Operator != corev1.NodeSelectorOpIn {
+		t.Errorf("unexpected node affinity %v", spec.Affinity.NodeAffinity)
+	}
+	anti := spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	if len(anti) != 1 || anti[0].Weight != 100 || anti[0].PodAffinityTerm.TopologyKey != "kubernetes.io/hostname" ||
+		anti[0].PodAffinityTerm.LabelSelector.MatchExpressions[0].Values[0] != "myfunc" {
+		t.Errorf("unexpected pod anti-affinity %v", spec.Affinity.PodAntiAffinity)
+	}
+
+	// Settings removed from the deploy spec are removed from the pod spec.
+	SetPodOptions(&spec, fn.DeploySpec{})
+	if spec.ServiceAccountName != "" || spec.ImagePullSecrets != nil || spec.NodeSelector != nil ||
+		spec.Tolerations != nil || spec.Affinity != nil {
+		t.Errorf("expected pod options to be cleared, got %+v", spec)
+	}
+}
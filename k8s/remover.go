@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/func/k8s/labels"
+)
+
+func NewRemover(namespaceOverride string, verbose bool) *Remover {
+	return &Remover{
+		Namespace: namespaceOverride,
+		verbose:   verbose,
+	}
+}
+
+// Remover of functions deployed by the Deployer.
+type Remover struct {
+	Namespace string
+	verbose   bool
+}
+
+// Remove the Deployment, Service, Ingress and HorizontalPodAutoscaler of the
+// named function.  Removing a function which is not deployed by the Deployer
+// is an error, such that the Deployment of a Knative Service of the same
+// name is left to Knative.
+func (remover *Remover) Remove(ctx context.Context, name string) (err error) {
+	client, namespace, err := NewClientAndResolvedNamespace(remover.Namespace)
+	if err != nil {
+		return
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+	if deployment.Labels[labels.DeployerKey] != DeployerName {
+		return errors.NewNotFound(appsv1.Resource("deployments"), name)
+	}
+
+	propagation := metav1.DeletePropagationForeground
+	opts := metav1.DeleteOptions{PropagationPolicy: &propagation}
+	if err = ignoreNotFound(client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Delete(ctx, name, opts)); err != nil {
+		return fmt.Errorf("kubernetes remover failed to delete the HorizontalPodAutoscaler: %v", err)
+	}
+	if err = ignoreNotFound(client.NetworkingV1().Ingresses(namespace).Delete(ctx, name, opts)); err != nil {
+		return fmt.Errorf("kubernetes remover failed to delete the Ingress: %v", err)
+	}
+	if err = ignoreNotFound(client.CoreV1().Services(namespace).Delete(ctx, name, opts)); err != nil {
+		return fmt.Errorf("kubernetes remover failed to delete the Service: %v", err)
+	}
+	if err = ignoreNotFound(client.AppsV1().Deployments(namespace).Delete(ctx, name, opts)); err != nil {
+		return fmt.Errorf("kubernetes remover failed to delete the Deployment: %v", err)
+	}
+	return
+}
@@ -0,0 +1,35 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	fn "knative.dev/func"
+)
+
+// Render the Deployment, Service, Ingress and HorizontalPodAutoscaler with
+// which the function would be deployed, as they would be created, without
+// connecting to the cluster.  Objects are rendered in the deployer's
+// namespace, defaulting to that of the function, or if neither is set
+// without a namespace such that they are applied to the current namespace.
+func (d *Deployer) Render(ctx context.Context, f fn.Function) (m fn.Manifests, err error) {
+	namespace := d.Namespace
+	if namespace == "" {
+		namespace = f.Deploy.Namespace
+	}
+	o, err := generateObjects(f, namespace)
+	if err != nil {
+		return m, fmt.Errorf("kubernetes deployer failed to generate the Deployment: %v", err)
+	}
+	m.Objects = append(m.Objects, o.deployment, o.service)
+	if o.ingress != nil {
+		m.Objects = append(m.Objects, o.ingress)
+	}
+	if o.autoscaler != nil {
+		m.Objects = append(m.Objects, o.autoscaler)
+	}
+	m.Secrets = o.secrets.List()
+	m.ConfigMaps = o.configMaps.List()
+	m.PersistentVolumeClaims = ReferencedClaims(f)
+	return
+}
@@ -8,6 +8,7 @@ import (
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
 	eventingv1 "knative.dev/eventing/pkg/client/clientset/versioned/typed/eventing/v1"
 	servingv1 "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1beta1 "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1beta1"
 
 	"knative.dev/func/k8s"
 )
@@ -50,3 +51,18 @@ func NewEventingClient(namespace string) (clienteventingv1.KnEventingClient, err
 
 	return client, nil
 }
+
+func NewDomainMappingsClient(namespace string) (servingv1beta1.DomainMappingInterface, error) {
+
+	restConfig, err := k8s.GetClientConfig().ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new domain mappings client: %v", err)
+	}
+
+	servingClient, err := servingv1beta1.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new domain mappings client: %v", err)
+	}
+
+	return servingClient.DomainMappings(namespace), nil
+}
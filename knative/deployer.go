@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"regexp"
-	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -18,15 +16,17 @@ import (
 	servingclientlib "knative.dev/client/pkg/serving"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
 	"knative.dev/client/pkg/wait"
+	"knative.dev/networking/pkg/apis/networking"
 	"knative.dev/serving/pkg/apis/autoscaling"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	fn "knative.dev/func"
 	"knative.dev/func/k8s"
 )
 
-const LIVENESS_ENDPOINT = "/health/liveness"
-const READINESS_ENDPOINT = "/health/readiness"
+const LIVENESS_ENDPOINT = k8s.DefaultLivenessEndpoint
+const READINESS_ENDPOINT = k8s.DefaultReadinessEndpoint
 
 type DeployDecorator interface {
 	UpdateAnnotations(fn.Function, map[string]string) map[string]string
@@ -153,7 +153,7 @@ func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResu
 				return fn.DeploymentResult{}, err
 			}
 
-			err = checkSecretsConfigMapsArePresent(ctx, d.Namespace, &referencedSecrets, &referencedConfigMaps)
+			err = k8s.CheckSecretsConfigMapsArePresent(ctx, d.Namespace, &referencedSecrets, &referencedConfigMaps)
 			if err != nil {
 				err = fmt.Errorf("knative deployer failed to generate the Knative Service: %v", err)
 				return fn.DeploymentResult{}, err
@@ -221,6 +221,10 @@ func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResu
 				return fn.DeploymentResult{}, err
 			}
 
+			if err = d.updateTriggers(ctx, client, f); err != nil {
+				return fn.DeploymentResult{}, err
+			}
+
 			if d.verbose {
 				fmt.Printf("Function deployed in namespace %q and exposed at URL:\n%s\n", d.Namespace, route.Status.URL.String())
 			}
@@ -239,23 +243,28 @@ func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResu
 		referencedSecrets := sets.NewString()
 		referencedConfigMaps := sets.NewString()
 
-		newEnv, newEnvFrom, err := processEnvs(f.Run.Envs, &referencedSecrets, &referencedConfigMaps)
+		newEnv, newEnvFrom, err := k8s.ProcessEnvs(f.Run.Envs, &referencedSecrets, &referencedConfigMaps)
+		if err != nil {
+			return fn.DeploymentResult{}, err
+		}
+
+		newVolumes, newVolumeMounts, err := k8s.ProcessVolumes(f.Run.Volumes, &referencedSecrets, &referencedConfigMaps)
 		if err != nil {
 			return fn.DeploymentResult{}, err
 		}
 
-		newVolumes, newVolumeMounts, err := processVolumes(f.Run.Volumes, &referencedSecrets, &referencedConfigMaps)
+		sidecars, initContainers, newVolumes, err := k8s.ProcessContainers(f.Deploy, newVolumes, &referencedSecrets, &referencedConfigMaps)
 		if err != nil {
 			return fn.DeploymentResult{}, err
 		}
 
-		err = checkSecretsConfigMapsArePresent(ctx, d.Namespace, &referencedSecrets, &referencedConfigMaps)
+		err = k8s.CheckSecretsConfigMapsArePresent(ctx, d.Namespace, &referencedSecrets, &referencedConfigMaps)
 		if err != nil {
 			err = fmt.Errorf("knative deployer failed to update the Knative Service: %v", err)
 			return fn.DeploymentResult{}, err
 		}
 
-		_, err = client.UpdateServiceWithRetry(ctx, f.Name, updateService(f, newEnv, newEnvFrom, newVolumes, newVolumeMounts, d.decorator), 3)
+		_, err = client.UpdateServiceWithRetry(ctx, f.Name, updateService(f, newEnv, newEnvFrom, newVolumes, newVolumeMounts, sidecars, initContainers, d.decorator), 3)
 		if err != nil {
 			err = fmt.Errorf("knative deployer failed to update the Knative Service: %v", err)
 			return fn.DeploymentResult{}, err
@@ -279,6 +288,10 @@ func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResu
 			return fn.DeploymentResult{}, err
 		}
 
+		if err = d.updateTriggers(ctx, client, f); err != nil {
+			return fn.DeploymentResult{}, err
+		}
+
 		return fn.DeploymentResult{
 			Status:    fn.Updated,
 			URL:       route.Status.URL.String(),
@@ -297,6 +310,21 @@ func probeFor(url string) *corev1.Probe {
 	}
 }
 
+// withVisibility returns a copy of the labels of a service including the
+// label which makes it reachable only from within the cluster, when its
+// visibility is cluster-local.  The labels of its revisions do not include it.
+func withVisibility(labels map[string]string, visibility string) map[string]string {
+	if visibility != fn.VisibilityClusterLocal {
+		return labels
+	}
+	l := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		l[k] = v
+	}
+	l[networking.VisibilityLabelKey] = serving.VisibilityClusterLocal
+	return l
+}
+
 func setHealthEndpoints(f fn.Function, c *corev1.Container) *corev1.Container {
 	// Set the defaults
 	c.LivenessProbe = probeFor(LIVENESS_ENDPOINT)
@@ -309,6 +337,15 @@ func setHealthEndpoints(f fn.Function, c *corev1.Container) *corev1.Container {
 	if f.Deploy.HealthEndpoints.Readiness != "" {
 		c.ReadinessProbe = probeFor(f.Deploy.HealthEndpoints.Readiness)
 	}
+	k8s.SetProbeOptions(c.LivenessProbe, f.Deploy.HealthEndpoints.Probes.Liveness)
+	k8s.SetProbeOptions(c.ReadinessProbe, f.Deploy.HealthEndpoints.Probes.Readiness)
+
+	// There is no default startup probe
+	c.StartupProbe = nil
+	if f.Deploy.HealthEndpoints.Startup != "" {
+		c.StartupProbe = probeFor(f.Deploy.HealthEndpoints.Startup)
+		k8s.SetProbeOptions(c.StartupProbe, f.Deploy.HealthEndpoints.Probes.Startup)
+	}
 	return c
 }
 
@@ -321,19 +358,24 @@ func generateNewService(f fn.Function, decorator DeployDecorator) (*v1.Service,
 	referencedSecrets := sets.NewString()
 	referencedConfigMaps := sets.NewString()
 
-	newEnv, newEnvFrom, err := processEnvs(f.Run.Envs, &referencedSecrets, &referencedConfigMaps)
+	newEnv, newEnvFrom, err := k8s.ProcessEnvs(f.Run.Envs, &referencedSecrets, &referencedConfigMaps)
 	if err != nil {
 		return nil, err
 	}
 	container.Env = newEnv
 	container.EnvFrom = newEnvFrom
 
-	newVolumes, newVolumeMounts, err := processVolumes(f.Run.Volumes, &referencedSecrets, &referencedConfigMaps)
+	newVolumes, newVolumeMounts, err := k8s.ProcessVolumes(f.Run.Volumes, &referencedSecrets, &referencedConfigMaps)
 	if err != nil {
 		return nil, err
 	}
 	container.VolumeMounts = newVolumeMounts
 
+	sidecars, initContainers, newVolumes, err := k8s.ProcessContainers(f.Deploy, newVolumes, &referencedSecrets, &referencedConfigMaps)
+	if err != nil {
+		return nil, err
+	}
+
 	labels, err := f.LabelsMap()
 	if err != nil {
 		return nil, err
@@ -350,7 +392,7 @@ func generateNewService(f fn.Function, decorator DeployDecorator) (*v1.Service,
 	service := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        f.Name,
-			Labels:      labels,
+			Labels:      withVisibility(labels, f.Deploy.Visibility),
 			Annotations: annotations,
 		},
 		Spec: v1.ServiceSpec{
@@ -362,10 +404,11 @@ func generateNewService(f fn.Function, decorator DeployDecorator) (*v1.Service,
 					},
 					Spec: v1.RevisionSpec{
 						PodSpec: corev1.PodSpec{
-							Containers: []corev1.Container{
+							Containers: append([]corev1.Container{
 								container,
-							},
-							Volumes: newVolumes,
+							}, sidecars...),
+							InitContainers: initContainers,
+							Volumes:        newVolumes,
 						},
 					},
 				},
@@ -373,15 +416,20 @@ func generateNewService(f fn.Function, decorator DeployDecorator) (*v1.Service,
 		},
 	}
 
+	k8s.SetPodOptions(&service.Spec.Template.Spec.PodSpec, f.Deploy)
+
 	err = setServiceOptions(&service.Spec.Template, f.Deploy.Options)
 	if err != nil {
 		return service, err
 	}
 
+	// The first revision receives all traffic, but may be tagged.
+	deployTraffic(service, f.Deploy.Traffic)
+
 	return service, nil
 }
 
-func updateService(f fn.Function, newEnv []corev1.EnvVar, newEnvFrom []corev1.EnvFromSource, newVolumes []corev1.Volume, newVolumeMounts []corev1.VolumeMount, decorator DeployDecorator) func(service *v1.Service) (*v1.Service, error) {
+func updateService(f fn.Function, newEnv []corev1.EnvVar, newEnvFrom []corev1.EnvFromSource, newVolumes []corev1.Volume, newVolumeMounts []corev1.VolumeMount, sidecars, initContainers []corev1.Container, decorator DeployDecorator) func(service *v1.Service) (*v1.Service, error) {
 	return func(service *v1.Service) (*v1.Service, error) {
 		// Removing the name so the k8s server can fill it in with generated name,
 		// this prevents conflicts in Revision name when updating the KService from multiple places.
@@ -393,10 +441,15 @@ func updateService(f fn.Function, newEnv []corev1.EnvVar, newEnvFrom []corev1.En
 			service.ObjectMeta.Annotations = decorator.UpdateAnnotations(f, service.ObjectMeta.Annotations)
 		}
 
+		// The commit of a previous deploy is not carried over to the new revision
+		delete(service.ObjectMeta.Annotations, fn.GitCommitAnnotation)
+		delete(service.Spec.Template.ObjectMeta.Annotations, fn.GitCommitAnnotation)
 		for k, v := range f.Deploy.Annotations {
 			service.ObjectMeta.Annotations[k] = v
 			service.Spec.Template.ObjectMeta.Annotations[k] = v
 		}
+
+		deployTraffic(service, f.Deploy.Traffic)
 		// I hate that we have to do this. Users should not see these values.
 		// It is an implementation detail. These health endpoints should not be
 		// a part of func.yaml since the user can only mess things up by changing
@@ -425,7 +478,7 @@ func updateService(f fn.Function, newEnv []corev1.EnvVar, newEnvFrom []corev1.En
 			labels = decorator.UpdateLabels(f, labels)
 		}
 
-		service.ObjectMeta.Labels = labels
+		service.ObjectMeta.Labels = withVisibility(labels, f.Deploy.Visibility)
 		service.Spec.Template.ObjectMeta.Labels = labels
 
 		err = flags.UpdateImage(&service.Spec.Template.Spec.PodSpec, f.ImageWithDigest())
@@ -437,288 +490,15 @@ func updateService(f fn.Function, newEnv []corev1.EnvVar, newEnvFrom []corev1.En
 		cp.EnvFrom = newEnvFrom
 		cp.VolumeMounts = newVolumeMounts
 		service.Spec.ConfigurationSpec.Template.Spec.Volumes = newVolumes
+		k8s.SetPodOptions(&service.Spec.Template.Spec.PodSpec, f.Deploy)
 
-		return service, nil
-	}
-}
-
-// processEnvs generates array of EnvVars and EnvFromSources from a function config
-// envs:
-//   - name: EXAMPLE1                            # ENV directly from a value
-//     value: value1
-//   - name: EXAMPLE2                            # ENV from the local ENV var
-//     value: {{ env:MY_ENV }}
-//   - name: EXAMPLE3
-//     value: {{ secret:example-secret:key }}    # ENV from a key in Secret
-//   - value: {{ secret:example-secret }}        # all ENVs from Secret
-//   - name: EXAMPLE4
-//     value: {{ configMap:configMapName:key }}  # ENV from a key in ConfigMap
-//   - value: {{ configMap:configMapName }}      # all key-pair values from ConfigMap are set as ENV
-func processEnvs(envs []fn.Env, referencedSecrets, referencedConfigMaps *sets.String) ([]corev1.EnvVar, []corev1.EnvFromSource, error) {
-
-	envVars := []corev1.EnvVar{{Name: "BUILT", Value: time.Now().Format("20060102T150405")}}
-	envFrom := []corev1.EnvFromSource{}
-
-	for _, env := range envs {
-		if env.Name == nil && env.Value != nil {
-			// all key-pair values from secret/configMap are set as ENV, eg. {{ secret:secretName }} or {{ configMap:configMapName }}
-			if strings.HasPrefix(*env.Value, "{{") {
-				envFromSource, err := createEnvFromSource(*env.Value, referencedSecrets, referencedConfigMaps)
-				if err != nil {
-					return nil, nil, err
-				}
-				envFrom = append(envFrom, *envFromSource)
-				continue
-			}
-		} else if env.Name != nil && env.Value != nil {
-			if strings.HasPrefix(*env.Value, "{{") {
-				slices := strings.Split(strings.Trim(*env.Value, "{} "), ":")
-				if len(slices) == 3 {
-					// ENV from a key in secret/configMap, eg. FOO={{ secret:secretName:key }} FOO={{ configMap:configMapName.key }}
-					valueFrom, err := createEnvVarSource(slices, referencedSecrets, referencedConfigMaps)
-					envVars = append(envVars, corev1.EnvVar{Name: *env.Name, ValueFrom: valueFrom})
-					if err != nil {
-						return nil, nil, err
-					}
-					continue
-				} else if len(slices) == 2 {
-					// ENV from the local ENV var, eg. FOO={{ env:LOCAL_ENV }}
-					localValue, err := processLocalEnvValue(*env.Value)
-					if err != nil {
-						return nil, nil, err
-					}
-					envVars = append(envVars, corev1.EnvVar{Name: *env.Name, Value: localValue})
-					continue
-				}
-			} else {
-				// a standard ENV with key and value, eg. FOO=bar
-				envVars = append(envVars, corev1.EnvVar{Name: *env.Name, Value: *env.Value})
-				continue
-			}
-		}
-		return nil, nil, fmt.Errorf("unsupported env source entry \"%v\"", env)
-	}
-
-	return envVars, envFrom, nil
-}
-
-func createEnvFromSource(value string, referencedSecrets, referencedConfigMaps *sets.String) (*corev1.EnvFromSource, error) {
-	slices := strings.Split(strings.Trim(value, "{} "), ":")
-	if len(slices) != 2 {
-		return nil, fmt.Errorf("env requires a value in form \"resourceType:name\" where \"resourceType\" can be one of \"configMap\" or \"secret\"; got %q", slices)
-	}
-
-	envVarSource := corev1.EnvFromSource{}
-
-	typeString := strings.TrimSpace(slices[0])
-	sourceName := strings.TrimSpace(slices[1])
-
-	var sourceType string
-
-	switch typeString {
-	case "configMap":
-		sourceType = "ConfigMap"
-		envVarSource.ConfigMapRef = &corev1.ConfigMapEnvSource{
-			LocalObjectReference: corev1.LocalObjectReference{
-				Name: sourceName,
-			}}
-
-		if !referencedConfigMaps.Has(sourceName) {
-			referencedConfigMaps.Insert(sourceName)
-		}
-	case "secret":
-		sourceType = "Secret"
-		envVarSource.SecretRef = &corev1.SecretEnvSource{
-			LocalObjectReference: corev1.LocalObjectReference{
-				Name: sourceName,
-			}}
-		if !referencedSecrets.Has(sourceName) {
-			referencedSecrets.Insert(sourceName)
-		}
-	default:
-		return nil, fmt.Errorf("unsupported env source type %q; supported source types are \"configMap\" or \"secret\"", slices[0])
-	}
-
-	if len(sourceName) == 0 {
-		return nil, fmt.Errorf("the name of %s cannot be an empty string", sourceType)
-	}
-
-	return &envVarSource, nil
-}
-
-func createEnvVarSource(slices []string, referencedSecrets, referencedConfigMaps *sets.String) (*corev1.EnvVarSource, error) {
-
-	if len(slices) != 3 {
-		return nil, fmt.Errorf("env requires a value in form \"resourceType:name:key\" where \"resourceType\" can be one of \"configMap\" or \"secret\"; got %q", slices)
-	}
-
-	envVarSource := corev1.EnvVarSource{}
-
-	typeString := strings.TrimSpace(slices[0])
-	sourceName := strings.TrimSpace(slices[1])
-	sourceKey := strings.TrimSpace(slices[2])
-
-	var sourceType string
-
-	switch typeString {
-	case "configMap":
-		sourceType = "ConfigMap"
-		envVarSource.ConfigMapKeyRef = &corev1.ConfigMapKeySelector{
-			LocalObjectReference: corev1.LocalObjectReference{
-				Name: sourceName,
-			},
-			Key: sourceKey}
-
-		if !referencedConfigMaps.Has(sourceName) {
-			referencedConfigMaps.Insert(sourceName)
-		}
-	case "secret":
-		sourceType = "Secret"
-		envVarSource.SecretKeyRef = &corev1.SecretKeySelector{
-			LocalObjectReference: corev1.LocalObjectReference{
-				Name: sourceName,
-			},
-			Key: sourceKey}
-
-		if !referencedSecrets.Has(sourceName) {
-			referencedSecrets.Insert(sourceName)
-		}
-	default:
-		return nil, fmt.Errorf("unsupported env source type %q; supported source types are \"configMap\" or \"secret\"", slices[0])
-	}
-
-	if len(sourceName) == 0 {
-		return nil, fmt.Errorf("the name of %s cannot be an empty string", sourceType)
-	}
-
-	if len(sourceKey) == 0 {
-		return nil, fmt.Errorf("the key referenced by resource %s %q cannot be an empty string", sourceType, sourceName)
-	}
-
-	return &envVarSource, nil
-}
-
-var evRegex = regexp.MustCompile(`^{{\s*(\w+)\s*:(\w+)\s*}}$`)
-
-const (
-	ctxIdx = 1
-	valIdx = 2
-)
-
-func processLocalEnvValue(val string) (string, error) {
-	match := evRegex.FindStringSubmatch(val)
-	if len(match) > valIdx {
-		if match[ctxIdx] != "env" {
-			return "", fmt.Errorf("allowed env value entry is \"{{ env:LOCAL_VALUE }}\"; got: %q", match[ctxIdx])
-		}
-		if v, ok := os.LookupEnv(match[valIdx]); ok {
-			return v, nil
-		} else {
-			return "", fmt.Errorf("required local environment variable %q is not set", match[valIdx])
-		}
-	} else {
-		return val, nil
-	}
-}
-
-// / processVolumes generates Volumes and VolumeMounts from a function config
-// volumes:
-//   - secret: example-secret               # mount Secret as Volume
-//     path: /etc/secret-volume
-//   - configMap: example-cm                # mount ConfigMap as Volume
-//     path: /etc/cm-volume
-func processVolumes(volumes []fn.Volume, referencedSecrets, referencedConfigMaps *sets.String) ([]corev1.Volume, []corev1.VolumeMount, error) {
-
-	createdVolumes := sets.NewString()
-	usedPaths := sets.NewString()
-
-	newVolumes := []corev1.Volume{}
-	newVolumeMounts := []corev1.VolumeMount{}
-
-	for _, vol := range volumes {
-
-		volumeName := ""
-
-		if vol.Secret != nil {
-			volumeName = "secret-" + *vol.Secret
-
-			if !createdVolumes.Has(volumeName) {
-				newVolumes = append(newVolumes, corev1.Volume{
-					Name: volumeName,
-					VolumeSource: corev1.VolumeSource{
-						Secret: &corev1.SecretVolumeSource{
-							SecretName: *vol.Secret,
-						},
-					},
-				})
-				createdVolumes.Insert(volumeName)
-
-				if !referencedSecrets.Has(*vol.Secret) {
-					referencedSecrets.Insert(*vol.Secret)
-				}
-			}
-		} else if vol.ConfigMap != nil {
-			volumeName = "config-map-" + *vol.ConfigMap
-
-			if !createdVolumes.Has(volumeName) {
-				newVolumes = append(newVolumes, corev1.Volume{
-					Name: volumeName,
-					VolumeSource: corev1.VolumeSource{
-						ConfigMap: &corev1.ConfigMapVolumeSource{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: *vol.ConfigMap,
-							},
-						},
-					},
-				})
-				createdVolumes.Insert(volumeName)
-
-				if !referencedConfigMaps.Has(*vol.ConfigMap) {
-					referencedConfigMaps.Insert(*vol.ConfigMap)
-				}
-			}
-		}
-
-		if volumeName != "" {
-			if !usedPaths.Has(*vol.Path) {
-				newVolumeMounts = append(newVolumeMounts, corev1.VolumeMount{
-					Name:      volumeName,
-					MountPath: *vol.Path,
-				})
-				usedPaths.Insert(*vol.Path)
-			} else {
-				return nil, nil, fmt.Errorf("mount path %s is defined multiple times", *vol.Path)
-			}
-		}
-	}
-
-	return newVolumes, newVolumeMounts, nil
-}
-
-// checkSecretsConfigMapsArePresent returns error if Secrets or ConfigMaps
-// referenced in input sets are not deployed on the cluster in the specified namespace
-func checkSecretsConfigMapsArePresent(ctx context.Context, namespace string, referencedSecrets, referencedConfigMaps *sets.String) error {
-
-	errMsg := ""
-	for s := range *referencedSecrets {
-		_, err := k8s.GetSecret(ctx, s, namespace)
-		if err != nil {
-			errMsg += fmt.Sprintf("  referenced Secret \"%s\" is not present in namespace \"%s\"\n", s, namespace)
-		}
-	}
+		// The function's container is first, followed by the sidecars, which
+		// replace those of the previous revision.
+		service.Spec.Template.Spec.Containers = append(service.Spec.Template.Spec.Containers[:1], sidecars...)
+		service.Spec.Template.Spec.InitContainers = initContainers
 
-	for cm := range *referencedConfigMaps {
-		_, err := k8s.GetConfigMap(ctx, cm, namespace)
-		if err != nil {
-			errMsg += fmt.Sprintf("  referenced ConfigMap \"%s\" is not present in namespace \"%s\"\n", cm, namespace)
-		}
-	}
-
-	if errMsg != "" {
-		return fmt.Errorf("\n" + errMsg)
+		return service, nil
 	}
-
-	return nil
 }
 
 // setServiceOptions sets annotations on Service Revision Template or in the Service Spec
@@ -812,5 +592,16 @@ func setServiceOptions(template *v1.RevisionTemplateSpec, options fn.Options) er
 		}
 	}
 
+	// the timeouts are likewise always set based on the contents of config
+	template.Spec.TimeoutSeconds = nil
+	template.Spec.ResponseStartTimeoutSeconds = nil
+	template.Spec.IdleTimeoutSeconds = nil
+
+	if options.Timeouts != nil {
+		template.Spec.TimeoutSeconds = options.Timeouts.Request
+		template.Spec.ResponseStartTimeoutSeconds = options.Timeouts.ResponseStart
+		template.Spec.IdleTimeoutSeconds = options.Timeouts.Idle
+	}
+
 	return servingclientlib.UpdateRevisionTemplateAnnotations(template, toUpdate, toRemove)
 }
@@ -9,6 +9,10 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/pkg/ptr"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+
 	fn "knative.dev/func"
 )
 
@@ -70,48 +74,118 @@ func Test_setHealthEndpointDefaults(t *testing.T) {
 	if got != READINESS_ENDPOINT {
 		t.Errorf("expected \"%v\" but got %v", READINESS_ENDPOINT, got)
 	}
+	if c.StartupProbe != nil {
+		t.Errorf("expected no startup probe but got %v", c.StartupProbe)
+	}
+}
+
+func Test_setHealthEndpointProbes(t *testing.T) {
+	f := fn.Function{
+		Name: "testing",
+		Deploy: fn.DeploySpec{
+			HealthEndpoints: fn.HealthEndpoints{
+				Startup: "/started",
+				Probes: fn.Probes{
+					Liveness: fn.ProbeOptions{InitialDelaySeconds: 5, TimeoutSeconds: 2},
+					Startup:  fn.ProbeOptions{PeriodSeconds: 5, FailureThreshold: 60},
+				},
+			},
+		},
+	}
+	c := corev1.Container{}
+	setHealthEndpoints(f, &c)
+	if c.LivenessProbe.InitialDelaySeconds != 5 || c.LivenessProbe.TimeoutSeconds != 2 {
+		t.Errorf("expected liveness probe timings to be set but got %+v", c.LivenessProbe)
+	}
+	if c.ReadinessProbe.PeriodSeconds != 0 {
+		t.Errorf("expected readiness probe timings to default but got %+v", c.ReadinessProbe)
+	}
+	if c.StartupProbe == nil || c.StartupProbe.HTTPGet.Path != "/started" ||
+		c.StartupProbe.PeriodSeconds != 5 || c.StartupProbe.FailureThreshold != 60 {
+		t.Errorf("expected startup probe on \"/started\" but got %+v", c.StartupProbe)
+	}
+
+	// The startup probe is removed when the endpoint is
+	f.Deploy.HealthEndpoints.Startup = ""
+	setHealthEndpoints(f, &c)
+	if c.StartupProbe != nil {
+		t.Errorf("expected startup probe to be removed but got %+v", c.StartupProbe)
+	}
 }
 
-func Test_processValue(t *testing.T) {
-	testEnvVarOld, testEnvVarOldExists := os.LookupEnv("TEST_KNATIVE_DEPLOYER")
-	os.Setenv("TEST_KNATIVE_DEPLOYER", "VALUE_FOR_TEST_KNATIVE_DEPLOYER")
-	defer func() {
-		if testEnvVarOldExists {
-			os.Setenv("TEST_KNATIVE_DEPLOYER", testEnvVarOld)
-		} else {
-			os.Unsetenv("TEST_KNATIVE_DEPLOYER")
-		}
-	}()
-
-	unsetVarOld, unsetVarOldExists := os.LookupEnv("UNSET_VAR")
-	os.Unsetenv("UNSET_VAR")
-	defer func() {
-		if unsetVarOldExists {
-			os.Setenv("UNSET_VAR", unsetVarOld)
-		}
-	}()
-
-	tests := []struct {
-		name    string
-		arg     string
-		want    string
-		wantErr bool
-	}{
-		{name: "simple value", arg: "A_VALUE", want: "A_VALUE", wantErr: false},
-		{name: "using envvar value", arg: "{{ env:TEST_KNATIVE_DEPLOYER }}", want: "VALUE_FOR_TEST_KNATIVE_DEPLOYER", wantErr: false},
-		{name: "bad context", arg: "{{secret:S}}", want: "", wantErr: true},
-		{name: "unset envvar", arg: "{{env:SOME_UNSET_VAR}}", want: "", wantErr: true},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := processLocalEnvValue(tt.arg)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("processValue() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if got != tt.want {
-				t.Errorf("processValue() got = %v, want %v", got, tt.want)
-			}
-		})
+func Test_setServiceOptions_Timeouts(t *testing.T) {
+	template := &v1.RevisionTemplateSpec{}
+	template.Spec.Containers = []corev1.Container{{}}
+
+	options := fn.Options{Timeouts: &fn.TimeoutsOptions{Request: ptr.Int64(600), Idle: ptr.Int64(120)}}
+	if err := setServiceOptions(template, options); err != nil {
+		t.Fatal(err)
+	}
+	if *template.Spec.TimeoutSeconds != 600 || *template.Spec.IdleTimeoutSeconds != 120 || template.Spec.ResponseStartTimeoutSeconds != nil {
+		t.Errorf("unexpected timeouts %v %v %v", template.Spec.TimeoutSeconds, template.Spec.ResponseStartTimeoutSeconds, template.Spec.IdleTimeoutSeconds)
+	}
+	if timeouts := timeoutsOf(template.Spec); timeouts == nil || *timeouts.Request != 600 {
+		t.Errorf("unexpected described timeouts %+v", timeouts)
+	}
+
+	// Timeouts removed from the function are removed from the service
+	if err := setServiceOptions(template, fn.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if template.Spec.TimeoutSeconds != nil || template.Spec.IdleTimeoutSeconds != nil {
+		t.Errorf("expected timeouts to be removed, got %v %v", template.Spec.TimeoutSeconds, template.Spec.IdleTimeoutSeconds)
+	}
+	if timeouts := timeoutsOf(template.Spec); timeouts != nil {
+		t.Errorf("expected no described timeouts, got %+v", timeouts)
+	}
+}
+
+func Test_withVisibility(t *testing.T) {
+	labels := map[string]string{"function.knative.dev/name": "testing"}
+
+	if got := withVisibility(labels, ""); got[networking.VisibilityLabelKey] != "" {
+		t.Errorf("expected a public service to have no visibility label, got %v", got)
+	}
+
+	got := withVisibility(labels, fn.VisibilityClusterLocal)
+	if got[networking.VisibilityLabelKey] != "cluster-local" || got["function.knative.dev/name"] != "testing" {
+		t.Errorf("expected the cluster-local visibility label and the function's labels, got %v", got)
+	}
+	if _, ok := labels[networking.VisibilityLabelKey]; ok {
+		t.Errorf("expected the labels of the service's revisions not to include the visibility label")
+	}
+}
+
+func Test_generateNewService_Sidecars(t *testing.T) {
+	f := fn.Function{
+		Name:  "testing",
+		Image: "example.com/testing:latest",
+		Deploy: fn.DeploySpec{
+			Sidecars:       []fn.Container{{Name: "proxy", Image: "proxy:1.0"}},
+			InitContainers: []fn.Container{{Name: "migrate", Image: "migrate:1.0"}},
+		},
+	}
+	service, err := generateNewService(f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := service.Spec.Template.Spec
+	if len(spec.Containers) != 2 || spec.Containers[0].Image != f.Image || spec.Containers[1].Name != "proxy" {
+		t.Fatalf("expected the function's container followed by the sidecar, got %+v", spec.Containers)
+	}
+	if len(spec.InitContainers) != 1 || spec.InitContainers[0].Name != "migrate" {
+		t.Fatalf("unexpected init containers %+v", spec.InitContainers)
+	}
+
+	// Sidecars and init containers removed from func.yaml are removed on
+	// update, and the function's container is kept.
+	f.Deploy = fn.DeploySpec{}
+	service, err = updateService(f, nil, nil, nil, nil, nil, nil, nil)(service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec = service.Spec.Template.Spec
+	if len(spec.Containers) != 1 || spec.Containers[0].Image != f.Image || spec.InitContainers != nil {
+		t.Errorf("expected only the function's container, got %+v and init containers %+v", spec.Containers, spec.InitContainers)
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	fn "knative.dev/func"
 	"knative.dev/func/k8s"
@@ -65,10 +66,28 @@ func (d *Describer) Describe(ctx context.Context, name string) (description fn.I
 		primaryRouteURL = routes.Items[0].Status.URL.String()
 	}
 
+	// Tagged revisions are reachable at their own URLs
+	traffic := toTraffic(service.Status.Traffic)
+	for _, t := range traffic {
+		if t.URL != "" {
+			routeURLs = append(routeURLs, t.URL)
+		}
+	}
+
+	// Custom domains are mapped to the function by DomainMappings, which
+	// are not listed when the cluster does not support them.
+	if domainsClient, err := NewDomainMappingsClient(d.namespace); err == nil {
+		if urls, err := domainURLs(ctx, domainsClient, name); err == nil {
+			routeURLs = append(routeURLs, urls...)
+		}
+	}
+
 	description.Name = name
 	description.Namespace = d.namespace
 	description.Route = primaryRouteURL
 	description.Routes = routeURLs
+	description.Traffic = traffic
+	description.Timeouts = timeoutsOf(service.Spec.Template.Spec)
 
 	triggers, err := eventingClient.ListTriggers(ctx)
 	// IsNotFound -- Eventing is probably not installed on the cluster
@@ -103,3 +122,15 @@ func (d *Describer) Describe(ctx context.Context, name string) (description fn.I
 
 	return
 }
+
+// timeoutsOf the revision spec, or nil if none are set.
+func timeoutsOf(spec v1.RevisionSpec) *fn.TimeoutsOptions {
+	if spec.TimeoutSeconds == nil && spec.ResponseStartTimeoutSeconds == nil && spec.IdleTimeoutSeconds == nil {
+		return nil
+	}
+	return &fn.TimeoutsOptions{
+		Request:       spec.TimeoutSeconds,
+		ResponseStart: spec.ResponseStartTimeoutSeconds,
+		Idle:          spec.IdleTimeoutSeconds,
+	}
+}
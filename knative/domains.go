@@ -0,0 +1,268 @@
+package knative
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1beta1"
+	servingv1beta1 "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1beta1"
+
+	fn "knative.dev/func"
+	"knative.dev/func/deployers"
+	"knative.dev/func/k8s"
+	"knative.dev/func/k8s/labels"
+)
+
+// DefaultDomainReadyTimeout is how long to wait for the domains of a function
+// to become ready before reporting those which are not.
+const DefaultDomainReadyTimeout = 60 * time.Second
+
+// DNSProvider routes the custom domains of functions to their Knative
+// Services with a Knative DomainMapping for each.  DNS records of the domains
+// are managed outside of the cluster.
+type DNSProvider struct {
+	Namespace string
+	verbose   bool
+}
+
+func NewDNSProvider(namespaceOverride string, verbose bool) *DNSProvider {
+	return &DNSProvider{
+		Namespace: namespaceOverride,
+		verbose:   verbose,
+	}
+}
+
+// Provide the domains of the deployed function, creating or updating a
+// DomainMapping for each and deleting those of domains no longer listed, and
+// report those which do not become ready.
+func (p *DNSProvider) Provide(ctx context.Context, f fn.Function) (err error) {
+	namespace := f.Deploy.Namespace
+	if namespace == "" {
+		if namespace, err = k8s.GetNamespace(p.Namespace); err != nil {
+			return
+		}
+	}
+	client, err := NewDomainMappingsClient(namespace)
+	if err != nil {
+		return
+	}
+
+	var service *v1.Service
+	if len(f.Deploy.Domains) > 0 {
+		servingClient, err := NewServingClient(namespace)
+		if err != nil {
+			return err
+		}
+		if service, err = servingClient.GetService(ctx, f.Name); errors.IsNotFound(err) {
+			return fmt.Errorf("custom domains are only supported for functions deployed as a Knative Service, with the '%s' deployer", deployers.Knative)
+		} else if err != nil {
+			return fmt.Errorf("knative DNS provider failed to get the Knative Service: %v", err)
+		}
+	}
+	if err = reconcileDomainMappings(ctx, client, f, service); err != nil {
+		return
+	}
+
+	ready, notReady, err := waitForDomainMappings(ctx, client, f.Deploy.Domains, DefaultDomainReadyTimeout)
+	if err != nil {
+		return
+	}
+	if p.verbose {
+		for _, url := range ready {
+			fmt.Printf("Function is served at domain %v\n", url)
+		}
+	}
+	for domain, reason := range notReady {
+		fmt.Printf("Warning: domain %v is not ready: %v\n", domain, reason)
+	}
+	return
+}
+
+// Remove the domains of the named function.  Functions removed without access
+// to a cluster, such as those deployed as local containers, have none.
+func (p *DNSProvider) Remove(ctx context.Context, name string) error {
+	namespace, err := k8s.GetNamespace(p.Namespace)
+	if err != nil {
+		return nil
+	}
+	client, err := NewDomainMappingsClient(namespace)
+	if err != nil {
+		return nil
+	}
+	return deleteDomainMappings(ctx, client, name)
+}
+
+// generateDomainMapping of the given domain to the function's service.  The
+// mapping is named for its domain, labeled with the function's name and owned
+// by its service.
+func generateDomainMapping(f fn.Function, domain fn.Domain, service *v1.Service) *v1beta1.DomainMapping {
+	mapping := &v1beta1.DomainMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   domain.Name,
+			Labels: map[string]string{labels.FunctionNameKey: f.Name},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: v1.SchemeGroupVersion.String(),
+				Kind:       "Service",
+				Name:       service.Name,
+				UID:        service.UID,
+			}},
+		},
+		Spec: v1beta1.DomainMappingSpec{
+			Ref: *serviceRef(f.Name),
+		},
+	}
+	mapping.Spec.Ref.Namespace = service.Namespace
+	if domain.TLSSecret != "" {
+		mapping.Spec.TLS = &v1beta1.SecretTLS{SecretName: domain.TLSSecret}
+	}
+	return mapping
+}
+
+// reconcileDomainMappings creates a DomainMapping for each of the function's
+// domains, updating those which exist, and deletes those of the function
+// whose domains are no longer listed.  A domain mapped to anything other than
+// the function is an error.
+func reconcileDomainMappings(ctx context.Context, client servingv1beta1.DomainMappingInterface, f fn.Function, service *v1.Service) error {
+	list, err := client.List(ctx, metav1.ListOptions{LabelSelector: labels.FunctionNameKey + "=" + f.Name})
+	if err != nil {
+		if len(f.Deploy.Domains) == 0 {
+			return nil // DomainMappings are probably not supported, and not required
+		}
+		return fmt.Errorf("knative DNS provider failed to list the DomainMappings: %v", err)
+	}
+	existing := map[string]*v1beta1.DomainMapping{}
+	for i := range list.Items {
+		existing[list.Items[i].Name] = &list.Items[i]
+	}
+
+	wanted := map[string]bool{}
+	for _, domain := range f.Deploy.Domains {
+		wanted[domain.Name] = true
+		desired := generateDomainMapping(f, domain, service)
+		current, ok := existing[domain.Name]
+		if !ok {
+			_, err = client.Create(ctx, desired, metav1.CreateOptions{})
+			if errors.IsAlreadyExists(err) {
+				return fmt.Errorf("domain %v is already mapped to another resource in namespace %v", domain.Name, service.Namespace)
+			} else if err != nil {
+				return fmt.Errorf("knative DNS provider failed to create the DomainMapping: %v", err)
+			}
+			continue
+		}
+		if equality.Semantic.DeepEqual(current.Spec, desired.Spec) &&
+			equality.Semantic.DeepEqual(current.OwnerReferences, desired.OwnerReferences) {
+			continue
+		}
+		current.Spec = desired.Spec
+		current.OwnerReferences = desired.OwnerReferences
+		if _, err = client.Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("knative DNS provider failed to update the DomainMapping: %v", err)
+		}
+	}
+
+	for name := range existing {
+		if wanted[name] {
+			continue
+		}
+		if err = client.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("knative DNS provider failed to delete the stale DomainMapping: %v", err)
+		}
+	}
+	return nil
+}
+
+// waitForDomainMappings of the given domains to become ready, returning the
+// URLs of those which are, and the reason each of the others is not when the
+// timeout elapses.
+func waitForDomainMappings(ctx context.Context, client servingv1beta1.DomainMappingInterface, domains []fn.Domain, timeout time.Duration) (ready []string, notReady map[string]string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	pending := make([]string, 0, len(domains))
+	for _, d := range domains {
+		pending = append(pending, d.Name)
+	}
+	for {
+		notReady = map[string]string{}
+		var remaining []string
+		for _, name := range pending {
+			mapping, err := client.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				if ctx.Err() != nil {
+					notReady[name] = "timed out waiting for the DomainMapping"
+					continue
+				}
+				return ready, notReady, fmt.Errorf("knative DNS provider failed to get the DomainMapping: %v", err)
+			}
+			if mapping.IsReady() {
+				ready = append(ready, domainURL(mapping))
+				continue
+			}
+			remaining = append(remaining, name)
+			notReady[name] = notReadyReason(mapping)
+		}
+		pending = remaining
+		if len(pending) == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return ready, notReady, nil
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// notReadyReason of a DomainMapping, such as that its domain is claimed by
+// another namespace.
+func notReadyReason(mapping *v1beta1.DomainMapping) string {
+	c := mapping.Status.GetCondition(apis.ConditionReady)
+	if c == nil || (c.Reason == "" && c.Message == "") {
+		return "not yet reconciled"
+	}
+	if c.Message == "" {
+		return c.Reason
+	}
+	return fmt.Sprintf("%v: %v", c.Reason, c.Message)
+}
+
+// domainURL of a DomainMapping, which is https when served over TLS.
+func domainURL(mapping *v1beta1.DomainMapping) string {
+	if mapping.Status.URL != nil {
+		return mapping.Status.URL.String()
+	}
+	return "http://" + mapping.Name
+}
+
+// domainURLs of the named function's DomainMappings.
+func domainURLs(ctx context.Context, client servingv1beta1.DomainMappingInterface, name string) ([]string, error) {
+	list, err := client.List(ctx, metav1.ListOptions{LabelSelector: labels.FunctionNameKey + "=" + name})
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, 0, len(list.Items))
+	for i := range list.Items {
+		urls = append(urls, domainURL(&list.Items[i]))
+	}
+	return urls, nil
+}
+
+// deleteDomainMappings of the named function.
+func deleteDomainMappings(ctx context.Context, client servingv1beta1.DomainMappingInterface, name string) error {
+	list, err := client.List(ctx, metav1.ListOptions{LabelSelector: labels.FunctionNameKey + "=" + name})
+	if err != nil {
+		return nil // DomainMappings are probably not supported, so there are none
+	}
+	for _, m := range list.Items {
+		if err = client.Delete(ctx, m.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("knative DNS provider failed to delete the DomainMapping: %v", err)
+		}
+	}
+	return nil
+}
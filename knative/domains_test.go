@@ -0,0 +1,109 @@
+//go:build !integration
+// +build !integration
+
+package knative
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1beta1"
+	"knative.dev/serving/pkg/client/clientset/versioned/fake"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s/labels"
+)
+
+func Test_reconcileDomainMappings(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		// A stale mapping of the function, and a mapping of another.
+		&v1beta1.DomainMapping{ObjectMeta: metav1.ObjectMeta{
+			Name: "old.example.com", Namespace: "prod", Labels: map[string]string{labels.FunctionNameKey: "myfunc"}}},
+		&v1beta1.DomainMapping{ObjectMeta: metav1.ObjectMeta{
+			Name: "taken.example.com", Namespace: "prod", Labels: map[string]string{labels.FunctionNameKey: "other"}}},
+	).ServingV1beta1().DomainMappings("prod")
+
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "myfunc", Namespace: "prod", UID: types.UID("1234")}}
+	f := fn.Function{Name: "myfunc", Deploy: fn.DeploySpec{Domains: []fn.Domain{
+		{Name: "api.example.com", TLSSecret: "api-tls"},
+	}}}
+
+	if err := reconcileDomainMappings(ctx, client, f, service); err != nil {
+		t.Fatal(err)
+	}
+	mapping, err := client.Get(ctx, "api.example.com", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapping.Spec.Ref.Name != "myfunc" || mapping.Spec.Ref.Namespace != "prod" || mapping.Spec.Ref.Kind != "Service" {
+		t.Errorf("unexpected reference %+v", mapping.Spec.Ref)
+	}
+	if mapping.Spec.TLS == nil || mapping.Spec.TLS.SecretName != "api-tls" {
+		t.Errorf("unexpected TLS %+v", mapping.Spec.TLS)
+	}
+	if len(mapping.OwnerReferences) != 1 || mapping.OwnerReferences[0].UID != "1234" {
+		t.Errorf("expected the mapping to be owned by the service, got %+v", mapping.OwnerReferences)
+	}
+	if _, err = client.Get(ctx, "old.example.com", metav1.GetOptions{}); err == nil {
+		t.Error("expected the stale mapping to be deleted")
+	}
+
+	// An update of the function's domains updates its mapping.
+	f.Deploy.Domains[0].TLSSecret = ""
+	if err = reconcileDomainMappings(ctx, client, f, service); err != nil {
+		t.Fatal(err)
+	}
+	if mapping, err = client.Get(ctx, "api.example.com", metav1.GetOptions{}); err != nil || mapping.Spec.TLS != nil {
+		t.Errorf("expected the mapping to be updated, got %+v (%v)", mapping, err)
+	}
+
+	// A domain mapped to another resource is an error.
+	f.Deploy.Domains = append(f.Deploy.Domains, fn.Domain{Name: "taken.example.com"})
+	if err = reconcileDomainMappings(ctx, client, f, service); err == nil {
+		t.Error("expected an error mapping a domain which is already mapped")
+	}
+
+	// The mappings of a removed function are removed, and only those.
+	if err = deleteDomainMappings(ctx, client, "myfunc"); err != nil {
+		t.Fatal(err)
+	}
+	list, err := client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "taken.example.com" {
+		t.Errorf("expected only the mapping of the other function to remain, got %v", list.Items)
+	}
+}
+
+func Test_waitForDomainMappings(t *testing.T) {
+	ready := &v1beta1.DomainMapping{ObjectMeta: metav1.ObjectMeta{Name: "api.example.com", Namespace: "prod"}}
+	ready.Status.URL = &apis.URL{Scheme: "https", Host: "api.example.com"}
+	ready.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+
+	claimed := &v1beta1.DomainMapping{ObjectMeta: metav1.ObjectMeta{Name: "www.example.com", Namespace: "prod"}}
+	claimed.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionFalse,
+		Reason: "DomainClaimNotOwned", Message: "The domain name is already in use by another namespace."}}
+
+	client := fake.NewSimpleClientset(ready, claimed).ServingV1beta1().DomainMappings("prod")
+	domains := []fn.Domain{{Name: "api.example.com"}, {Name: "www.example.com"}}
+
+	urls, notReady, err := waitForDomainMappings(context.Background(), client, domains, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(urls) != 1 || urls[0] != "https://api.example.com" {
+		t.Errorf("unexpected ready domains %v", urls)
+	}
+	if len(notReady) != 1 || notReady["www.example.com"] != "DomainClaimNotOwned: The domain name is already in use by another namespace." {
+		t.Errorf("unexpected domains which are not ready %v", notReady)
+	}
+}
@@ -0,0 +1,94 @@
+package knative
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s"
+)
+
+// Logger retrieves the logs of the pods of a deployed function's revision.
+type Logger struct {
+	namespace string
+	verbose   bool
+}
+
+// NewLogger creates a logger of functions deployed to the given namespace,
+// or to the namespace of the current kubernetes configuration if empty.
+func NewLogger(namespaceOverride string, verbose bool) *Logger {
+	return &Logger{
+		namespace: namespaceOverride,
+		verbose:   verbose,
+	}
+}
+
+// Logs of the pods of the function's revision, which defaults to its latest
+// ready revision.  Only the function's own container is included unless
+// another container is named in the options.
+func (l *Logger) Logs(ctx context.Context, f fn.Function, opts fn.LogOptions, emit func(fn.LogEntry)) (err error) {
+	client, namespace, err := k8s.NewClientAndResolvedNamespace(l.namespace)
+	if err != nil {
+		return fmt.Errorf("cannot create k8s client: %w", err)
+	}
+
+	revision := opts.Revision
+	if revision == "" {
+		if revision, err = latestReadyRevision(ctx, namespace, f.Name); err != nil {
+			return
+		}
+	}
+	container := opts.Container
+	if container == "" {
+		container = userContainer
+	}
+
+	podOpts := podLogOptions{
+		selector:   fmt.Sprintf("serving.knative.dev/revision=%s", revision),
+		container:  container,
+		since:      opts.Since,
+		follow:     opts.Follow,
+		timestamps: true,
+	}
+	return streamPodLogs(ctx, client, namespace, podOpts, func(pod corev1.Pod, r io.Reader) error {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			emit(parseLogLine(pod.Name, container, scanner.Text()))
+		}
+		return scanner.Err()
+	})
+}
+
+// latestReadyRevision of the named service.
+func latestReadyRevision(ctx context.Context, namespace, name string) (string, error) {
+	client, err := NewServingClient(namespace)
+	if err != nil {
+		return "", err
+	}
+	service, err := client.GetService(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if service.Status.LatestReadyRevisionName == "" {
+		return "", fmt.Errorf("function %v has no ready revision", name)
+	}
+	return service.Status.LatestReadyRevisionName, nil
+}
+
+// parseLogLine into an entry, splitting the timestamp with which the line is
+// prefixed from the message.
+func parseLogLine(pod, container, line string) fn.LogEntry {
+	e := fn.LogEntry{Instance: pod, Container: container, Message: line}
+	if ts, msg, ok := strings.Cut(line, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			e.Time, e.Message = t, msg
+		}
+	}
+	return e
+}
@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
 	"knative.dev/func/k8s"
 )
 
@@ -29,45 +30,71 @@ func GetKServiceLogs(ctx context.Context, namespace, kServiceName, image string,
 		return fmt.Errorf("cannot create k8s client: %w", err)
 	}
 
-	pods := client.CoreV1().Pods(namespace)
-
-	podListOpts := metav1.ListOptions{
-		Watch:         true,
-		LabelSelector: fmt.Sprintf("serving.knative.dev/service=%s", kServiceName),
+	opts := podLogOptions{
+		selector:  fmt.Sprintf("serving.knative.dev/service=%s", kServiceName),
+		container: userContainer,
+		follow:    true,
+		filter: func(pod corev1.Pod) bool {
+			return image == "" || image == containerImage(pod, userContainer)
+		},
 	}
-
-	w, err := pods.Watch(ctx, podListOpts)
-	if err != nil {
-		return fmt.Errorf("cannot create watch: %w", err)
+	if since != nil {
+		opts.since = *since
 	}
-	defer w.Stop()
+	return streamPodLogs(ctx, client, namespace, opts, func(_ corev1.Pod, r io.Reader) error {
+		_, err := io.Copy(out, r)
+		return err
+	})
+}
+
+// userContainer is the name of the container of a Knative service's pods
+// which runs the function.
+const userContainer = "user-container"
+
+// podLogOptions select the pods and container whose logs are streamed.
+type podLogOptions struct {
+	selector   string                // label selector of the pods
+	container  string                // container of each pod
+	since      time.Time             // optional start time of the logs
+	follow     bool                  // follow logs of current and future pods
+	timestamps bool                  // prefix each line with its timestamp
+	filter     func(corev1.Pod) bool // optional additional pod filter
+}
+
+// streamPodLogs passes a stream of the logs of each selected pod to the given
+// copy function.  When following, pods are watched such that the logs of pods
+// started subsequently are also streamed, and the function returns when the
+// context is canceled.  Otherwise the logs of the pods existing at the time of
+// the call are streamed to completion.
+func streamPodLogs(ctx context.Context, client kubernetes.Interface, namespace string, opts podLogOptions, copyLogs func(corev1.Pod, io.Reader) error) error {
+	pods := client.CoreV1().Pods(namespace)
 
 	beingProcessed := make(map[string]bool)
 	var beingProcessedMu sync.Mutex
 
-	copyLogs := func(pod corev1.Pod) error {
+	copyPodLogs := func(pod corev1.Pod) error {
 		defer func() {
 			beingProcessedMu.Lock()
 			delete(beingProcessed, pod.Name)
 			beingProcessedMu.Unlock()
 		}()
 		podLogOpts := corev1.PodLogOptions{
-			Container: "user-container",
-			Follow:    true,
+			Container:  opts.container,
+			Follow:     opts.follow,
+			Timestamps: opts.timestamps,
 		}
-		if since != nil {
-			sinceTime := metav1.NewTime(*since)
+		if !opts.since.IsZero() {
+			sinceTime := metav1.NewTime(opts.since)
 			podLogOpts.SinceTime = &sinceTime
 		}
-		req := client.CoreV1().Pods(namespace).GetLogs(pod.Name, &podLogOpts)
+		req := pods.GetLogs(pod.Name, &podLogOpts)
 
 		r, e := req.Stream(ctx)
 		if e != nil {
 			return fmt.Errorf("cannot get stream: %w", e)
 		}
 		defer r.Close()
-		_, e = io.Copy(out, r)
-		if e != nil {
+		if e = copyLogs(pod, r); e != nil {
 			return fmt.Errorf("error copying logs: %w", e)
 		}
 		return nil
@@ -75,50 +102,68 @@ func GetKServiceLogs(ctx context.Context, namespace, kServiceName, image string,
 
 	mayReadLogs := func(pod corev1.Pod) bool {
 		for _, status := range pod.Status.ContainerStatuses {
-			if status.Name == "user-container" {
+			if status.Name == opts.container {
 				return status.State.Running != nil || status.State.Terminated != nil
 			}
 		}
 		return false
 	}
 
-	getImage := func(pod corev1.Pod) string {
-		for _, ctr := range pod.Spec.Containers {
-			if ctr.Name == "user-container" {
-				return ctr.Image
-			}
-		}
-		return ""
-	}
-
 	var eg errgroup.Group
 
-	for event := range w.ResultChan() {
-		if event.Type == watch.Modified || event.Type == watch.Added {
-			pod := *event.Object.(*corev1.Pod)
+	process := func(pod corev1.Pod) {
+		beingProcessedMu.Lock()
+		_, loggingAlready := beingProcessed[pod.Name]
+		beingProcessedMu.Unlock()
+
+		if !loggingAlready && (opts.filter == nil || opts.filter(pod)) && mayReadLogs(pod) {
 
 			beingProcessedMu.Lock()
-			_, loggingAlready := beingProcessed[pod.Name]
+			beingProcessed[pod.Name] = true
 			beingProcessedMu.Unlock()
 
-			if !loggingAlready && (image == "" || image == getImage(pod)) && mayReadLogs(pod) {
+			eg.Go(func() error { return copyPodLogs(pod) })
+		}
+	}
 
-				beingProcessedMu.Lock()
-				beingProcessed[pod.Name] = true
-				beingProcessedMu.Unlock()
+	if opts.follow {
+		w, err := pods.Watch(ctx, metav1.ListOptions{Watch: true, LabelSelector: opts.selector})
+		if err != nil {
+			return fmt.Errorf("cannot create watch: %w", err)
+		}
+		defer w.Stop()
 
-				eg.Go(func() error { return copyLogs(pod) })
+		for event := range w.ResultChan() {
+			if event.Type == watch.Modified || event.Type == watch.Added {
+				process(*event.Object.(*corev1.Pod))
 			}
 		}
+	} else {
+		list, err := pods.List(ctx, metav1.ListOptions{LabelSelector: opts.selector})
+		if err != nil {
+			return fmt.Errorf("cannot list pods: %w", err)
+		}
+		for _, pod := range list.Items {
+			process(pod)
+		}
 	}
 
-	err = eg.Wait()
-	if err != nil {
+	if err := eg.Wait(); err != nil {
 		return fmt.Errorf("error while gathering logs: %w", err)
 	}
 	return nil
 }
 
+// containerImage returns the image of the named container of the pod.
+func containerImage(pod corev1.Pod, container string) string {
+	for _, ctr := range pod.Spec.Containers {
+		if ctr.Name == container {
+			return ctr.Image
+		}
+	}
+	return ""
+}
+
 type SynchronizedBuffer struct {
 	b  bytes.Buffer
 	mu sync.Mutex
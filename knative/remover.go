@@ -35,9 +35,17 @@ func (remover *Remover) Remove(ctx context.Context, name string) (err error) {
 		return
 	}
 
+	eventingClient, err := NewEventingClient(remover.Namespace)
+	if err != nil {
+		return
+	}
+	if err = deleteTriggers(ctx, eventingClient, name); err != nil {
+		return
+	}
+
 	err = client.DeleteService(ctx, name, RemoveTimeout)
 	if err != nil {
-		err = fmt.Errorf("knative remover failed to delete the service: %v", err)
+		err = fmt.Errorf("knative remover failed to delete the service: %w", err)
 	}
 
 	return
@@ -0,0 +1,78 @@
+package knative
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1beta1"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s"
+)
+
+// Render the Knative Service, Triggers and DomainMappings with which the function would be
+// deployed, as they would be created, without connecting to the cluster.
+// The Secrets, ConfigMaps and PersistentVolumeClaims it references are listed.
+// Objects are rendered in the deployer's namespace, defaulting to that of
+// the function, or if neither is set without a namespace such that they are
+// applied to the current namespace.  The deployer's decorator is not applied,
+// as it may itself connect to the cluster, such as to detect OpenShift.
+func (d *Deployer) Render(ctx context.Context, f fn.Function) (fn.Manifests, error) {
+	namespace := d.Namespace
+	if namespace == "" {
+		namespace = f.Deploy.Namespace
+	}
+	return render(f, namespace, nil)
+}
+
+func render(f fn.Function, namespace string, decorator DeployDecorator) (m fn.Manifests, err error) {
+	service, err := generateNewService(f, decorator)
+	if err != nil {
+		return m, fmt.Errorf("knative deployer failed to generate the Knative Service: %v", err)
+	}
+	service.TypeMeta = metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: "Service"}
+	service.Namespace = namespace
+	m.Objects = append(m.Objects, service)
+
+	triggers, err := generateTriggers(f, service)
+	if err != nil {
+		return m, fmt.Errorf("knative deployer failed to generate the Knative Triggers: %v", err)
+	}
+	for i := range triggers {
+		trigger := &triggers[i]
+		trigger.TypeMeta = metav1.TypeMeta{APIVersion: eventingv1.SchemeGroupVersion.String(), Kind: "Trigger"}
+		trigger.Namespace = namespace
+		// The owner's UID is only known once the service is created.
+		trigger.OwnerReferences = nil
+		m.Objects = append(m.Objects, trigger)
+	}
+
+	for _, domain := range f.Deploy.Domains {
+		mapping := generateDomainMapping(f, domain, service)
+		mapping.TypeMeta = metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "DomainMapping"}
+		mapping.Namespace = namespace
+		// As for triggers, the owner is only known once the service is created.
+		mapping.OwnerReferences = nil
+		m.Objects = append(m.Objects, mapping)
+	}
+
+	referencedSecrets := sets.NewString()
+	referencedConfigMaps := sets.NewString()
+	if _, _, err = k8s.ProcessEnvs(f.Run.Envs, &referencedSecrets, &referencedConfigMaps); err != nil {
+		return
+	}
+	if _, _, err = k8s.ProcessVolumes(f.Run.Volumes, &referencedSecrets, &referencedConfigMaps); err != nil {
+		return
+	}
+	if _, _, _, err = k8s.ProcessContainers(f.Deploy, nil, &referencedSecrets, &referencedConfigMaps); err != nil {
+		return
+	}
+	m.Secrets = referencedSecrets.List()
+	m.ConfigMaps = referencedConfigMaps.List()
+	m.PersistentVolumeClaims = k8s.ReferencedClaims(f)
+	return
+}
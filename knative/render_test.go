@@ -0,0 +1,199 @@
+//go:build !integration
+// +build !integration
+
+package knative
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/pkg/ptr"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1beta1"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s"
+)
+
+func Test_render(t *testing.T) {
+	f := fn.Function{
+		Name:  "testing",
+		Image: "example.com/alice/testing:latest",
+		Run: fn.RunSpec{
+			Envs: []fn.Env{
+				{Name: ptr.String("API_KEY"), Value: ptr.String("{{ secret:credentials:key }}")},
+				{Value: ptr.String("{{ configMap:settings }}")},
+			},
+			Volumes: []fn.Volume{
+				{Secret: ptr.String("certs"), Path: ptr.String("/certs")},
+			},
+		},
+		Deploy: fn.DeploySpec{
+			Subscriptions: []fn.KnativeSubscription{
+				{Filters: map[string]string{"type": "com.example.order.created"}},
+			},
+		},
+	}
+
+	m, err := render(f, "ns", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Objects) != 2 {
+		t.Fatalf("expected a service and a trigger, got %d objects", len(m.Objects))
+	}
+
+	service, ok := m.Objects[0].(*v1.Service)
+	if !ok {
+		t.Fatalf("expected a Knative Service, got %T", m.Objects[0])
+	}
+	if service.APIVersion != "serving.knative.dev/v1" || service.Kind != "Service" {
+		t.Errorf("unexpected type of service: %v %v", service.APIVersion, service.Kind)
+	}
+	if service.Name != "testing" || service.Namespace != "ns" {
+		t.Errorf("unexpected service %v/%v", service.Namespace, service.Name)
+	}
+	if image := service.Spec.Template.Spec.Containers[0].Image; image != f.Image {
+		t.Errorf("expected image %v, got %v", f.Image, image)
+	}
+
+	trigger, ok := m.Objects[1].(*eventingv1.Trigger)
+	if !ok {
+		t.Fatalf("expected a Knative Trigger, got %T", m.Objects[1])
+	}
+	if trigger.APIVersion != "eventing.knative.dev/v1" || trigger.Kind != "Trigger" || trigger.Namespace != "ns" {
+		t.Errorf("unexpected trigger %v %v in %v", trigger.APIVersion, trigger.Kind, trigger.Namespace)
+	}
+	if len(trigger.OwnerReferences) != 0 {
+		t.Errorf("expected a rendered trigger to have no owner, got %v", trigger.OwnerReferences)
+	}
+
+	if len(m.Secrets) != 2 || m.Secrets[0] != "certs" || m.Secrets[1] != "credentials" {
+		t.Errorf("unexpected referenced secrets %v", m.Secrets)
+	}
+	if len(m.ConfigMaps) != 1 || m.ConfigMaps[0] != "settings" {
+		t.Errorf("unexpected referenced config maps %v", m.ConfigMaps)
+	}
+}
+
+func Test_render_Domains(t *testing.T) {
+	f := fn.Function{
+		Name:   "testing",
+		Image:  "example.com/alice/testing:latest",
+		Deploy: fn.DeploySpec{Domains: []fn.Domain{{Name: "api.example.com"}}},
+	}
+
+	m, err := render(f, "ns", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Objects) != 2 {
+		t.Fatalf("expected a service and a domain mapping, got %d objects", len(m.Objects))
+	}
+	mapping, ok := m.Objects[1].(*v1beta1.DomainMapping)
+	if !ok {
+		t.Fatalf("expected a Knative DomainMapping, got %T", m.Objects[1])
+	}
+	if mapping.APIVersion != "serving.knative.dev/v1beta1" || mapping.Kind != "DomainMapping" ||
+		mapping.Name != "api.example.com" || mapping.Namespace != "ns" {
+		t.Errorf("unexpected domain mapping %v %v %v/%v", mapping.APIVersion, mapping.Kind, mapping.Namespace, mapping.Name)
+	}
+	if mapping.Spec.Ref.Name != "testing" || mapping.Spec.Ref.Namespace != "ns" || len(mapping.OwnerReferences) != 0 {
+		t.Errorf("unexpected domain mapping %+v", mapping)
+	}
+}
+
+func Test_render_PersistentVolumeClaims(t *testing.T) {
+	f := fn.Function{
+		Name:  "testing",
+		Image: "example.com/alice/testing:latest",
+		Run: fn.RunSpec{
+			Volumes: []fn.Volume{
+				{PersistentVolumeClaim: &fn.PersistentVolumeClaim{ClaimName: ptr.String("data")}, Path: ptr.String("/data")},
+				{PersistentVolumeClaim: &fn.PersistentVolumeClaim{ClaimName: ptr.String("data"), ReadOnly: true}, Path: ptr.String("/ro")},
+			},
+		},
+		Deploy: fn.DeploySpec{
+			Sidecars: []fn.Container{{
+				Name:    "exporter",
+				Image:   "example.com/alice/exporter:latest",
+				Volumes: []fn.Volume{{PersistentVolumeClaim: &fn.PersistentVolumeClaim{ClaimName: ptr.String("archive")}, Path: ptr.String("/archive")}},
+			}},
+		},
+	}
+
+	m, err := render(f, "ns", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.PersistentVolumeClaims) != 2 || m.PersistentVolumeClaims[0] != "archive" || m.PersistentVolumeClaims[1] != "data" {
+		t.Errorf("unexpected referenced persistent volume claims %v", m.PersistentVolumeClaims)
+	}
+}
+
+// TestDeployer_Render ensures that rendering makes no request of the cluster,
+// including by the deployer's decorator, which may itself connect to it such
+// as to detect OpenShift.
+func TestDeployer_Render(t *testing.T) {
+	requests := 0
+	cluster := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer cluster.Close()
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: `+cluster.URL+`
+contexts:
+- name: context
+  context:
+    cluster: cluster
+current-context: context
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+
+	decorator := &clusterDecorator{}
+	d := NewDeployer(WithDeployerDecorator(decorator))
+	f := fn.Function{Name: "testing", Image: "example.com/alice/testing:latest"}
+	if _, err := d.Render(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 0 || decorator.requests != 0 {
+		t.Fatalf("expected no requests of the cluster, got %v", requests+decorator.requests)
+	}
+}
+
+// clusterDecorator requests the services of the cluster when decorating, as
+// does that detecting OpenShift.
+type clusterDecorator struct {
+	requests int
+}
+
+func (d *clusterDecorator) request() {
+	d.requests++
+	if c, err := k8s.NewKubernetesClientset(); err == nil {
+		_, _ = c.CoreV1().Services("default").List(context.Background(), metav1.ListOptions{})
+	}
+}
+
+func (d *clusterDecorator) UpdateAnnotations(_ fn.Function, annotations map[string]string) map[string]string {
+	d.request()
+	return annotations
+}
+
+func (d *clusterDecorator) UpdateLabels(_ fn.Function, labels map[string]string) map[string]string {
+	d.request()
+	return labels
+}
@@ -0,0 +1,152 @@
+package knative
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/wait"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s"
+)
+
+// Rollbacker of deployed functions to their previous Knative Revisions.
+type Rollbacker struct {
+	namespace string
+	verbose   bool
+}
+
+func NewRollbacker(namespaceOverride string, verbose bool) *Rollbacker {
+	return &Rollbacker{
+		namespace: namespaceOverride,
+		verbose:   verbose,
+	}
+}
+
+// Revisions of the named function's Knative Service, newest first.
+func (r *Rollbacker) Revisions(ctx context.Context, name string) ([]fn.Revision, error) {
+	client, err := r.client()
+	if err != nil {
+		return nil, err
+	}
+	return listRevisions(ctx, client, name)
+}
+
+// Rollback the named function by routing all of its traffic to the given
+// revision.  Returns once the service is ready.
+func (r *Rollbacker) Rollback(ctx context.Context, name, revision string) (fn.Revision, error) {
+	client, err := r.client()
+	if err != nil {
+		return fn.Revision{}, err
+	}
+	return rollback(ctx, client, name, revision)
+}
+
+func (r *Rollbacker) client() (clientservingv1.KnServingClient, error) {
+	if r.namespace == "" {
+		namespace, err := k8s.GetNamespace(r.namespace)
+		if err != nil {
+			return nil, err
+		}
+		r.namespace = namespace
+	}
+	return NewServingClient(r.namespace)
+}
+
+// listRevisions of the named service, newest first, with the percentage of
+// the service's traffic currently routed to each.
+func listRevisions(ctx context.Context, client clientservingv1.KnServingClient, name string) ([]fn.Revision, error) {
+	service, err := client.GetService(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("knative rollbacker failed to get the Knative Service: %v", err)
+	}
+	list, err := client.ListRevisions(ctx, clientservingv1.WithService(name))
+	if err != nil {
+		return nil, fmt.Errorf("knative rollbacker failed to list the Revisions: %v", err)
+	}
+
+	traffic := map[string]int64{}
+	for _, t := range service.Status.Traffic {
+		if t.Percent != nil {
+			traffic[t.RevisionName] += *t.Percent
+		}
+	}
+
+	items := list.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		ti, tj := items[i].CreationTimestamp, items[j].CreationTimestamp
+		if ti.Equal(&tj) {
+			return items[i].Name > items[j].Name
+		}
+		return tj.Before(&ti)
+	})
+	revisions := make([]fn.Revision, 0, len(items))
+	for _, item := range items {
+		revisions = append(revisions, fn.Revision{
+			Name:      item.Name,
+			Image:     revisionImage(item),
+			Created:   item.CreationTimestamp.Time,
+			GitCommit: item.Annotations[fn.GitCommitAnnotation],
+			Traffic:   traffic[item.Name],
+		})
+	}
+	return revisions, nil
+}
+
+// revisionImage is the image of the revision's function container, resolved
+// to its digest when known.
+func revisionImage(r v1.Revision) string {
+	if len(r.Status.ContainerStatuses) > 0 && r.Status.ContainerStatuses[0].ImageDigest != "" {
+		return r.Status.ContainerStatuses[0].ImageDigest
+	}
+	if len(r.Spec.Containers) > 0 {
+		return r.Spec.Containers[0].Image
+	}
+	return ""
+}
+
+// rollback the named service by routing all of its traffic to the given
+// revision.  Subsequent revisions receive no traffic until the function is
+// next deployed.
+func rollback(ctx context.Context, client clientservingv1.KnServingClient, name, revision string) (fn.Revision, error) {
+	revisions, err := listRevisions(ctx, client, name)
+	if err != nil {
+		return fn.Revision{}, err
+	}
+	var target *fn.Revision
+	for i := range revisions {
+		if revisions[i].Name == revision {
+			target = &revisions[i]
+			break
+		}
+	}
+	if target == nil {
+		return fn.Revision{}, fmt.Errorf("%w: %v is not a revision of %v", fn.ErrRevisionNotFound, revision, name)
+	}
+
+	_, err = client.UpdateServiceWithRetry(ctx, name, func(service *v1.Service) (*v1.Service, error) {
+		traffic := []fn.TrafficTarget{{Revision: revision, Percent: 100}}
+		// Tagged revisions retain their tags, and so their URLs
+		for _, t := range toTraffic(service.Status.Traffic) {
+			if t.Tag != "" {
+				traffic = append(traffic, fn.TrafficTarget{Revision: t.Revision, Tag: t.Tag})
+			}
+		}
+		service.Spec.Traffic = fromTraffic(traffic)
+		return service, nil
+	}, 3)
+	if err != nil {
+		return fn.Revision{}, fmt.Errorf("knative rollbacker failed to update the Knative Service: %v", err)
+	}
+	err, _ = client.WaitForService(ctx, name,
+		clientservingv1.WaitConfig{Timeout: DefaultWaitingTimeout, ErrorWindow: DefaultErrorWindowTimeout},
+		wait.NoopMessageCallback())
+	if err != nil {
+		return fn.Revision{}, err
+	}
+	target.Traffic = 100
+	return *target, nil
+}
@@ -0,0 +1,105 @@
+//go:build !integration
+// +build !integration
+
+package knative
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/pkg/ptr"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	fn "knative.dev/func"
+)
+
+func testRevisions() (*v1.Service, *v1.RevisionList) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	revision := func(name string, age time.Duration, commit string) v1.Revision {
+		r := v1.Revision{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created.Add(-age)),
+			Annotations:       map[string]string{},
+		}}
+		r.Spec.Containers = append(r.Spec.Containers, corev1.Container{Image: "example.com/alice/myfunc:latest"})
+		r.Status.ContainerStatuses = []v1.ContainerStatus{{Name: "user-container", ImageDigest: "example.com/alice/myfunc@sha256:" + name}}
+		if commit != "" {
+			r.Annotations[fn.GitCommitAnnotation] = commit
+		}
+		return r
+	}
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "myfunc"}}
+	service.Status.Traffic = []v1.TrafficTarget{{RevisionName: "myfunc-00002", Percent: ptr.Int64(100)}}
+	return service, &v1.RevisionList{Items: []v1.Revision{
+		revision("myfunc-00001", 2*time.Hour, ""),
+		revision("myfunc-00003", 0, "c3"),
+		revision("myfunc-00002", time.Hour, "c2"),
+	}}
+}
+
+// Test_listRevisions ensures that revisions are listed newest first with
+// their image digest, commit and traffic.
+func Test_listRevisions(t *testing.T) {
+	service, list := testRevisions()
+	client := clientservingv1.NewMockKnServiceClient(t)
+	recorder := client.Recorder()
+	recorder.GetService("myfunc", service, nil)
+	recorder.ListRevisions(mock.Any(), list, nil)
+
+	revisions, err := listRevisions(context.Background(), client, "myfunc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.Validate()
+
+	if len(revisions) != 3 || revisions[0].Name != "myfunc-00003" || revisions[2].Name != "myfunc-00001" {
+		t.Fatalf("expected revisions newest first, got %+v", revisions)
+	}
+	r := revisions[1]
+	if r.Image != "example.com/alice/myfunc@sha256:myfunc-00002" || r.GitCommit != "c2" || r.Traffic != 100 {
+		t.Fatalf("unexpected revision %+v", r)
+	}
+	if revisions[0].Traffic != 0 || revisions[2].GitCommit != "" {
+		t.Fatalf("unexpected revisions %+v", revisions)
+	}
+}
+
+// Test_rollback ensures that all traffic is routed to the chosen revision,
+// and that an unknown revision is reported as such.
+func Test_rollback(t *testing.T) {
+	service, list := testRevisions()
+	client := clientservingv1.NewMockKnServiceClient(t)
+	recorder := client.Recorder()
+	recorder.GetService("myfunc", service, nil)
+	recorder.ListRevisions(mock.Any(), list, nil)
+	recorder.GetService("myfunc", service, nil)
+	recorder.UpdateService(func(t *testing.T, a interface{}) {
+		traffic := a.(*v1.Service).Spec.Traffic
+		if len(traffic) != 1 || traffic[0].RevisionName != "myfunc-00001" ||
+			*traffic[0].Percent != 100 || *traffic[0].LatestRevision {
+			t.Errorf("expected all traffic routed to myfunc-00001, got %+v", traffic)
+		}
+	}, true, nil)
+	recorder.WaitForService("myfunc", mock.Any(), mock.Any(), nil, time.Second)
+
+	r, err := rollback(context.Background(), client, "myfunc", "myfunc-00001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Name != "myfunc-00001" || r.Traffic != 100 {
+		t.Fatalf("unexpected revision %+v", r)
+	}
+
+	recorder.GetService("myfunc", service, nil)
+	recorder.ListRevisions(mock.Any(), list, nil)
+	if _, err = rollback(context.Background(), client, "myfunc", "other-00001"); !errors.Is(err, fn.ErrRevisionNotFound) {
+		t.Fatalf("expected ErrRevisionNotFound, got %v", err)
+	}
+	recorder.Validate()
+}
@@ -0,0 +1,138 @@
+package knative
+
+import (
+	"context"
+	"fmt"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/wait"
+	"knative.dev/pkg/ptr"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s"
+)
+
+// TrafficSplitter of functions' traffic among their Knative Revisions.
+type TrafficSplitter struct {
+	namespace string
+	verbose   bool
+}
+
+func NewTrafficSplitter(namespaceOverride string, verbose bool) *TrafficSplitter {
+	return &TrafficSplitter{
+		namespace: namespaceOverride,
+		verbose:   verbose,
+	}
+}
+
+// Split the named function's traffic among its revisions as given.  Returns
+// once the service is ready.
+func (s *TrafficSplitter) Split(ctx context.Context, name string, targets []fn.TrafficTarget) error {
+	if s.namespace == "" {
+		namespace, err := k8s.GetNamespace(s.namespace)
+		if err != nil {
+			return err
+		}
+		s.namespace = namespace
+	}
+	client, err := NewServingClient(s.namespace)
+	if err != nil {
+		return err
+	}
+	return split(ctx, client, name, targets)
+}
+
+// split the traffic of the named service among the given targets.
+func split(ctx context.Context, client clientservingv1.KnServingClient, name string, targets []fn.TrafficTarget) error {
+	_, err := client.UpdateServiceWithRetry(ctx, name, func(service *v1.Service) (*v1.Service, error) {
+		service.Spec.Traffic = fromTraffic(targets)
+		return service, nil
+	}, 3)
+	if err != nil {
+		return fmt.Errorf("knative traffic splitter failed to update the Knative Service: %v", err)
+	}
+	err, _ = client.WaitForService(ctx, name,
+		clientservingv1.WaitConfig{Timeout: DefaultWaitingTimeout, ErrorWindow: DefaultErrorWindowTimeout},
+		wait.NoopMessageCallback())
+	return err
+}
+
+// toTraffic returns the function traffic targets of a service's traffic.
+func toTraffic(targets []v1.TrafficTarget) []fn.TrafficTarget {
+	traffic := make([]fn.TrafficTarget, 0, len(targets))
+	for _, t := range targets {
+		target := fn.TrafficTarget{
+			Revision: t.RevisionName,
+			Latest:   t.LatestRevision != nil && *t.LatestRevision,
+			Tag:      t.Tag,
+		}
+		if t.Percent != nil {
+			target.Percent = *t.Percent
+		}
+		if t.URL != nil {
+			target.URL = t.URL.String()
+		}
+		traffic = append(traffic, target)
+	}
+	return traffic
+}
+
+// fromTraffic returns the service traffic of function traffic targets.
+func fromTraffic(targets []fn.TrafficTarget) []v1.TrafficTarget {
+	traffic := make([]v1.TrafficTarget, 0, len(targets))
+	for _, t := range targets {
+		target := v1.TrafficTarget{
+			Tag:            t.Tag,
+			LatestRevision: ptr.Bool(t.Latest),
+			Percent:        ptr.Int64(t.Percent),
+		}
+		if !t.Latest {
+			target.RevisionName = t.Revision
+		}
+		traffic = append(traffic, target)
+	}
+	return traffic
+}
+
+// deployTraffic routes the traffic of a service whose latest revision is
+// being deployed according to the function's traffic options.  By default
+// all traffic is routed to the latest revision.  Otherwise the latest
+// revision receives the given percentage, with the remainder staying with
+// the revisions currently serving in proportion.  Revisions retain their
+// tags, and so their URLs, unless the tag is moved to the latest revision.
+func deployTraffic(service *v1.Service, opts *fn.TrafficOptions) {
+	var (
+		tag     string
+		percent = int64(100)
+	)
+	if opts != nil {
+		tag = opts.Tag
+		if opts.Percent != nil {
+			percent = *opts.Percent
+		}
+	}
+
+	// The revisions currently serving are pinned, as the latest will change.
+	var serving int64
+	current := toTraffic(service.Status.Traffic)
+	for i := range current {
+		current[i].Latest = false
+		current[i].URL = ""
+		if current[i].Tag == tag {
+			current[i].Tag = ""
+		}
+		serving += current[i].Percent
+	}
+	if serving == 0 {
+		percent = 100
+	}
+	others := fn.ScaleTraffic(current, 100-percent)
+
+	if tag == "" && len(others) == 0 {
+		service.Spec.Traffic = nil // defaults to all traffic to the latest
+		return
+	}
+	latest := fn.TrafficTarget{Latest: true, Tag: tag, Percent: percent}
+	service.Spec.Traffic = fromTraffic(append([]fn.TrafficTarget{latest}, others...))
+}
@@ -0,0 +1,86 @@
+//go:build !integration
+// +build !integration
+
+package knative
+
+import (
+	"reflect"
+	"testing"
+
+	"knative.dev/pkg/ptr"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	fn "knative.dev/func"
+)
+
+// Test_deployTraffic ensures that the traffic of a service being deployed is
+// routed according to the function's traffic options.
+func Test_deployTraffic(t *testing.T) {
+	canary := func(percent int64) *fn.TrafficOptions {
+		return &fn.TrafficOptions{Tag: "canary", Percent: ptr.Int64(percent)}
+	}
+	target := func(revision, tag string, latest bool, percent int64) v1.TrafficTarget {
+		return v1.TrafficTarget{RevisionName: revision, Tag: tag, LatestRevision: ptr.Bool(latest), Percent: ptr.Int64(percent)}
+	}
+
+	tests := []struct {
+		name     string
+		options  *fn.TrafficOptions
+		status   []v1.TrafficTarget
+		expected []v1.TrafficTarget
+	}{
+		{
+			name:     "default routes all traffic to the latest",
+			status:   []v1.TrafficTarget{target("f-00001", "", true, 100)},
+			expected: nil,
+		},
+		{
+			name:     "new service",
+			options:  canary(10),
+			expected: []v1.TrafficTarget{target("", "canary", true, 100)},
+		},
+		{
+			name:    "canary",
+			options: canary(10),
+			status:  []v1.TrafficTarget{target("f-00001", "", true, 100)},
+			expected: []v1.TrafficTarget{
+				target("", "canary", true, 10),
+				target("f-00001", "", false, 90),
+			},
+		},
+		{
+			name:    "tag moved to the latest",
+			options: canary(20),
+			status: []v1.TrafficTarget{
+				target("f-00002", "canary", true, 10),
+				target("f-00001", "", false, 90),
+			},
+			expected: []v1.TrafficTarget{
+				target("", "canary", true, 20),
+				target("f-00002", "", false, 8),
+				target("f-00001", "", false, 72),
+			},
+		},
+		{
+			name: "default retains other tags",
+			status: []v1.TrafficTarget{
+				target("f-00001", "", true, 100),
+				target("f-00001", "stable", false, 0),
+			},
+			expected: []v1.TrafficTarget{
+				target("", "", true, 100),
+				target("f-00001", "stable", false, 0),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &v1.Service{}
+			service.Status.Traffic = tt.status
+			deployTraffic(service, tt.options)
+			if !reflect.DeepEqual(service.Spec.Traffic, tt.expected) {
+				t.Fatalf("expected traffic %+v, got %+v", tt.expected, service.Spec.Traffic)
+			}
+		})
+	}
+}
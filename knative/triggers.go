@@ -0,0 +1,183 @@
+package knative
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clienteventingv1 "knative.dev/client/pkg/eventing/v1"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s/labels"
+)
+
+// generateTriggers returns a Trigger of the given service for each of the
+// function's subscriptions.  Triggers are labeled with the function's name
+// and owned by its service.  A trigger's name is derived from its
+// subscription, such that a change to a subscription results in the trigger
+// being replaced rather than updated (its broker is immutable).
+func generateTriggers(f fn.Function, service *v1.Service) ([]eventingv1.Trigger, error) {
+	triggers := make([]eventingv1.Trigger, 0, len(f.Deploy.Subscriptions))
+	for _, s := range f.Deploy.Subscriptions {
+		name, err := triggerName(f.Name, s)
+		if err != nil {
+			return nil, err
+		}
+		broker := s.Broker
+		if broker == "" {
+			broker = fn.DefaultBroker
+		}
+		trigger := eventingv1.Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{labels.FunctionNameKey: f.Name},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: v1.SchemeGroupVersion.String(),
+					Kind:       "Service",
+					Name:       service.Name,
+					UID:        service.UID,
+				}},
+			},
+			Spec: eventingv1.TriggerSpec{
+				Broker: broker,
+				Subscriber: duckv1.Destination{
+					Ref: serviceRef(f.Name),
+				},
+			},
+		}
+		if len(s.Filters) > 0 {
+			trigger.Spec.Filter = &eventingv1.TriggerFilter{
+				Attributes: eventingv1.TriggerFilterAttributes(s.Filters),
+			}
+		}
+		if s.Delivery != nil {
+			trigger.Spec.Delivery = deliverySpec(*s.Delivery)
+		}
+		triggers = append(triggers, trigger)
+	}
+	return triggers, nil
+}
+
+// triggerName for the given subscription of the named function.
+func triggerName(function string, s fn.KnativeSubscription) (string, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(b)
+	return fmt.Sprintf("%s-trigger-%x", function, hash[:4]), nil
+}
+
+// serviceRef is a reference to the named Knative Service.
+func serviceRef(name string) *duckv1.KReference {
+	return &duckv1.KReference{
+		APIVersion: v1.SchemeGroupVersion.String(),
+		Kind:       "Service",
+		Name:       name,
+	}
+}
+
+// deliverySpec of a trigger from the delivery options of a subscription.
+func deliverySpec(d fn.SubscriptionDelivery) *eventingduckv1.DeliverySpec {
+	spec := &eventingduckv1.DeliverySpec{Retry: d.Retry}
+	if d.BackoffPolicy != "" {
+		policy := eventingduckv1.BackoffPolicyType(d.BackoffPolicy)
+		spec.BackoffPolicy = &policy
+	}
+	if d.BackoffDelay != "" {
+		delay := d.BackoffDelay
+		spec.BackoffDelay = &delay
+	}
+	if d.DeadLetterSink != "" {
+		if u, err := url.Parse(d.DeadLetterSink); err == nil && u.IsAbs() {
+			spec.DeadLetterSink = &duckv1.Destination{URI: (*apis.URL)(u)}
+		} else {
+			spec.DeadLetterSink = &duckv1.Destination{Ref: serviceRef(d.DeadLetterSink)}
+		}
+	}
+	return spec
+}
+
+// reconcileTriggers creates a Trigger for each of the function's subscriptions
+// which does not yet exist, and deletes those triggers of the function which
+// are no longer subscribed.
+func reconcileTriggers(ctx context.Context, client clienteventingv1.KnEventingClient, f fn.Function, service *v1.Service) error {
+	desired, err := generateTriggers(f, service)
+	if err != nil {
+		return err
+	}
+	list, err := client.ListTriggers(ctx)
+	if err != nil {
+		if len(desired) == 0 {
+			return nil // Eventing is probably not installed, and not required
+		}
+		return fmt.Errorf("knative deployer failed to list the Triggers: %v", err)
+	}
+
+	existing := map[string]bool{}
+	for _, t := range list.Items {
+		if t.Labels[labels.FunctionNameKey] == f.Name {
+			existing[t.Name] = true
+		}
+	}
+	wanted := map[string]bool{}
+	for i := range desired {
+		name := desired[i].Name
+		if wanted[name] || existing[name] {
+			wanted[name] = true // duplicate subscription or already exists
+			continue
+		}
+		wanted[name] = true
+		if err = client.CreateTrigger(ctx, &desired[i]); err != nil {
+			return fmt.Errorf("knative deployer failed to create the Trigger: %v", err)
+		}
+	}
+	for name := range existing {
+		if wanted[name] {
+			continue
+		}
+		if err = client.DeleteTrigger(ctx, name); err != nil {
+			return fmt.Errorf("knative deployer failed to delete the stale Trigger: %v", err)
+		}
+	}
+	return nil
+}
+
+// updateTriggers of the deployed function to match its subscriptions.
+func (d *Deployer) updateTriggers(ctx context.Context, client clientservingv1.KnServingClient, f fn.Function) error {
+	service, err := client.GetService(ctx, f.Name)
+	if err != nil {
+		return fmt.Errorf("knative deployer failed to get the Knative Service: %v", err)
+	}
+	eventingClient, err := NewEventingClient(d.Namespace)
+	if err != nil {
+		return err
+	}
+	return reconcileTriggers(ctx, eventingClient, f, service)
+}
+
+// deleteTriggers of the named function.
+func deleteTriggers(ctx context.Context, client clienteventingv1.KnEventingClient, name string) error {
+	list, err := client.ListTriggers(ctx)
+	if err != nil {
+		return nil // Eventing is probably not installed, so there are none
+	}
+	for _, t := range list.Items {
+		if t.Labels[labels.FunctionNameKey] != name {
+			continue
+		}
+		if err = client.DeleteTrigger(ctx, t.Name); err != nil {
+			return fmt.Errorf("knative remover failed to delete the Trigger: %v", err)
+		}
+	}
+	return nil
+}
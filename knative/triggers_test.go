@@ -0,0 +1,125 @@
+//go:build !integration
+// +build !integration
+
+package knative
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clienteventingv1 "knative.dev/client/pkg/eventing/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	fn "knative.dev/func"
+	"knative.dev/func/k8s/labels"
+)
+
+func Test_generateTriggers(t *testing.T) {
+	retry := int32(3)
+	f := fn.Function{
+		Name: "testing",
+		Deploy: fn.DeploySpec{
+			Subscriptions: []fn.KnativeSubscription{
+				{Filters: map[string]string{"type": "com.example.order.created"}},
+				{
+					Broker: "orders",
+					Delivery: &fn.SubscriptionDelivery{
+						Retry:          &retry,
+						BackoffPolicy:  "linear",
+						DeadLetterSink: "https://example.com/dls",
+					},
+				},
+			},
+		},
+	}
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "testing", UID: types.UID("uid")}}
+
+	triggers, err := generateTriggers(f, service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggers) != 2 {
+		t.Fatalf("expected 2 triggers, got %v", len(triggers))
+	}
+	if triggers[0].Name == triggers[1].Name {
+		t.Fatalf("expected distinct trigger names, got %v", triggers[0].Name)
+	}
+
+	// Default broker, filters, and a subscriber of the function's service
+	first := triggers[0]
+	if first.Spec.Broker != fn.DefaultBroker {
+		t.Errorf("expected broker '%v', got '%v'", fn.DefaultBroker, first.Spec.Broker)
+	}
+	if first.Spec.Filter == nil || first.Spec.Filter.Attributes["type"] != "com.example.order.created" {
+		t.Errorf("expected type filter, got %+v", first.Spec.Filter)
+	}
+	if first.Spec.Subscriber.Ref == nil || first.Spec.Subscriber.Ref.Name != "testing" || first.Spec.Subscriber.Ref.Kind != "Service" {
+		t.Errorf("expected the function's service as subscriber, got %+v", first.Spec.Subscriber)
+	}
+	if first.Spec.Delivery != nil {
+		t.Errorf("expected no delivery options, got %+v", first.Spec.Delivery)
+	}
+
+	// Owned by the service and labeled with the function's name
+	if first.Labels[labels.FunctionNameKey] != "testing" {
+		t.Errorf("expected function name label, got %v", first.Labels)
+	}
+	if len(first.OwnerReferences) != 1 || first.OwnerReferences[0].UID != "uid" {
+		t.Errorf("expected owner reference to the service, got %v", first.OwnerReferences)
+	}
+
+	// Delivery options
+	second := triggers[1]
+	if second.Spec.Broker != "orders" {
+		t.Errorf("expected broker 'orders', got '%v'", second.Spec.Broker)
+	}
+	d := second.Spec.Delivery
+	if d == nil || *d.Retry != 3 || string(*d.BackoffPolicy) != "linear" ||
+		d.DeadLetterSink == nil || d.DeadLetterSink.URI.String() != "https://example.com/dls" {
+		t.Errorf("unexpected delivery options %+v", d)
+	}
+}
+
+// Test_reconcileTriggers ensures that triggers are created for new
+// subscriptions, and that stale triggers of the function are deleted while
+// those of other functions are untouched.
+func Test_reconcileTriggers(t *testing.T) {
+	f := fn.Function{
+		Name: "testing",
+		Deploy: fn.DeploySpec{
+			Subscriptions: []fn.KnativeSubscription{
+				{Filters: map[string]string{"type": "existing"}},
+				{Filters: map[string]string{"type": "new"}},
+			},
+		},
+	}
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "testing"}}
+	desired, err := generateTriggers(f, service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trigger := func(name, function string) eventingv1.Trigger {
+		return eventingv1.Trigger{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{labels.FunctionNameKey: function},
+		}}
+	}
+
+	client := clienteventingv1.NewMockKnEventingClient(t)
+	recorder := client.Recorder()
+	recorder.ListTriggers(&eventingv1.TriggerList{Items: []eventingv1.Trigger{
+		trigger(desired[0].Name, "testing"),
+		trigger("testing-trigger-stale", "testing"),
+		trigger("other-trigger", "other"),
+	}}, nil)
+	recorder.CreateTrigger(&desired[1], nil)
+	recorder.DeleteTrigger("testing-trigger-stale", nil)
+
+	if err := reconcileTriggers(context.Background(), client, f, service); err != nil {
+		t.Fatal(err)
+	}
+	recorder.Validate()
+}
@@ -0,0 +1,31 @@
+package mock
+
+import (
+	"context"
+
+	fn "knative.dev/func"
+)
+
+type Archiver struct {
+	SaveInvoked bool
+	SaveFn      func(fn.Function, string) (string, error)
+	LoadInvoked bool
+	LoadFn      func(fn.Function, string) (string, error)
+}
+
+func NewArchiver() *Archiver {
+	return &Archiver{
+		SaveFn: func(fn.Function, string) (string, error) { return "", nil },
+		LoadFn: func(fn.Function, string) (string, error) { return "", nil },
+	}
+}
+
+func (i *Archiver) Save(ctx context.Context, f fn.Function, output string) (string, error) {
+	i.SaveInvoked = true
+	return i.SaveFn(f, output)
+}
+
+func (i *Archiver) Load(ctx context.Context, f fn.Function, from string) (string, error) {
+	i.LoadInvoked = true
+	return i.LoadFn(f, from)
+}
@@ -9,11 +9,14 @@ import (
 type Deployer struct {
 	DeployInvoked bool
 	DeployFn      func(context.Context, fn.Function) (fn.DeploymentResult, error)
+	RenderInvoked bool
+	RenderFn      func(context.Context, fn.Function) (fn.Manifests, error)
 }
 
 func NewDeployer() *Deployer {
 	return &Deployer{
 		DeployFn: func(context.Context, fn.Function) (fn.DeploymentResult, error) { return fn.DeploymentResult{}, nil },
+		RenderFn: func(context.Context, fn.Function) (fn.Manifests, error) { return fn.Manifests{}, nil },
 	}
 }
 
@@ -22,11 +25,17 @@ func (i *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResu
 	return i.DeployFn(ctx, f)
 }
 
+func (i *Deployer) Render(ctx context.Context, f fn.Function) (fn.Manifests, error) {
+	i.RenderInvoked = true
+	return i.RenderFn(ctx, f)
+}
+
 // NewDeployerWithResult is a convenience method for creating a mock deployer
 // with a deploy function implementation which returns the given result
 // and no error.
 func NewDeployerWithResult(result fn.DeploymentResult) *Deployer {
 	return &Deployer{
 		DeployFn: func(context.Context, fn.Function) (fn.DeploymentResult, error) { return result, nil },
+		RenderFn: func(context.Context, fn.Function) (fn.Manifests, error) { return fn.Manifests{}, nil },
 	}
 }
@@ -1,18 +1,31 @@
 package mock
 
+import (
+	"context"
+
+	fn "knative.dev/func"
+)
+
 type DNSProvider struct {
-	ProvideInvoked   bool
-	NameRequested    string
-	AddressRequested string
+	ProvideInvoked bool
+	RemoveInvoked  bool
+	ProvideFn      func(fn.Function) error
+	RemoveFn       func(string) error
 }
 
 func NewDNSProvider() *DNSProvider {
-	return &DNSProvider{}
+	return &DNSProvider{
+		ProvideFn: func(fn.Function) error { return nil },
+		RemoveFn:  func(string) error { return nil },
+	}
 }
 
-func (d *DNSProvider) Provide(name, address string) string {
+func (d *DNSProvider) Provide(_ context.Context, f fn.Function) error {
 	d.ProvideInvoked = true
-	d.NameRequested = name
-	d.AddressRequested = address
-	return name
+	return d.ProvideFn(f)
+}
+
+func (d *DNSProvider) Remove(_ context.Context, name string) error {
+	d.RemoveInvoked = true
+	return d.RemoveFn(name)
 }
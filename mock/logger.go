@@ -0,0 +1,23 @@
+package mock
+
+import (
+	"context"
+
+	fn "knative.dev/func"
+)
+
+type Logger struct {
+	LogsInvoked bool
+	LogsFn      func(fn.Function, fn.LogOptions, func(fn.LogEntry)) error
+}
+
+func NewLogger() *Logger {
+	return &Logger{
+		LogsFn: func(fn.Function, fn.LogOptions, func(fn.LogEntry)) error { return nil },
+	}
+}
+
+func (l *Logger) Logs(_ context.Context, f fn.Function, opts fn.LogOptions, emit func(fn.LogEntry)) error {
+	l.LogsInvoked = true
+	return l.LogsFn(f, opts, emit)
+}
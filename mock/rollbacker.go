@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	fn "knative.dev/func"
+)
+
+type Rollbacker struct {
+	RevisionsInvoked bool
+	RevisionsFn      func(string) ([]fn.Revision, error)
+
+	RollbackInvoked bool
+	RollbackFn      func(string, string) (fn.Revision, error)
+}
+
+func NewRollbacker() *Rollbacker {
+	return &Rollbacker{
+		RevisionsFn: func(string) ([]fn.Revision, error) { return []fn.Revision{}, nil },
+		RollbackFn:  func(_, revision string) (fn.Revision, error) { return fn.Revision{Name: revision}, nil },
+	}
+}
+
+func (r *Rollbacker) Revisions(_ context.Context, name string) ([]fn.Revision, error) {
+	r.RevisionsInvoked = true
+	return r.RevisionsFn(name)
+}
+
+func (r *Rollbacker) Rollback(_ context.Context, name, revision string) (fn.Revision, error) {
+	r.RollbackInvoked = true
+	return r.RollbackFn(name, revision)
+}
@@ -12,6 +12,7 @@ import (
 type Runner struct {
 	RunInvoked    bool
 	RootRequested string
+	PortRequested string
 	RunFn         func(context.Context, fn.Function) (*fn.Job, error)
 	sync.Mutex
 }
@@ -31,6 +32,7 @@ func (r *Runner) Run(ctx context.Context, f fn.Function) (*fn.Job, error) {
 	defer r.Unlock()
 	r.RunInvoked = true
 	r.RootRequested = f.Root
+	r.PortRequested = f.Run.Port
 
 	return r.RunFn(ctx, f)
 }
@@ -0,0 +1,23 @@
+package mock
+
+import (
+	"context"
+
+	fn "knative.dev/func"
+)
+
+type SBOMGenerator struct {
+	GenerateInvoked bool
+	GenerateFn      func(fn.Function) error
+}
+
+func NewSBOMGenerator() *SBOMGenerator {
+	return &SBOMGenerator{
+		GenerateFn: func(fn.Function) error { return nil },
+	}
+}
+
+func (i *SBOMGenerator) Generate(ctx context.Context, f fn.Function) error {
+	i.GenerateInvoked = true
+	return i.GenerateFn(f)
+}
@@ -0,0 +1,23 @@
+package mock
+
+import (
+	"context"
+
+	fn "knative.dev/func"
+)
+
+type TrafficSplitter struct {
+	SplitInvoked bool
+	SplitFn      func(string, []fn.TrafficTarget) error
+}
+
+func NewTrafficSplitter() *TrafficSplitter {
+	return &TrafficSplitter{
+		SplitFn: func(string, []fn.TrafficTarget) error { return nil },
+	}
+}
+
+func (s *TrafficSplitter) Split(_ context.Context, name string, targets []fn.TrafficTarget) error {
+	s.SplitInvoked = true
+	return s.SplitFn(name, targets)
+}
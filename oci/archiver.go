@@ -0,0 +1,364 @@
+package oci
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dockerClient "github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+
+	fn "knative.dev/func"
+	"knative.dev/func/docker"
+)
+
+// Formats of the outputs to which function images are saved.
+const (
+	// LayoutFormat is a directory holding an OCI image layout.
+	LayoutFormat = "oci-layout"
+	// ArchiveFormat is a tar archive of an OCI image layout.
+	ArchiveFormat = "oci-archive"
+)
+
+// Output to which a function image is saved, such as "oci-layout:<dir>" or
+// "oci-archive:<file.tar>".
+type Output struct {
+	Format string
+	Path   string
+}
+
+func (o Output) String() string {
+	return o.Format + ":" + o.Path
+}
+
+// ParseOutput of the form <format>:<path>, where format is either
+// "oci-layout" or "oci-archive".
+func ParseOutput(s string) (o Output, err error) {
+	format, path, ok := strings.Cut(s, ":")
+	if !ok || path == "" || (format != LayoutFormat && format != ArchiveFormat) {
+		return o, fmt.Errorf("invalid output %q, expected %v:<dir> or %v:<file>", s, LayoutFormat, ArchiveFormat)
+	}
+	return Output{Format: format, Path: path}, nil
+}
+
+// DockerClient is the subset of dockerClient.CommonAPIClient required to
+// save images built into the daemon.
+type DockerClient interface {
+	daemon.Client
+	Close() error
+}
+
+type DockerClientFactory func() (DockerClient, error)
+
+// Archiver of function images to and from OCI image layouts and archives,
+// such that they may be delivered without a registry.
+type Archiver struct {
+	dockerClientFactory DockerClientFactory
+}
+
+type ArchiverOption func(*Archiver)
+
+// WithArchiverDockerClientFactory sets the factory of the client of the
+// daemon from which images built into it are saved.
+func WithArchiverDockerClientFactory(f DockerClientFactory) ArchiverOption {
+	return func(a *Archiver) {
+		a.dockerClientFactory = f
+	}
+}
+
+// NewArchiver creates an instance of an Archiver with static defaults.
+func NewArchiver(options ...ArchiverOption) *Archiver {
+	a := &Archiver{
+		dockerClientFactory: func() (DockerClient, error) {
+			c, _, err := docker.NewClient(dockerClient.DefaultDockerHost)
+			return c, err
+		},
+	}
+	for _, o := range options {
+		o(a)
+	}
+	return a
+}
+
+// Save the image of the function to the output.  The image is that of the
+// function's image layout if built without a daemon, or for several
+// platforms, and otherwise that of the daemon.  Its digest is preserved.
+func (a *Archiver) Save(ctx context.Context, f fn.Function, output string) (digest string, err error) {
+	out, err := ParseOutput(output)
+	if err != nil {
+		return
+	}
+	if samePath(out.Path, f.ImageLayout()) {
+		return "", fmt.Errorf("cannot save the image to the function's own image layout %v", f.ImageLayout())
+	}
+
+	path := out.Path
+	if out.Format == ArchiveFormat {
+		tmp, err := os.MkdirTemp("", "func-oci-archive")
+		if err != nil {
+			return "", fmt.Errorf("cannot create temporary dir for the archive: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		path = filepath.Join(tmp, "layout")
+	}
+
+	if f.HasImageLayout() {
+		index, desc, err := readLayout(f.ImageLayout())
+		if err != nil {
+			return "", err
+		}
+		if err = copyLayout(path, f.Image, index, desc); err != nil {
+			return "", err
+		}
+		digest = desc.Digest.String()
+	} else {
+		img, err := a.daemonImage(ctx, f)
+		if err != nil {
+			return "", err
+		}
+		if err = WriteLayout(path, f.Image, Variant{Image: img}); err != nil {
+			return "", err
+		}
+		hash, err := img.Digest()
+		if err != nil {
+			return "", err
+		}
+		digest = hash.String()
+	}
+
+	if out.Format == ArchiveFormat {
+		if err = writeArchive(path, out.Path); err != nil {
+			return "", fmt.Errorf("cannot write the archive %v: %w", out.Path, err)
+		}
+	}
+	return
+}
+
+// Load the image saved to an OCI image layout or archive into the function's
+// image layout, named as the function's image.  The source is given either
+// as an output, such as "oci-archive:<file.tar>", or as a path: a directory
+// is read as a layout and a file as an archive.
+func (a *Archiver) Load(ctx context.Context, f fn.Function, from string) (digest string, err error) {
+	src, err := source(from)
+	if err != nil {
+		return
+	}
+	if samePath(src.Path, f.ImageLayout()) {
+		return "", fmt.Errorf("cannot load the image from the function's own image layout %v", f.ImageLayout())
+	}
+
+	path := src.Path
+	if src.Format == ArchiveFormat {
+		tmp, err := os.MkdirTemp("", "func-oci-archive")
+		if err != nil {
+			return "", fmt.Errorf("cannot create temporary dir for the archive: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		if err = readArchive(src.Path, tmp); err != nil {
+			return "", fmt.Errorf("cannot read the archive %v: %w", src.Path, err)
+		}
+		path = tmp
+	}
+
+	index, desc, err := readLayout(path)
+	if err != nil {
+		return
+	}
+	if err = copyLayout(f.ImageLayout(), f.Image, index, desc); err != nil {
+		return
+	}
+	return desc.Digest.String(), nil
+}
+
+// daemonImage of the function, as built into the daemon.
+func (a *Archiver) daemonImage(ctx context.Context, f fn.Function) (v1.Image, error) {
+	ref, err := name.ParseReference(f.Image)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := a.dockerClientFactory()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create docker client: %w", err)
+	}
+	defer cli.Close()
+	img, err := daemon.Image(ref, daemon.WithContext(ctx), daemon.WithClient(cli))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the image %v from the daemon: %w", f.Image, err)
+	}
+	return img, nil
+}
+
+// source from which an image is loaded: an output, or a path whose kind
+// determines its format.
+func source(from string) (Output, error) {
+	if strings.HasPrefix(from, LayoutFormat+":") || strings.HasPrefix(from, ArchiveFormat+":") {
+		return ParseOutput(from)
+	}
+	fi, err := os.Stat(from)
+	if err != nil {
+		return Output{}, err
+	}
+	if fi.IsDir() {
+		return Output{Format: LayoutFormat, Path: from}, nil
+	}
+	return Output{Format: ArchiveFormat, Path: from}, nil
+}
+
+// readLayout at path, which must hold exactly one image or image index,
+// returning its index and the descriptor of the image or index.
+func readLayout(path string) (v1.ImageIndex, v1.Descriptor, error) {
+	index, err := layout.ImageIndexFromPath(path)
+	if err != nil {
+		return nil, v1.Descriptor{}, fmt.Errorf("cannot read the image layout %v: %w", path, err)
+	}
+	im, err := index.IndexManifest()
+	if err != nil {
+		return nil, v1.Descriptor{}, err
+	}
+	if len(im.Manifests) != 1 {
+		return nil, v1.Descriptor{}, fmt.Errorf("expected one image in the image layout %v, found %v", path, len(im.Manifests))
+	}
+	return index, im.Manifests[0], nil
+}
+
+// copyLayout of the image or image index described within index to a new
+// layout at path, named.  Manifests are copied unchanged, preserving the
+// digest.
+func copyLayout(path, name string, index v1.ImageIndex, desc v1.Descriptor) error {
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	p, err := layout.Write(path, empty.Index)
+	if err != nil {
+		return fmt.Errorf("cannot write the image layout: %w", err)
+	}
+	annotations := layout.WithAnnotations(map[string]string{refNameAnnotation: name})
+	if desc.MediaType.IsIndex() {
+		ii, err := index.ImageIndex(desc.Digest)
+		if err != nil {
+			return err
+		}
+		err = p.AppendIndex(ii, annotations)
+	} else {
+		img, err := index.Image(desc.Digest)
+		if err != nil {
+			return err
+		}
+		err = p.AppendImage(img, annotations)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write the image layout: %w", err)
+	}
+	return nil
+}
+
+// writeArchive of the layout at dir to the file.
+func writeArchive(dir, file string) (err error) {
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return
+	}
+	w, err := os.Create(file)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if e := w.Close(); err == nil {
+			err = e
+		}
+	}()
+	tw := tar.NewWriter(w)
+	err = filepath.Walk(dir, func(path string, fi fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		p, err := filepath.Rel(dir, path)
+		if err != nil || p == "." {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(p)
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err = tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		r, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		_, err = io.Copy(tw, r)
+		return err
+	})
+	if err != nil {
+		return
+	}
+	return tw.Close()
+}
+
+// readArchive of a layout, extracting it to dir.  Only directories and
+// regular files within dir are extracted.
+func readArchive(file, dir string) error {
+	r, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(os.PathSeparator)) {
+			return fmt.Errorf("path %q points outside the archive", hdr.Name)
+		}
+		path := filepath.Join(dir, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			w, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(w, tr)
+			w.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// samePath returns true if the paths resolve to the same absolute path.
+func samePath(a, b string) bool {
+	a, errA := filepath.Abs(a)
+	b, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && a == b
+}
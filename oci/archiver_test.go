@@ -0,0 +1,163 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	fn "knative.dev/func"
+)
+
+func TestParseOutput(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Output
+		wantErr bool
+	}{
+		{value: "oci-layout:./out", want: Output{Format: LayoutFormat, Path: "./out"}},
+		{value: "oci-archive:/tmp/f.tar", want: Output{Format: ArchiveFormat, Path: "/tmp/f.tar"}},
+		{value: "oci-archive:C:\\f.tar", want: Output{Format: ArchiveFormat, Path: "C:\\f.tar"}},
+		{value: "oci-archive:", wantErr: true},
+		{value: "docker-archive:f.tar", wantErr: true},
+		{value: "f.tar", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseOutput(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOutput(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOutput(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestArchiver_SaveLoad ensures that the image index of a function's image
+// layout saved to an archive or layout is loaded as another function's image
+// with the same digest.
+func TestArchiver_SaveLoad(t *testing.T) {
+	amd64, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arm64, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := fn.Function{Root: t.TempDir(), Image: "example.com/alice/f:latest"}
+	if err = WriteLayout(f.ImageLayout(), f.Image,
+		Variant{Platform: v1.Platform{OS: "linux", Architecture: "amd64"}, Image: amd64},
+		Variant{Platform: v1.Platform{OS: "linux", Architecture: "arm64"}, Image: arm64},
+	); err != nil {
+		t.Fatal(err)
+	}
+	built := layoutDescriptor(t, f.ImageLayout())
+
+	a := NewArchiver(WithArchiverDockerClientFactory(func() (DockerClient, error) {
+		t.Fatal("unexpected use of the daemon")
+		return nil, nil
+	}))
+	dir := t.TempDir()
+	for _, output := range []Output{
+		{Format: ArchiveFormat, Path: filepath.Join(dir, "f.tar")},
+		{Format: LayoutFormat, Path: filepath.Join(dir, "layout")},
+	} {
+		digest, err := a.Save(context.Background(), f, output.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if digest != built.Digest.String() {
+			t.Errorf("expected %v saved with digest %v, got %v", output, built.Digest, digest)
+		}
+
+		// Loaded from the output or its path, named as the function
+		for _, from := range []string{output.String(), output.Path} {
+			g := fn.Function{Root: t.TempDir(), Image: "example.com/bob/g:latest"}
+			if digest, err = a.Load(context.Background(), g, from); err != nil {
+				t.Fatal(err)
+			}
+			loaded := layoutDescriptor(t, g.ImageLayout())
+			if digest != built.Digest.String() || loaded.Digest != built.Digest || !loaded.MediaType.IsIndex() {
+				t.Errorf("expected the index %v loaded from %v, got %v", built.Digest, from, loaded)
+			}
+			if loaded.Annotations[refNameAnnotation] != g.Image {
+				t.Errorf("expected the image loaded to be named %v, got %v", g.Image, loaded.Annotations)
+			}
+		}
+	}
+}
+
+// TestArchiver_SaveDaemon ensures that the image of a function built into
+// the daemon is saved.
+func TestArchiver_SaveDaemon(t *testing.T) {
+	f := fn.Function{Root: t.TempDir(), Image: "example.com/alice/f:latest"}
+	img, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.ParseReference(f.Image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := mockDocker{img: img, ref: ref}
+	a := NewArchiver(WithArchiverDockerClientFactory(func() (DockerClient, error) {
+		return cli, nil
+	}))
+
+	output := Output{Format: LayoutFormat, Path: filepath.Join(t.TempDir(), "layout")}
+	digest, err := a.Save(context.Background(), f, output.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != want.String() {
+		t.Errorf("expected the image %v saved, got %v", want, digest)
+	}
+	if desc := layoutDescriptor(t, output.Path); desc.Digest != want {
+		t.Errorf("expected the layout to hold %v, got %v", want, desc.Digest)
+	}
+}
+
+// mockDocker daemon holding a single image.
+type mockDocker struct {
+	img v1.Image
+	ref name.Reference
+}
+
+func (m mockDocker) NegotiateAPIVersion(ctx context.Context) {}
+
+func (m mockDocker) ImageSave(ctx context.Context, images []string) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(tarball.Write(m.ref, m.img, pw))
+	}()
+	return pr, nil
+}
+
+func (m mockDocker) ImageLoad(ctx context.Context, input io.Reader, quiet bool) (types.ImageLoadResponse, error) {
+	return types.ImageLoadResponse{}, errors.New("not implemented")
+}
+
+func (m mockDocker) ImageTag(ctx context.Context, source, target string) error {
+	return errors.New("not implemented")
+}
+
+func (m mockDocker) ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error) {
+	id, err := m.img.ConfigName()
+	return types.ImageInspect{ID: id.String()}, nil, err
+}
+
+func (m mockDocker) Close() error { return nil }
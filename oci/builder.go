@@ -0,0 +1,281 @@
+/*
+Package oci implements a builder of Go functions which needs no container
+daemon: the function is compiled with the Go toolchain of the host and its
+binary layered onto a base image, written as an OCI image layout.  It also
+writes the image indexes of images built for several platforms, and saves and
+loads function images to and from OCI image layouts and archives.
+*/
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	fn "knative.dev/func"
+	"knative.dev/func/builders"
+)
+
+// DefaultName when no WithName option is provided to NewBuilder
+const DefaultName = builders.Host
+
+// DefaultBaseImage onto which the function's binary is layered, unless the
+// function defines a builder image for the host builder.  A base image
+// referenced by digest yields reproducible images.
+const DefaultBaseImage = "gcr.io/distroless/static:nonroot"
+
+// DefaultPlatform for which the function is built when none is provided.
+const DefaultPlatform = "linux/amd64"
+
+// binaryPath within the image of the function's binary, its entrypoint.
+const binaryPath = "/func/f"
+
+// ErrRuntimeNotSupported is returned when building a function of a runtime
+// other than Go.
+type ErrRuntimeNotSupported struct {
+	Runtime string
+}
+
+func (e ErrRuntimeNotSupported) Error() string {
+	return fmt.Sprintf("the %q builder only supports the \"go\" runtime, not %q", DefaultName, e.Runtime)
+}
+
+// Builder of Go functions into OCI images without a container daemon.
+type Builder struct {
+	name     string
+	verbose  bool
+	platform string
+	remote   []remote.Option
+}
+
+type Option func(*Builder)
+
+func WithName(n string) Option {
+	return func(b *Builder) {
+		b.name = n
+	}
+}
+
+// WithVerbose toggles verbose logging.
+func WithVerbose(v bool) Option {
+	return func(b *Builder) {
+		b.verbose = v
+	}
+}
+
+func WithPlatform(platform string) Option {
+	return func(b *Builder) {
+		b.platform = platform
+	}
+}
+
+// WithRemoteOptions used when fetching the base image, such as a transport
+// or credentials.  By default the credentials of the default keychain are
+// used.
+func WithRemoteOptions(options ...remote.Option) Option {
+	return func(b *Builder) {
+		b.remote = options
+	}
+}
+
+// NewBuilder creates a new instance of a Builder with static defaults.
+func NewBuilder(options ...Option) *Builder {
+	b := &Builder{name: DefaultName}
+	for _, o := range options {
+		o(b)
+	}
+	return b
+}
+
+// Build the function's image, writing it to the function's image layout
+// (see fn.Function.ImageLayout) from which it is pushed.  Builds are
+// reproducible: the same source, Go toolchain and base image yield the same
+// image digest.  When built for several platforms, such as
+// "linux/amd64,linux/arm64", the layout holds an image index of the image
+// built for each.
+func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
+	if f.Runtime != "go" {
+		return ErrRuntimeNotSupported{Runtime: f.Runtime}
+	}
+
+	p := b.platform
+	if p == "" {
+		p = DefaultPlatform
+	}
+	platforms, err := ParsePlatforms(p)
+	if err != nil {
+		return
+	}
+
+	tmp, err := os.MkdirTemp("", "func-host-build")
+	if err != nil {
+		return fmt.Errorf("cannot create temporary dir for the host build: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	variants := make([]Variant, 0, len(platforms))
+	for i, platform := range platforms {
+		binary := filepath.Join(tmp, fmt.Sprintf("f-%d", i))
+		if err = b.compile(ctx, f, platform, filepath.Join(tmp, "scaffolding"), binary); err != nil {
+			return
+		}
+		base, err := b.baseImage(ctx, f, platform)
+		if err != nil {
+			return err
+		}
+		img, err := image(base, binary)
+		if err != nil {
+			return err
+		}
+		variants = append(variants, Variant{Platform: platform, Image: img})
+	}
+
+	if err = WriteLayout(f.ImageLayout(), f.Image, variants...); err != nil {
+		return
+	}
+
+	if b.verbose {
+		for _, v := range variants {
+			digest, err := v.Image.Digest()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Built %v (%v) for %v in %v\n", f.Image, digest, v.Platform, f.ImageLayout())
+		}
+	}
+	return nil
+}
+
+// compile the function for the platform into the given binary, with a main
+// package scaffolded in dir.  Build envs are set in the environment of the
+// Go toolchain, such as GOFLAGS or GOPRIVATE.
+func (b *Builder) compile(ctx context.Context, f fn.Function, platform v1.Platform, dir, binary string) error {
+	if _, err := exec.LookPath("go"); err != nil {
+		return fmt.Errorf("the %q builder requires the Go toolchain: %w", b.name, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := scaffold(dir, f); err != nil {
+		return err
+	}
+
+	buildEnvs, err := fn.Interpolate(f.Build.BuildEnvs)
+	if err != nil {
+		return err
+	}
+	env := os.Environ()
+	for k, v := range buildEnvs {
+		env = append(env, k+"="+v)
+	}
+	env = append(env, "CGO_ENABLED=0", "GOOS="+platform.OS, "GOARCH="+platform.Architecture, "GOFLAGS=-mod=mod")
+	if platform.Architecture == "arm" && platform.Variant != "" {
+		env = append(env, "GOARM="+strings.TrimPrefix(platform.Variant, "v"))
+	}
+
+	// Paths, build IDs and version control stamps vary between hosts and
+	// checkouts, and are omitted such that the binary is reproducible.
+	cmd := exec.CommandContext(ctx, "go", "build", "-trimpath", "-buildvcs=false",
+		"-ldflags=-s -w -buildid=", "-o", binary, ".")
+	cmd.Dir = dir
+	cmd.Env = env
+	var stderr bytes.Buffer
+	cmd.Stdout = io.Discard
+	cmd.Stderr = &stderr
+	if b.verbose {
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	}
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("cannot compile the function: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// baseImage for the platform: the function's builder image for the host
+// builder, or the default.
+func (b *Builder) baseImage(ctx context.Context, f fn.Function, platform v1.Platform) (v1.Image, error) {
+	base := DefaultBaseImage
+	if v, ok := f.Build.BuilderImages[b.name]; ok {
+		base = v
+	}
+	ref, err := name.ParseReference(base)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse base image name: %w", err)
+	}
+	options := append([]remote.Option{
+		remote.WithContext(ctx),
+		remote.WithPlatform(platform),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+	}, b.remote...)
+	img, err := remote.Image(ref, options...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the base image %v: %w", base, err)
+	}
+	return img, nil
+}
+
+// image of the binary layered onto the base, as its entrypoint.  The layer
+// and configuration carry no timestamps.
+func image(base v1.Image, binary string) (v1.Image, error) {
+	bin, err := os.ReadFile(binary)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	dir := strings.TrimPrefix(filepath.ToSlash(filepath.Dir(binaryPath)), "/")
+	if err = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: dir + "/", Mode: 0755}); err != nil {
+		return nil, err
+	}
+	if err = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: strings.TrimPrefix(binaryPath, "/"), Mode: 0755, Size: int64(len(bin))}); err != nil {
+		return nil, err
+	}
+	if _, err = tw.Write(bin); err != nil {
+		return nil, err
+	}
+	if err = tw.Close(); err != nil {
+		return nil, err
+	}
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := mutate.Append(base, mutate.Addendum{
+		Layer:   layer,
+		History: v1.History{CreatedBy: "func " + DefaultName + " builder", Comment: binaryPath},
+	})
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, errors.New("the base image has no configuration")
+	}
+	cfg = cfg.DeepCopy()
+	cfg.Config.Entrypoint = []string{binaryPath}
+	cfg.Config.Cmd = nil
+	cfg.Config.WorkingDir = filepath.ToSlash(filepath.Dir(binaryPath))
+	cfg.Created = v1.Time{}
+	return mutate.ConfigFile(img, cfg)
+}
@@ -0,0 +1,179 @@
+package oci
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	fn "knative.dev/func"
+	"knative.dev/func/builders"
+)
+
+const testHandle = `package function
+
+import (
+	"context"
+	"net/http"
+)
+
+func Handle(ctx context.Context, res http.ResponseWriter, req *http.Request) {
+	res.Write([]byte("OK"))
+}
+`
+
+// TestBuild ensures that a Go function is built into an OCI layout with its
+// binary layered onto the base image as the entrypoint, and that building
+// again yields the same digest.
+func TestBuild(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the Go toolchain is not available")
+	}
+
+	// A base image in a registry
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	base := strings.TrimPrefix(server.URL, "http://") + "/base:latest"
+	baseImg, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.ParseReference(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = remote.Write(ref, baseImg); err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	if err = os.WriteFile(filepath.Join(root, "go.mod"), []byte("module function\n\ngo 1.18\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(root, "handle.go"), []byte(testHandle), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := fn.Function{
+		Root:    root,
+		Runtime: "go",
+		Image:   "example.com/alice/f:latest",
+		Build:   fn.BuildSpec{BuilderImages: map[string]string{builders.Host: base}},
+	}
+
+	b := NewBuilder(WithPlatform("linux/arm64"))
+	if err = b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	img := layoutImage(t, f)
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Config.Entrypoint) != 1 || cfg.Config.Entrypoint[0] != binaryPath {
+		t.Errorf("expected the entrypoint %v, got %v", binaryPath, cfg.Config.Entrypoint)
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layers) != 2 {
+		t.Errorf("expected the base layer and that of the function, got %v layers", len(layers))
+	}
+	first, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Building again yields the same image
+	if err = b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	second, err := layoutImage(t, f).Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("expected a reproducible build, got %v and %v", first, second)
+	}
+
+	// Built for several platforms, the layout holds an index of the images
+	b = NewBuilder(WithPlatform("linux/amd64,linux/arm64"))
+	if err = b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if desc := layoutDescriptor(t, f.ImageLayout()); !desc.MediaType.IsIndex() {
+		t.Errorf("expected an image index, got %v", desc.MediaType)
+	}
+}
+
+// TestBuild_RuntimeNotSupported ensures that functions of runtimes other than
+// Go are not built.
+func TestBuild_RuntimeNotSupported(t *testing.T) {
+	err := NewBuilder().Build(context.Background(), fn.Function{Root: t.TempDir(), Runtime: "node"})
+	if _, ok := err.(ErrRuntimeNotSupported); !ok {
+		t.Fatalf("expected ErrRuntimeNotSupported, got %v", err)
+	}
+}
+
+// TestScaffold ensures that the main package scaffolded for a function
+// serves it with its health endpoints, through the CloudEvents SDK if
+// invoked with CloudEvents.
+func TestScaffold(t *testing.T) {
+	dir := t.TempDir()
+	f := fn.Function{Root: "/src/f", Invoke: "cloudevent"}
+	f.Deploy.HealthEndpoints.Readiness = "/ready"
+	if err := scaffold(dir, f); err != nil {
+		t.Fatal(err)
+	}
+	main, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`"/ready"`, `"` + fn.DefaultLivenessEndpoint + `"`, "cloudevents.NewHTTPReceiveHandler(ctx, protocol, function.Handle)"} {
+		if !strings.Contains(string(main), s) {
+			t.Errorf("expected main.go to contain %v, got\n%s", s, main)
+		}
+	}
+	mod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(mod), `replace function => "/src/f"`) {
+		t.Errorf("expected go.mod to replace the function module with its root, got\n%s", mod)
+	}
+}
+
+// layoutImage returns the image written to the function's image layout.
+func layoutImage(t *testing.T, f fn.Function) v1.Image {
+	t.Helper()
+	p, err := layout.FromPath(f.ImageLayout())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ii, err := p.ImageIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	im, err := ii.IndexManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(im.Manifests) != 1 {
+		t.Fatalf("expected one image in the layout, got %v", len(im.Manifests))
+	}
+	img, err := ii.Image(im.Manifests[0].Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
@@ -0,0 +1,125 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	fn "knative.dev/func"
+)
+
+// refNameAnnotation of the image in an OCI image layout.
+const refNameAnnotation = "org.opencontainers.image.ref.name"
+
+// Variant of a multi-platform image: the image built for one platform.
+type Variant struct {
+	Platform v1.Platform
+	Image    v1.Image
+}
+
+// ParsePlatforms of a comma-separated list, such as
+// "linux/amd64,linux/arm64".  An empty list yields no platforms.
+func ParsePlatforms(s string) (platforms []v1.Platform, err error) {
+	if strings.TrimSpace(s) == "" {
+		return
+	}
+	seen := map[string]bool{}
+	for _, p := range strings.Split(s, ",") {
+		platform, err := v1.ParsePlatform(strings.TrimSpace(p))
+		if err != nil || platform.OS == "" || platform.Architecture == "" {
+			return nil, fmt.Errorf("invalid platform %q, expected os/arch[/variant], for example \"linux/amd64\"", strings.TrimSpace(p))
+		}
+		if seen[platform.String()] {
+			return nil, fmt.Errorf("duplicate platform %q", platform)
+		}
+		seen[platform.String()] = true
+		platforms = append(platforms, *platform)
+	}
+	return
+}
+
+// WriteLayout of the image named, built for one or more platforms, to an
+// OCI image layout at path, replacing any there.  A single variant is written
+// as an image, several as an image index of them, in the order given.
+func WriteLayout(path, name string, variants ...Variant) error {
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	p, err := layout.Write(path, empty.Index)
+	if err != nil {
+		return fmt.Errorf("cannot write the image layout: %w", err)
+	}
+	annotations := layout.WithAnnotations(map[string]string{refNameAnnotation: name})
+	if len(variants) == 1 {
+		err = p.AppendImage(variants[0].Image, annotations)
+	} else {
+		index := mutate.IndexMediaType(empty.Index, types.OCIImageIndex)
+		for _, v := range variants {
+			platform := v.Platform
+			index = mutate.AppendManifests(index, mutate.IndexAddendum{
+				Add:        v.Image,
+				Descriptor: v1.Descriptor{Platform: &platform},
+			})
+		}
+		err = p.AppendIndex(index, annotations)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write the image layout: %w", err)
+	}
+	return nil
+}
+
+// BuildVariants of the function's image for each of several platforms with
+// the given build, which builds the image in the daemon of the given client
+// under the function's image name, and writes the image index of them to the
+// function's image layout (see fn.Function.ImageLayout), from which it is
+// pushed.  Each variant is copied out of the daemon before the next replaces
+// it.
+func BuildVariants(ctx context.Context, f fn.Function, cli daemon.Client, platforms []v1.Platform, build func(v1.Platform) error) error {
+	ref, err := name.ParseReference(f.Image)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "func-variants")
+	if err != nil {
+		return fmt.Errorf("cannot create temporary dir for the image variants: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	cache, err := layout.Write(tmp, empty.Index)
+	if err != nil {
+		return err
+	}
+
+	variants := make([]Variant, 0, len(platforms))
+	for _, platform := range platforms {
+		if err = build(platform); err != nil {
+			return fmt.Errorf("cannot build for %v: %w", platform, err)
+		}
+		img, err := daemon.Image(ref, daemon.WithContext(ctx), daemon.WithClient(cli))
+		if err != nil {
+			return fmt.Errorf("cannot read the image built for %v: %w", platform, err)
+		}
+		if err = cache.AppendImage(img); err != nil {
+			return fmt.Errorf("cannot copy the image built for %v: %w", platform, err)
+		}
+		digest, err := img.Digest()
+		if err != nil {
+			return err
+		}
+		if img, err = cache.Image(digest); err != nil {
+			return err
+		}
+		variants = append(variants, Variant{Platform: platform, Image: img})
+	}
+	return WriteLayout(f.ImageLayout(), f.Image, variants...)
+}
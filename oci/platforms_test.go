@@ -0,0 +1,122 @@
+package oci
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+)
+
+func TestParsePlatforms(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "none", value: ""},
+		{name: "one", value: "linux/amd64", want: []string{"linux/amd64"}},
+		{name: "several", value: "linux/amd64, linux/arm64,linux/arm/v7", want: []string{"linux/amd64", "linux/arm64", "linux/arm/v7"}},
+		{name: "empty entry", value: "linux/amd64,", wantErr: true},
+		{name: "no architecture", value: "linux", wantErr: true},
+		{name: "duplicate", value: "linux/amd64,linux/amd64", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platforms, err := ParsePlatforms(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePlatforms(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if len(platforms) != len(tt.want) {
+				t.Fatalf("ParsePlatforms(%q) = %v, want %v", tt.value, platforms, tt.want)
+			}
+			for i, p := range platforms {
+				if p.String() != tt.want[i] {
+					t.Errorf("ParsePlatforms(%q) = %v, want %v", tt.value, platforms, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestWriteLayout ensures that the image built for a single platform is
+// written as such, and those built for several as an image index of them
+// with their platforms.
+func TestWriteLayout(t *testing.T) {
+	const image = "example.com/alice/f:latest"
+	amd64, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arm64, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err = WriteLayout(dir, image, Variant{Platform: v1.Platform{OS: "linux", Architecture: "amd64"}, Image: amd64}); err != nil {
+		t.Fatal(err)
+	}
+	desc := layoutDescriptor(t, dir)
+	if desc.MediaType.IsIndex() || desc.Annotations[refNameAnnotation] != image {
+		t.Errorf("expected an image named %v, got %v", image, desc)
+	}
+
+	// Replaced by an index of the images of several platforms
+	if err = WriteLayout(dir, image,
+		Variant{Platform: v1.Platform{OS: "linux", Architecture: "amd64"}, Image: amd64},
+		Variant{Platform: v1.Platform{OS: "linux", Architecture: "arm64"}, Image: arm64},
+	); err != nil {
+		t.Fatal(err)
+	}
+	desc = layoutDescriptor(t, dir)
+	if !desc.MediaType.IsIndex() || desc.Annotations[refNameAnnotation] != image {
+		t.Fatalf("expected an image index named %v, got %v", image, desc)
+	}
+	p, err := layout.FromPath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ii, err := p.ImageIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := ii.ImageIndex(desc.Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	im, err := index.IndexManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(im.Manifests) != 2 {
+		t.Fatalf("expected two images in the index, got %v", len(im.Manifests))
+	}
+	for i, want := range []string{"linux/amd64", "linux/arm64"} {
+		if im.Manifests[i].Platform == nil || im.Manifests[i].Platform.String() != want {
+			t.Errorf("expected image %v for %v, got %v", i, want, im.Manifests[i].Platform)
+		}
+	}
+}
+
+// layoutDescriptor returns the descriptor of the only manifest in the layout.
+func layoutDescriptor(t *testing.T, dir string) v1.Descriptor {
+	t.Helper()
+	p, err := layout.FromPath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ii, err := p.ImageIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	im, err := ii.IndexManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(im.Manifests) != 1 {
+		t.Fatalf("expected one manifest in the layout, got %v", len(im.Manifests))
+	}
+	return im.Manifests[0]
+}
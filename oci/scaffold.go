@@ -0,0 +1,142 @@
+package oci
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	fn "knative.dev/func"
+)
+
+// scaffoldModule is the module path of the main package generated to run
+// the function, which is the module "function" of its root.
+const scaffoldModule = "f"
+
+var goMod = template.Must(template.New("go.mod").Parse(`module ` + scaffoldModule + `
+
+go 1.18
+
+require function v0.0.0
+
+replace function => {{ printf "%q" .Root }}
+`))
+
+var mainGo = template.Must(template.New("main.go").Parse(`// Code generated by func. DO NOT EDIT.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+{{ if .CloudEvents }}
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+{{ end }}
+	"function"
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if err := run(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	mux := http.NewServeMux()
+{{- range .HealthEndpoints }}
+	mux.HandleFunc({{ printf "%q" . }}, func(res http.ResponseWriter, _ *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	})
+{{- end }}
+{{- if .CloudEvents }}
+	protocol, err := cloudevents.NewHTTP()
+	if err != nil {
+		return err
+	}
+	receiver, err := cloudevents.NewHTTPReceiveHandler(ctx, protocol, function.Handle)
+	if err != nil {
+		return err
+	}
+	mux.Handle("/", receiver)
+{{- else }}
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		function.Handle(req.Context(), res, req)
+	})
+{{- end }}
+
+	srv := &http.Server{Addr: ":" + port, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+`))
+
+// scaffold writes to dir a main module which serves the function at root,
+// with its health endpoints, over HTTP on $PORT (by default 8080).  Functions
+// invoked with CloudEvents are served through the CloudEvents SDK, which
+// supports each signature of Handle described by the template.
+func scaffold(dir string, f fn.Function) (err error) {
+	data := struct {
+		Root            string
+		CloudEvents     bool
+		HealthEndpoints []string
+	}{
+		Root:        f.Root,
+		CloudEvents: f.Invoke == "cloudevent",
+	}
+	seen := map[string]bool{}
+	for _, e := range []struct{ path, def string }{
+		{f.Deploy.HealthEndpoints.Liveness, fn.DefaultLivenessEndpoint},
+		{f.Deploy.HealthEndpoints.Readiness, fn.DefaultReadinessEndpoint},
+		{f.Deploy.HealthEndpoints.Startup, ""},
+	} {
+		path := e.path
+		if path == "" {
+			path = e.def
+		}
+		if path == "" || path == "/" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		data.HealthEndpoints = append(data.HealthEndpoints, path)
+	}
+
+	for name, t := range map[string]*template.Template{"go.mod": goMod, "main.go": mainGo} {
+		file, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		err = t.Execute(file, data)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("cannot scaffold %v: %w", name, err)
+		}
+	}
+
+	// The main module requires exactly the modules required by the function,
+	// and so their checksums.
+	sum, err := os.ReadFile(filepath.Join(f.Root, "go.sum"))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return
+	}
+	return os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0644)
+}
@@ -223,8 +223,15 @@ func sourcesAsTarStream(f fn.Function) *io.PipeReader {
 
 	const up = ".." + string(os.PathSeparator)
 	go func() {
+		// Paths listed in .funcignore are not uploaded either
+		funcignored, err := f.Ignorer()
+		if err != nil {
+			_ = pw.CloseWithError(fmt.Errorf("error while creating tar stream from sources: %w", err))
+			return
+		}
+
 		tw := tar.NewWriter(pw)
-		err := filepath.Walk(f.Root, func(p string, fi fs.FileInfo, err error) error {
+		err = filepath.Walk(f.Root, func(p string, fi fs.FileInfo, err error) error {
 			if err != nil {
 				return fmt.Errorf("error traversing function directory: %w", err)
 			}
@@ -238,6 +245,13 @@ func sourcesAsTarStream(f fn.Function) *io.PipeReader {
 				return nil
 			}
 
+			if funcignored(relp, fi.IsDir()) {
+				if fi.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
 			if ignored(relp) {
 				return nil
 			}
@@ -66,3 +66,47 @@ func TestSourcesAsTarStream(t *testing.T) {
 		t.Error("symlink missing in the stream")
 	}
 }
+
+func TestSourcesAsTarStream_FuncIgnore(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		fn.IgnoreFile:               "node_modules/\n*.log\n",
+		"index.js":                  "console.log('hi')\n",
+		"debug.log":                 "ignored\n",
+		"node_modules/dep/index.js": "ignored\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rc := sourcesAsTarStream(fn.Function{Root: root})
+	t.Cleanup(func() { _ = rc.Close() })
+
+	var indexFound bool
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(hdr.Name, "source/node_modules") || hdr.Name == "source/debug.log" {
+			t.Errorf("stream contains path listed in %v: %q", fn.IgnoreFile, hdr.Name)
+		}
+		if hdr.Name == "source/index.js" {
+			indexFound = true
+		}
+	}
+	if !indexFound {
+		t.Error("the index.js file is missing in the stream")
+	}
+}
@@ -3,6 +3,7 @@ package tekton
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	pplnv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1 "k8s.io/api/core/v1"
@@ -76,7 +77,7 @@ func generatePipeline(f fn.Function, labels map[string]string) *pplnv1beta1.Pipe
 
 	var taskBuild pplnv1beta1.PipelineTask
 
-	// Deploy step that uses an image produced by S2I builds needs explicit reference to the image
+	// Deploy step that uses an image produced by S2I or Dockerfile builds needs explicit reference to the image
 	referenceImageFromPreviousTaskResults := false
 
 	var tasks []pplnv1beta1.PipelineTask
@@ -101,6 +102,20 @@ func generatePipeline(f fn.Function, labels map[string]string) *pplnv1beta1.Pipe
 
 		taskBuild = taskS2iBuild(buildPreReq)
 		referenceImageFromPreviousTaskResults = true
+
+	} else if f.Build.Builder == builders.Dockerfile {
+		// ----- Dockerfile build related properties
+
+		params = append(params,
+			pplnv1beta1.ParamSpec{Name: "dockerfile", Description: "Path of the Dockerfile relative to the function. Defaults to its Dockerfile or Containerfile.",
+				Default: pplnv1beta1.NewArrayOrString("")},
+			pplnv1beta1.ParamSpec{Name: "target", Description: "Stage of a multi-stage Dockerfile to build. Defaults to the last.",
+				Default: pplnv1beta1.NewArrayOrString("")},
+			pplnv1beta1.ParamSpec{Name: "platform", Description: "Platform, or comma-separated platforms, to build. Defaults to that of the node.",
+				Default: pplnv1beta1.NewArrayOrString("")})
+
+		taskBuild = taskDockerfileBuild(buildPreReq)
+		referenceImageFromPreviousTaskResults = true
 	}
 
 	// ----- Pipeline definition
@@ -200,6 +215,11 @@ func generatePipelineRun(f fn.Function, labels map[string]string) *pplnv1beta1.P
 		if f.Runtime == "quarkus" {
 			params = append(params, pplnv1beta1.Param{Name: "s2iImageScriptsUrl", Value: *pplnv1beta1.NewArrayOrString("image:///usr/local/s2i")})
 		}
+	} else if f.Build.Builder == builders.Dockerfile {
+		params = append(params,
+			pplnv1beta1.Param{Name: "dockerfile", Value: *pplnv1beta1.NewArrayOrString(filepath.ToSlash(f.Build.Dockerfile))},
+			pplnv1beta1.Param{Name: "target", Value: *pplnv1beta1.NewArrayOrString(f.Build.Target)},
+			pplnv1beta1.Param{Name: "platform", Value: *pplnv1beta1.NewArrayOrString(f.Build.Platform)})
 	}
 
 	// ----- PipelineRun definition
@@ -223,9 +243,12 @@ func generatePipelineRun(f fn.Function, labels map[string]string) *pplnv1beta1.P
 // with the Pack strategy if it can be calculated (the Function has a defined
 // language runtime.  Errors are checked elsewhere, so at this level they
 // manifest as an inability to get a builder image = empty string.
+// Dockerfile builds use no builder image.
 func getBuilderImage(f fn.Function) (name string) {
 	if f.Build.Builder == builders.S2I {
 		name, _ = s2i.BuilderImage(f, builders.S2I)
+	} else if f.Build.Builder == builders.Dockerfile {
+		name = ""
 	} else {
 		name, _ = buildpacks.BuilderImage(f, builders.Pack)
 	}
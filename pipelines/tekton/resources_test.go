@@ -31,6 +31,11 @@ func Test_generatePipeline(t *testing.T) {
 			function:      fn.Function{Build: fn.BuildSpec{Builder: builders.S2I, Git: testGit}},
 			taskBuildName: "func-s2i",
 		},
+		{
+			name:          "Dockerfile builder - use",
+			function:      fn.Function{Build: fn.BuildSpec{Builder: builders.Dockerfile, Git: testGit}},
+			taskBuildName: "func-dockerfile",
+		},
 	}
 
 	for _, tt := range tests {
@@ -52,3 +57,31 @@ func Test_generatePipeline(t *testing.T) {
 		})
 	}
 }
+
+// Test_generatePipelineRun_Platform ensures that the platform of a Dockerfile
+// build is passed to the pipeline and from it to the build task.
+func Test_generatePipelineRun_Platform(t *testing.T) {
+	f := fn.Function{Name: "f", Build: fn.BuildSpec{Builder: builders.Dockerfile, Platform: "linux/arm64"}}
+
+	var platform string
+	for _, p := range generatePipelineRun(f, map[string]string{}).Spec.Params {
+		if p.Name == "platform" {
+			platform = p.Value.StringVal
+		}
+	}
+	if platform != "linux/arm64" {
+		t.Errorf("expected the platform param of the pipeline run to be linux/arm64, got %q", platform)
+	}
+
+	var param string
+	for _, task := range generatePipeline(f, map[string]string{}).Spec.Tasks {
+		for _, p := range task.Params {
+			if task.Name == taskNameBuild && p.Name == "PLATFORM" {
+				param = p.Value.StringVal
+			}
+		}
+	}
+	if param != "$(params.platform)" {
+		t.Errorf("expected the PLATFORM param of the build task to be that of the pipeline, got %q", param)
+	}
+}
@@ -96,11 +96,46 @@ func taskS2iBuild(runAfter []string) pplnv1beta1.PipelineTask {
 
 }
 
+func taskDockerfileBuild(runAfter []string) pplnv1beta1.PipelineTask {
+	params := []pplnv1beta1.Param{
+		{Name: "IMAGE", Value: *pplnv1beta1.NewArrayOrString("$(params.imageName)")},
+		{Name: "PATH_CONTEXT", Value: *pplnv1beta1.NewArrayOrString("$(params.contextDir)")},
+		{Name: "DOCKERFILE", Value: *pplnv1beta1.NewArrayOrString("$(params.dockerfile)")},
+		{Name: "TARGET", Value: *pplnv1beta1.NewArrayOrString("$(params.target)")},
+		{Name: "PLATFORM", Value: *pplnv1beta1.NewArrayOrString("$(params.platform)")},
+		{Name: "ENV_VARS", Value: pplnv1beta1.ArrayOrString{
+			Type:     pplnv1beta1.ParamTypeArray,
+			ArrayVal: []string{"$(params.buildEnvs[*])"},
+		}},
+	}
+	return pplnv1beta1.PipelineTask{
+		Name: taskNameBuild,
+		TaskRef: &pplnv1beta1.TaskRef{
+			Name: "func-dockerfile",
+		},
+		RunAfter: runAfter,
+		Workspaces: []pplnv1beta1.WorkspacePipelineTaskBinding{
+			{
+				Name:      "source",
+				Workspace: "source-workspace",
+			},
+			{
+				Name:      "cache",
+				Workspace: "cache-workspace",
+			},
+			{
+				Name:      "dockerconfig",
+				Workspace: "dockerconfig-workspace",
+			}},
+		Params: params,
+	}
+}
+
 func taskDeploy(runAfter string, referenceImageFromPreviousTaskResults bool) pplnv1beta1.PipelineTask {
 
 	params := []pplnv1beta1.Param{{Name: "path", Value: *pplnv1beta1.NewArrayOrString("$(workspaces.source.path)/$(params.contextDir)")}}
 
-	// Deploy step that uses an image produced by S2I builds needs explicit reference to the image
+	// Deploy step that uses an image produced by S2I or Dockerfile builds needs explicit reference to the image
 	if referenceImageFromPreviousTaskResults {
 		params = append(params, pplnv1beta1.Param{Name: "image", Value: *pplnv1beta1.NewArrayOrString(fmt.Sprintf("$(params.imageName)@$(tasks.%s.results.IMAGE_DIGEST)", runAfter))})
 	}
@@ -14,6 +14,8 @@ var (
 	ErrRuntimeRequired = errors.New("runtime is required to build")
 
 	ErrBuilpacksNotSupported = errors.New("additional Buildpacks are not supported for on cluster build")
+
+	ErrHostBuilderNotSupported = errors.New("the host builder is not supported for on cluster build")
 )
 
 type ErrRuntimeNotSupported struct {
@@ -40,6 +42,11 @@ func validatePipeline(f fn.Function) error {
 	} else if f.Build.Builder == builders.S2I {
 		_, err := s2i.BuilderImage(f, builders.S2I)
 		return err
+	} else if f.Build.Builder == builders.Dockerfile {
+		_, err := f.Dockerfile()
+		return err
+	} else if f.Build.Builder == builders.Host {
+		return ErrHostBuilderNotSupported
 	} else {
 		return builders.ErrUnknownBuilder{Name: f.Build.Builder}
 	}
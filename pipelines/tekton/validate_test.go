@@ -4,6 +4,8 @@
 package tekton
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	fn "knative.dev/func"
@@ -14,6 +16,11 @@ func Test_validatePipeline(t *testing.T) {
 
 	testBuildpacks := []string{"quay.io/foo/my-buildpack"}
 
+	testDockerfileRoot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(testDockerfileRoot, "Dockerfile"), []byte("FROM scratch"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
 	tests := []struct {
 		name     string
 		function fn.Function
@@ -104,6 +111,21 @@ func Test_validatePipeline(t *testing.T) {
 			function: fn.Function{Build: fn.BuildSpec{Builder: builders.S2I}, Runtime: "rust"},
 			wantErr:  true,
 		},
+		{
+			name:     "Without Dockerfile - dockerfile builder",
+			function: fn.Function{Root: t.TempDir(), Build: fn.BuildSpec{Builder: builders.Dockerfile}, Runtime: "go"},
+			wantErr:  true,
+		},
+		{
+			name:     "With Dockerfile - dockerfile builder",
+			function: fn.Function{Root: testDockerfileRoot, Build: fn.BuildSpec{Builder: builders.Dockerfile}, Runtime: "rust"},
+			wantErr:  false,
+		},
+		{
+			name:     "Supported runtime - Go - host builder",
+			function: fn.Function{Build: fn.BuildSpec{Builder: builders.Host}, Runtime: "go"},
+			wantErr:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -109,6 +109,10 @@ func TestRepository_Inheritance(t *testing.T) {
 	if diff := cmp.Diff([]string{"runtimeBuildpack"}, fB.Build.Buildpacks); diff != "" {
 		t.Errorf("Runtime-level Buildpack differs (-want, +got): %s", diff)
 	}
+	startupProbe := fn.ProbeOptions{PeriodSeconds: 5, FailureThreshold: 60}
+	if fB.Deploy.HealthEndpoints.Startup != "/runtimeStartup" || fB.Deploy.HealthEndpoints.Probes.Startup != startupProbe {
+		t.Errorf("Runtime-level startup probe not loaded to template, got %q %+v", fB.Deploy.HealthEndpoints.Startup, fB.Deploy.HealthEndpoints.Probes.Startup)
+	}
 
 	envVarName := "TEST_RUNTIME_VARIABLE"
 	envVarValue := "test-runtime"
@@ -130,4 +134,8 @@ func TestRepository_Inheritance(t *testing.T) {
 	if diff := cmp.Diff([]string{"templateBuildpack"}, fC.Build.Buildpacks); diff != "" {
 		t.Fatalf("Template-level Buildpack differs (-want, +got): %s", diff)
 	}
+	// The startup probe, not set by the template, is inherited from the runtime
+	if fC.Deploy.HealthEndpoints.Probes.Startup != startupProbe {
+		t.Fatalf("Runtime-level startup probe not inherited by template, got %+v", fC.Deploy.HealthEndpoints.Probes.Startup)
+	}
 }
@@ -17,8 +17,10 @@ import (
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/openshift/source-to-image/pkg/api"
+	"github.com/openshift/source-to-image/pkg/api/constants"
 	"github.com/openshift/source-to-image/pkg/api/validation"
 	"github.com/openshift/source-to-image/pkg/build"
 	"github.com/openshift/source-to-image/pkg/build/strategies"
@@ -29,6 +31,7 @@ import (
 	fn "knative.dev/func"
 	"knative.dev/func/builders"
 	"knative.dev/func/docker"
+	"knative.dev/func/oci"
 )
 
 // DefaultName when no WithName option is provided to NewBuilder
@@ -44,8 +47,8 @@ var DefaultBuilderImages = map[string]string{
 
 // DockerClient is subset of dockerClient.CommonAPIClient required by this package
 type DockerClient interface {
+	daemon.Client
 	ImageBuild(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
-	ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error)
 }
 
 // Builder of functions using the s2i subsystem.
@@ -102,7 +105,37 @@ func NewBuilder(options ...Option) *Builder {
 	return b
 }
 
+// Build the function's image.  When built for several platforms, such as
+// "linux/amd64,linux/arm64", the image built for each is written to the
+// function's image layout as an image index (see oci.BuildVariants).
 func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
+	platforms, err := oci.ParsePlatforms(b.platform)
+	if err != nil {
+		return
+	}
+
+	var client = b.cli
+	if client == nil {
+		var c dockerClient.CommonAPIClient
+		c, _, err = docker.NewClient(dockerClient.DefaultDockerHost)
+		if err != nil {
+			return fmt.Errorf("cannot create docker client: %w", err)
+		}
+		defer c.Close()
+		client = c
+	}
+
+	if len(platforms) <= 1 {
+		return b.build(ctx, client, f, b.platform)
+	}
+	return oci.BuildVariants(ctx, f, client, platforms, func(platform v1.Platform) error {
+		return b.build(ctx, client, f, platform.String())
+	})
+}
+
+// build the function's image for the given platform, or that of the builder
+// image if none, into the daemon.
+func (b *Builder) build(ctx context.Context, client DockerClient, f fn.Function, platform string) (err error) {
 	// TODO this function currently doesn't support private s2i builder images since credentials are not set
 
 	// Builder image from the function if defined, default otherwise.
@@ -111,8 +144,8 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 		return
 	}
 
-	if b.platform != "" {
-		builderImage, err = docker.GetPlatformImage(builderImage, b.platform)
+	if platform != "" {
+		builderImage, err = docker.GetPlatformImage(builderImage, platform)
 		if err != nil {
 			return fmt.Errorf("cannot get platform specific image reference: %w", err)
 		}
@@ -137,17 +170,6 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 
 	cfg.AsDockerfile = filepath.Join(tmp, "Dockerfile")
 
-	var client = b.cli
-	if client == nil {
-		var c dockerClient.CommonAPIClient
-		c, _, err = docker.NewClient(dockerClient.DefaultDockerHost)
-		if err != nil {
-			return fmt.Errorf("cannot create docker client: %w", err)
-		}
-		defer c.Close()
-		client = c
-	}
-
 	scriptURL, err := s2iScriptURL(ctx, client, cfg.BuilderImage)
 	if err != nil {
 		return fmt.Errorf("cannot get s2i script url: %w", err)
@@ -201,9 +223,17 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 		}
 	}
 
+	// Paths listed in .funcignore are excluded from the function source
+	// s2i has placed in the build context.
+	ignored, err := f.Ignorer()
+	if err != nil {
+		return
+	}
+
 	pr, pw := io.Pipe()
 
 	const up = ".." + string(os.PathSeparator)
+	const src = constants.Source + string(os.PathSeparator)
 	go func() {
 		tw := tar.NewWriter(pw)
 		err := filepath.Walk(tmp, func(path string, fi fs.FileInfo, err error) error {
@@ -219,6 +249,13 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 				return nil
 			}
 
+			if strings.HasPrefix(p, src) && ignored(strings.TrimPrefix(p, src), fi.IsDir()) {
+				if fi.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
 			lnk := ""
 			if fi.Mode()&fs.ModeSymlink != 0 {
 				lnk, err = os.Readlink(path)
@@ -268,6 +305,7 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 
 	opts := types.ImageBuildOptions{
 		Tags:       []string{f.Image},
+		Platform:   platform,
 		PullParent: true,
 	}
 
@@ -368,6 +368,68 @@ func TestBuildContextUpload(t *testing.T) {
 	}
 }
 
+// TestBuildContextFuncIgnore ensures that the paths listed in the function's
+// .funcignore are not included in the build context.
+func TestBuildContextFuncIgnore(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, fn.IgnoreFile), []byte("node_modules/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var indexFound bool
+	cli := mockDocker{
+		build: func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+			tr := tar.NewReader(context)
+			for {
+				hdr, err := tr.Next()
+				if err != nil {
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					return types.ImageBuildResponse{}, err
+				}
+				if strings.HasPrefix(hdr.Name, "upload/src/node_modules") {
+					t.Errorf("build context contains ignored path: %q", hdr.Name)
+				}
+				if hdr.Name == "upload/src/index.js" {
+					indexFound = true
+				}
+			}
+			return types.ImageBuildResponse{
+				Body:   io.NopCloser(strings.NewReader(`{"stream": "OK!"}`)),
+				OSType: "linux",
+			}, nil
+		},
+	}
+
+	impl := &mockImpl{
+		BuildFn: func(config *api.Config) (*api.Result, error) {
+			dir := filepath.Dir(config.AsDockerfile)
+			if err := os.WriteFile(config.AsDockerfile, []byte("FROM scratch"), 0644); err != nil {
+				return nil, err
+			}
+			if err := os.MkdirAll(filepath.Join(dir, "upload", "src", "node_modules", "dep"), 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(filepath.Join(dir, "upload", "src", "node_modules", "dep", "index.js"), nil, 0644); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(filepath.Join(dir, "upload", "src", "index.js"), nil, 0644); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		},
+	}
+
+	b := s2i.NewBuilder(s2i.WithImpl(impl), s2i.WithDockerClient(cli))
+	if err := b.Build(context.Background(), fn.Function{Root: root, Runtime: "node"}); err != nil {
+		t.Fatal(err)
+	}
+	if !indexFound {
+		t.Error("build context is missing index.js")
+	}
+}
+
 func TestBuildFail(t *testing.T) {
 	cli := mockDocker{
 		build: func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
@@ -423,6 +485,20 @@ func (m mockDocker) ImageBuild(ctx context.Context, context io.Reader, options t
 	}, nil
 }
 
+func (m mockDocker) NegotiateAPIVersion(ctx context.Context) {}
+
+func (m mockDocker) ImageSave(ctx context.Context, images []string) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m mockDocker) ImageLoad(ctx context.Context, input io.Reader, quiet bool) (types.ImageLoadResponse, error) {
+	return types.ImageLoadResponse{}, errors.New("not implemented")
+}
+
+func (m mockDocker) ImageTag(ctx context.Context, source, target string) error {
+	return errors.New("not implemented")
+}
+
 type notFoundErr struct {
 }
 
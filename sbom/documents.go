@@ -0,0 +1,212 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	fn "knative.dev/func"
+)
+
+// Subject of an SBOM: the image whose packages it lists.
+type Subject struct {
+	// Name of the image, such as that of the function.
+	Name string
+	// Digest identifying the image as pushed: that of its manifest or of the
+	// image index of a multi-platform image.  Empty if not yet known, as of
+	// images built into the daemon.
+	Digest string
+	// Created is the time the image was created, and that of the SBOM.
+	Created time.Time
+}
+
+// Encode an SBOM of the subject listing the packages, in the format given:
+// either SPDX 2.3 or CycloneDX 1.4 JSON.
+func Encode(format string, subject Subject, pkgs []Package) ([]byte, error) {
+	switch format {
+	case fn.SBOMFormatSPDX:
+		return json.MarshalIndent(spdxDocument(subject, pkgs), "", "  ")
+	case fn.SBOMFormatCycloneDX:
+		return json.MarshalIndent(cycloneDXDocument(subject, pkgs), "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported SBOM format %q", format)
+	}
+}
+
+// creator of the SBOMs, as recorded within them.
+const creator = "func"
+
+// SPDX
+// ----
+// See https://spdx.github.io/spdx-spec/v2.3/
+
+type spdxDoc struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	PrimaryPurpose   string            `json:"primaryPackagePurpose,omitempty"`
+	SourceInfo       string            `json:"sourceInfo,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	Category string `json:"referenceCategory"`
+	Type     string `json:"referenceType"`
+	Locator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	Element string `json:"spdxElementId"`
+	Type    string `json:"relationshipType"`
+	Related string `json:"relatedSpdxElement"`
+}
+
+const spdxImageID = "SPDXRef-Image"
+
+// spdxNamespace of the subject's document: unique to the image by its
+// digest, or by when it was created if its digest is not yet known.
+func spdxNamespace(subject Subject) string {
+	id := subject.Digest
+	if id == "" {
+		id = strconv.FormatInt(subject.Created.Unix(), 10)
+	}
+	return "https://knative.dev/func/spdx/" + url.PathEscape(subject.Name) + "/" + id
+}
+
+// spdxDocument which describes the image as a package containing each of
+// the packages found within it.
+func spdxDocument(subject Subject, pkgs []Package) spdxDoc {
+	doc := spdxDoc{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              subject.Name,
+		DocumentNamespace: spdxNamespace(subject),
+		CreationInfo: spdxCreationInfo{
+			Created:  subject.Created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + creator},
+		},
+		Packages: []spdxPackage{{
+			SPDXID:           spdxImageID,
+			Name:             subject.Name,
+			VersionInfo:      subject.Digest,
+			DownloadLocation: "NOASSERTION",
+			PrimaryPurpose:   "CONTAINER",
+		}},
+		Relationships: []spdxRelationship{{
+			Element: "SPDXRef-DOCUMENT",
+			Type:    "DESCRIBES",
+			Related: spdxImageID,
+		}},
+	}
+	for i, p := range pkgs {
+		id := "SPDXRef-Package-" + strconv.Itoa(i+1)
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SPDXID:           id,
+			Name:             p.Name,
+			VersionInfo:      p.Version,
+			DownloadLocation: "NOASSERTION",
+			SourceInfo:       "found at " + p.Location,
+			ExternalRefs: []spdxExternalRef{{
+				Category: "PACKAGE-MANAGER",
+				Type:     "purl",
+				Locator:  p.PURL(),
+			}},
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			Element: spdxImageID,
+			Type:    "CONTAINS",
+			Related: id,
+		})
+	}
+	return doc
+}
+
+// CycloneDX
+// ---------
+// See https://cyclonedx.org/docs/1.4/json/
+
+type cdxDoc struct {
+	BOMFormat   string         `json:"bomFormat"`
+	SpecVersion string         `json:"specVersion"`
+	Version     int            `json:"version"`
+	Metadata    cdxMetadata    `json:"metadata"`
+	Components  []cdxComponent `json:"components"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     []cdxTool    `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTool struct {
+	Name string `json:"name"`
+}
+
+type cdxComponent struct {
+	BOMRef     string        `json:"bom-ref"`
+	Type       string        `json:"type"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	PURL       string        `json:"purl,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// cycloneDXDocument whose metadata describes the image as a container, with
+// each of the packages found within it as a component.
+func cycloneDXDocument(subject Subject, pkgs []Package) cdxDoc {
+	doc := cdxDoc{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.4",
+		Version:     1,
+		Metadata: cdxMetadata{
+			Timestamp: subject.Created.UTC().Format(time.RFC3339),
+			Tools:     []cdxTool{{Name: creator}},
+			Component: cdxComponent{
+				BOMRef:  "image",
+				Type:    "container",
+				Name:    subject.Name,
+				Version: subject.Digest,
+			},
+		},
+		Components: []cdxComponent{},
+	}
+	for _, p := range pkgs {
+		purl := p.PURL()
+		doc.Components = append(doc.Components, cdxComponent{
+			BOMRef:     purl,
+			Type:       "library",
+			Name:       p.Name,
+			Version:    p.Version,
+			PURL:       purl,
+			Properties: []cdxProperty{{Name: "func:location", Value: p.Location}},
+		})
+	}
+	return doc
+}
@@ -0,0 +1,243 @@
+/*
+Package sbom generates the software bill of materials (SBOM) of function
+images.  The filesystem of the image built is scanned for the packages within
+it, such as those installed by the distribution's package manager, the Go
+modules from which its executables were built, and the npm and Python packages
+installed, and these are listed in an SPDX or CycloneDX document kept in the
+function's .func directory.  The SBOMs recorded by buildpacks in images built
+with them are surfaced alongside.
+*/
+package sbom
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	dockerClient "github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	fn "knative.dev/func"
+	"knative.dev/func/docker"
+)
+
+// BuildpacksDir is the name of the directory within the function's SBOM
+// directory to which the SBOMs recorded by buildpacks are written.
+const BuildpacksDir = "buildpacks"
+
+// DockerClient is the subset of dockerClient.CommonAPIClient required to read
+// images built into the daemon.
+type DockerClient interface {
+	ImageSave(ctx context.Context, images []string) (io.ReadCloser, error)
+	Close() error
+}
+
+type DockerClientFactory func() (DockerClient, error)
+
+// Generator of the SBOMs of function images.
+type Generator struct {
+	dockerClientFactory DockerClientFactory
+}
+
+type Option func(*Generator)
+
+// WithDockerClientFactory sets the factory of the client of the daemon from
+// which images built into it are read.
+func WithDockerClientFactory(f DockerClientFactory) Option {
+	return func(g *Generator) {
+		g.dockerClientFactory = f
+	}
+}
+
+// NewGenerator creates an instance of a Generator with static defaults.
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{
+		dockerClientFactory: func() (DockerClient, error) {
+			c, _, err := docker.NewClient(dockerClient.DefaultDockerHost)
+			return c, err
+		},
+	}
+	for _, o := range options {
+		o(g)
+	}
+	return g
+}
+
+// Generate the SBOM of the function's built image in the format of its
+// build.sbom, written to its SBOM file (see fn.Function.SBOMFile).  The image
+// is that of the function's image layout if built without a daemon, or for
+// several platforms, in which case the packages of each are listed, and
+// otherwise that of the daemon.  Any SBOMs recorded by the buildpacks which
+// built the image are written to the buildpacks directory beside it, in a
+// directory of each platform's own if built for several.
+//
+// The digest of an image built into the daemon is not known until pushed, as
+// its manifest is created then, so its SBOM names none.
+func (g *Generator) Generate(ctx context.Context, f fn.Function) error {
+	file, _ := f.SBOMFile()
+	if file == "" {
+		return fmt.Errorf("no SBOM format set, expected build.sbom to be one of: %v, %v", fn.SBOMFormatSPDX, fn.SBOMFormatCycloneDX)
+	}
+
+	var (
+		subject   = Subject{Name: f.Image}
+		images    []v1.Image
+		platforms []*v1.Platform
+	)
+	if f.HasImageLayout() {
+		index, err := layout.ImageIndexFromPath(f.ImageLayout())
+		if err != nil {
+			return fmt.Errorf("cannot read the image layout %v: %w", f.ImageLayout(), err)
+		}
+		if images, platforms, subject.Digest, err = layoutImages(index); err != nil {
+			return err
+		}
+	} else {
+		tmp, err := os.MkdirTemp("", "func-sbom")
+		if err != nil {
+			return fmt.Errorf("cannot create temporary dir for the image: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		img, err := g.daemonImage(ctx, f, filepath.Join(tmp, "image.tar"))
+		if err != nil {
+			return err
+		}
+		images = []v1.Image{img}
+	}
+
+	// Those of a previous build are not of this image.
+	buildpacks := filepath.Join(f.SBOMDir(), BuildpacksDir)
+	if err := os.RemoveAll(buildpacks); err != nil {
+		return err
+	}
+
+	var pkgs []Package
+	for i, img := range images {
+		p, err := Packages(img)
+		if err != nil {
+			return fmt.Errorf("cannot read the packages of the image: %w", err)
+		}
+		pkgs = append(pkgs, p...)
+
+		dir := buildpacks
+		if platforms != nil {
+			dir = filepath.Join(dir, platformDir(platforms[i]))
+		}
+		if _, err = WriteBuildpackSBOMs(img, dir); err != nil {
+			return err
+		}
+
+		cfg, err := img.ConfigFile()
+		if err != nil {
+			return err
+		}
+		if cfg.Created.After(subject.Created) {
+			subject.Created = cfg.Created.Time
+		}
+	}
+	if subject.Created.IsZero() {
+		subject.Created = time.Unix(0, 0) // as are reproducible builds
+	}
+
+	doc, err := Encode(f.Build.SBOM, subject, unique(pkgs))
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(f.SBOMDir(), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(file, doc, 0644)
+}
+
+// layoutImages of the single image or image index of an image layout, and
+// the digest of that pushed: of the image's manifest or of the index.  The
+// platform of each image is returned if of an index.
+func layoutImages(index v1.ImageIndex) (images []v1.Image, platforms []*v1.Platform, digest string, err error) {
+	im, err := index.IndexManifest()
+	if err != nil {
+		return
+	}
+	if len(im.Manifests) != 1 {
+		return nil, nil, "", fmt.Errorf("expected one image in the image layout, found %v", len(im.Manifests))
+	}
+	desc := im.Manifests[0]
+	if !desc.MediaType.IsIndex() {
+		img, err := index.Image(desc.Digest)
+		return []v1.Image{img}, nil, desc.Digest.String(), err
+	}
+
+	ii, err := index.ImageIndex(desc.Digest)
+	if err != nil {
+		return
+	}
+	if im, err = ii.IndexManifest(); err != nil {
+		return
+	}
+	for _, m := range im.Manifests {
+		img, err := ii.Image(m.Digest)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		if m.Platform == nil {
+			m.Platform = &v1.Platform{}
+			if cfg, err := img.ConfigFile(); err == nil {
+				m.Platform.OS, m.Platform.Architecture, m.Platform.Variant = cfg.OS, cfg.Architecture, cfg.Variant
+			}
+		}
+		images = append(images, img)
+		platforms = append(platforms, m.Platform)
+	}
+	return images, platforms, desc.Digest.String(), nil
+}
+
+// platformDir is the name of the directory of the platform's buildpack SBOMs,
+// such as linux-arm64-v8.
+func platformDir(p *v1.Platform) string {
+	var parts []string
+	for _, s := range []string{p.OS, p.Architecture, p.Variant} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if len(parts) == 0 {
+		return "unknown"
+	}
+	return strings.Join(parts, "-")
+}
+
+// daemonImage of the function, as built into the daemon, saved to the file at
+// path such that its layers are each read once.
+func (g *Generator) daemonImage(ctx context.Context, f fn.Function, path string) (v1.Image, error) {
+	ref, err := name.ParseReference(f.Image)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := g.dockerClientFactory()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create docker client: %w", err)
+	}
+	defer cli.Close()
+
+	rc, err := cli.ImageSave(ctx, []string{ref.Name()})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the image %v from the daemon: %w", f.Image, err)
+	}
+	defer rc.Close()
+	w, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(w, rc)
+	w.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the image %v from the daemon: %w", f.Image, err)
+	}
+	return tarball.ImageFromPath(path, nil)
+}
@@ -0,0 +1,413 @@
+package sbom
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	fn "knative.dev/func"
+)
+
+const dpkgStatus = `Package: libc6
+Status: install ok installed
+Architecture: amd64
+Version: 2.31-13+deb11u5
+Description: GNU C Library
+ continuation: not a field
+
+Package: removed
+Status: deinstall ok config-files
+Version: 1.0
+`
+
+const apkInstalled = `P:musl
+V:1.2.3-r4
+A:x86_64
+
+P:busybox
+V:1.35.0-r29
+A:x86_64
+`
+
+// TestPackages ensures that the packages of each kind are found in the
+// filesystem of an image, each once and ordered by package URL, and that
+// files removed by a later layer are not read.
+func TestPackages(t *testing.T) {
+	img := testImage(t, map[string]string{
+		"etc/os-release":                                               "NAME=\"Debian GNU/Linux\"\nID=debian\n",
+		"var/lib/dpkg/status":                                          dpkgStatus,
+		"var/lib/dpkg/status.d/tzdata":                                 "Package: tzdata\nVersion: 2021a-1+deb11u8\nArchitecture: all\n",
+		"lib/apk/db/installed":                                         apkInstalled,
+		"app/node_modules/express/package.json":                        `{"name": "express", "version": "4.18.2"}`,
+		"app/node_modules/@scope/pkg/package.json":                     `{"name": "@scope/pkg", "version": "1.0.0"}`,
+		"app/node_modules/express/lib/package.json":                    `{"name": "not-a-module", "version": "1.0.0"}`,
+		"app/package.json":                                             `{"name": "the-function", "version": "0.1.0"}`,
+		"usr/lib/python3/site-packages/Flask-2.2.2.dist-info/METADATA": "Metadata-Version: 2.1\nName: Flask\nVersion: 2.2.2\n\nName: body\n",
+	}, map[string]string{
+		"app/node_modules/express/package.json": "", // removed
+	})
+
+	pkgs, err := Packages(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var purls []string
+	for _, p := range pkgs {
+		purls = append(purls, p.PURL())
+	}
+	expected := []string{
+		"pkg:apk/debian/busybox@1.35.0-r29?arch=x86_64",
+		"pkg:apk/debian/musl@1.2.3-r4?arch=x86_64",
+		"pkg:deb/debian/libc6@2.31-13%2Bdeb11u5?arch=amd64",
+		"pkg:deb/debian/tzdata@2021a-1%2Bdeb11u8?arch=all",
+		"pkg:npm/%40scope/pkg@1.0.0",
+		"pkg:pypi/flask@2.2.2",
+	}
+	if strings.Join(purls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected packages\n%v\ngot\n%v", strings.Join(expected, "\n"), strings.Join(purls, "\n"))
+	}
+	if pkgs[0].Location != "/lib/apk/db/installed" {
+		t.Errorf("expected the location of the package, got %q", pkgs[0].Location)
+	}
+}
+
+// TestPackages_Go ensures that the modules from which a Go executable was
+// built, and its standard library, are found.
+func TestPackages_Go(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip("the test executable is not available")
+	}
+	bin, err := os.ReadFile(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := testImage(t, map[string]string{"usr/bin/f": string(bin)}, nil)
+
+	pkgs, err := Packages(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdlib, module bool
+	for _, p := range pkgs {
+		if p.Type != "golang" || p.Location != "/usr/bin/f" {
+			t.Errorf("unexpected package %v at %v", p.PURL(), p.Location)
+		}
+		stdlib = stdlib || p.Name == "stdlib"
+		module = module || p.PURL() == "pkg:golang/github.com/google/go-containerregistry@v0.11.0"
+	}
+	if !stdlib || !module {
+		t.Errorf("expected the standard library and go-containerregistry, got %v", pkgs)
+	}
+}
+
+// TestEncode ensures that the SBOM documents describe the image and list
+// each package by its package URL.
+func TestEncode(t *testing.T) {
+	subject := Subject{Name: "example.com/alice/f:latest", Digest: "sha256:0123"}
+	pkgs := []Package{{Type: "deb", Namespace: "debian", Name: "libc6", Version: "2.31", Location: "/var/lib/dpkg/status"}}
+
+	doc, err := Encode(fn.SBOMFormatSPDX, subject, pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spdx spdxDoc
+	if err = json.Unmarshal(doc, &spdx); err != nil {
+		t.Fatal(err)
+	}
+	if spdx.SPDXVersion != "SPDX-2.3" || len(spdx.Packages) != 2 || spdx.Packages[0].VersionInfo != subject.Digest {
+		t.Errorf("expected an SPDX document of the image, got %s", doc)
+	}
+	if ref := spdx.Packages[1].ExternalRefs; len(ref) != 1 || ref[0].Locator != "pkg:deb/debian/libc6@2.31" {
+		t.Errorf("expected the package URL of the package, got %v", ref)
+	}
+	if r := spdx.Relationships; len(r) != 2 || r[0].Type != "DESCRIBES" || r[1].Type != "CONTAINS" || r[1].Related != spdx.Packages[1].SPDXID {
+		t.Errorf("expected the image to be described, containing the package, got %v", r)
+	}
+
+	if doc, err = Encode(fn.SBOMFormatCycloneDX, subject, pkgs); err != nil {
+		t.Fatal(err)
+	}
+	var cdx cdxDoc
+	if err = json.Unmarshal(doc, &cdx); err != nil {
+		t.Fatal(err)
+	}
+	if cdx.BOMFormat != "CycloneDX" || cdx.Metadata.Component.Type != "container" || cdx.Metadata.Component.Version != subject.Digest {
+		t.Errorf("expected a CycloneDX document of the image, got %s", doc)
+	}
+	if len(cdx.Components) != 1 || cdx.Components[0].PURL != "pkg:deb/debian/libc6@2.31" {
+		t.Errorf("expected the package as a component, got %v", cdx.Components)
+	}
+
+	if _, err = Encode("swid", subject, pkgs); err == nil {
+		t.Error("expected an unsupported format to error")
+	}
+}
+
+// TestWriteBuildpackSBOMs ensures that the SBOMs recorded by buildpacks in
+// the layer referenced by the lifecycle metadata are written.
+func TestWriteBuildpackSBOMs(t *testing.T) {
+	img := testImage(t, map[string]string{"etc/os-release": "ID=ubuntu\n"}, nil)
+	sbomLayer := testLayer(t, map[string]string{
+		"layers/sbom/launch/paketo-buildpacks_go-build/targets/sbom.cdx.json": `{"bomFormat": "CycloneDX"}`,
+		"layers/sbom/launch/sbom.legacy.json":                                 `[]`,
+	}, nil)
+	img, err := mutate.AppendLayers(img, sbomLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffID, err := sbomLayer.DiffID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	img = withLabel(t, img, lifecycleLabel, `{"sbom": {"sha": "`+diffID.String()+`"}}`)
+
+	dir := t.TempDir()
+	files, err := WriteBuildpackSBOMs(img, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected two SBOM files, got %v", files)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "launch", "paketo-buildpacks_go-build", "targets", "sbom.cdx.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "CycloneDX") {
+		t.Errorf("unexpected SBOM %s", b)
+	}
+
+	// Images built otherwise have none
+	if files, err = WriteBuildpackSBOMs(testImage(t, nil, nil), t.TempDir()); err != nil || len(files) != 0 {
+		t.Errorf("expected no SBOM files, got %v, %v", files, err)
+	}
+}
+
+// TestGenerate ensures that the SBOM of the image in the function's image
+// layout is written to its SBOM file.
+func TestGenerate(t *testing.T) {
+	root := t.TempDir()
+	f := fn.Function{Root: root, Image: "example.com/alice/f:latest", Build: fn.BuildSpec{SBOM: fn.SBOMFormatCycloneDX}}
+	img := testImage(t, map[string]string{"lib/apk/db/installed": apkInstalled, "etc/os-release": "ID=alpine\n"}, nil)
+	p, err := layout.Write(f.ImageLayout(), empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.AppendImage(img); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = NewGenerator().Generate(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	file, _ := f.SBOMFile()
+	var doc cdxDoc
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Metadata.Component.Name != f.Image || doc.Metadata.Component.Version != digest.String() {
+		t.Errorf("expected the image %v %v, got %v", f.Image, digest, doc.Metadata.Component)
+	}
+	if len(doc.Components) != 2 || doc.Components[0].PURL != "pkg:apk/alpine/busybox@1.35.0-r29?arch=x86_64" {
+		t.Errorf("unexpected components %v", doc.Components)
+	}
+}
+
+// TestGenerate_Daemon ensures that the SBOM of an image built into the daemon
+// is generated from the image saved from it.
+func TestGenerate_Daemon(t *testing.T) {
+	f := fn.Function{Root: t.TempDir(), Image: "example.com/alice/f:latest", Build: fn.BuildSpec{SBOM: fn.SBOMFormatSPDX}}
+	img := testImage(t, map[string]string{"var/lib/dpkg/status": dpkgStatus}, nil)
+	cli := &mockDocker{img: img, ref: f.Image}
+
+	g := NewGenerator(WithDockerClientFactory(func() (DockerClient, error) { return cli, nil }))
+	if err := g.Generate(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if !cli.saved {
+		t.Error("expected the image to be saved from the daemon")
+	}
+	file, _ := f.SBOMFile()
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "pkg:deb/debian/libc6@2.31-13%2Bdeb11u5?arch=amd64") {
+		t.Errorf("expected the SPDX document to list libc6, got\n%s", b)
+	}
+	// Its manifest, and so digest, is not created until pushed.
+	if strings.Contains(string(b), "sha256:") {
+		t.Errorf("expected the SPDX document to name no digest of the image, got\n%s", b)
+	}
+}
+
+// TestGenerate_Platforms ensures that the SBOM of a multi-platform image
+// names its index, and that the buildpack SBOMs of each platform are written
+// to a directory of its own.
+func TestGenerate_Platforms(t *testing.T) {
+	f := fn.Function{Root: t.TempDir(), Image: "example.com/alice/f:latest", Build: fn.BuildSpec{SBOM: fn.SBOMFormatCycloneDX}}
+	amd64 := buildpackImage(t, "amd64")
+	arm64 := buildpackImage(t, "arm64")
+	ii := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: amd64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}}},
+		mutate.IndexAddendum{Add: arm64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}}})
+	p, err := layout.Write(f.ImageLayout(), empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.AppendIndex(ii); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = NewGenerator().Generate(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	file, _ := f.SBOMFile()
+	var doc cdxDoc
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	digest, err := ii.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Metadata.Component.Version != digest.String() {
+		t.Errorf("expected the image index %v, got %v", digest, doc.Metadata.Component)
+	}
+	for dir, arch := range map[string]string{"linux-amd64": "amd64", "linux-arm64-v8": "arm64"} {
+		b, err := os.ReadFile(filepath.Join(f.SBOMDir(), BuildpacksDir, dir, "launch", "sbom.cdx.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), arch) {
+			t.Errorf("expected the SBOM of %v in %v, got %s", arch, dir, b)
+		}
+	}
+}
+
+type mockDocker struct {
+	img   v1.Image
+	ref   string
+	saved bool
+}
+
+func (m *mockDocker) ImageSave(_ context.Context, images []string) (io.ReadCloser, error) {
+	m.saved = true
+	tag, err := name.NewTag(m.ref)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = tarball.Write(tag, m.img, &buf); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(&buf), nil
+}
+
+func (m *mockDocker) Close() error { return nil }
+
+// testImage of a single layer of the given files, and a second layer of
+// whiteouts removing those of the paths given.
+func testImage(t *testing.T, files, removed map[string]string) v1.Image {
+	t.Helper()
+	layers := []v1.Layer{testLayer(t, files, nil)}
+	if len(removed) > 0 {
+		layers = append(layers, testLayer(t, nil, removed))
+	}
+	img, err := mutate.AppendLayers(empty.Image, layers...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+// testLayer of the given files, and whiteouts of those of the paths
+// removed.
+func testLayer(t *testing.T, files, removed map[string]string) v1.Layer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	write := func(path, content string) {
+		mode := int64(0644)
+		if strings.HasPrefix(path, "usr/bin/") {
+			mode = 0755
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: path, Mode: mode, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range files {
+		write(path, content)
+	}
+	for path := range removed {
+		write(filepath.ToSlash(filepath.Join(filepath.Dir(path), ".wh."+filepath.Base(path))), "")
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	layer, err := tarball.LayerFromReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return layer
+}
+
+// buildpackImage with an SBOM recorded by buildpacks naming the architecture.
+func buildpackImage(t *testing.T, arch string) v1.Image {
+	t.Helper()
+	layer := testLayer(t, map[string]string{"layers/sbom/launch/sbom.cdx.json": `{"arch": "` + arch + `"}`}, nil)
+	img, err := mutate.AppendLayers(testImage(t, nil, nil), layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffID, err := layer.DiffID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return withLabel(t, img, lifecycleLabel, `{"sbom": {"sha": "`+diffID.String()+`"}}`)
+}
+
+// withLabel returns the image with the label set.
+func withLabel(t *testing.T, img v1.Image, key, value string) v1.Image {
+	t.Helper()
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = cfg.DeepCopy()
+	cfg.Config.Labels = map[string]string{key: value}
+	img, err = mutate.ConfigFile(img, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
@@ -0,0 +1,101 @@
+package sbom
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// lifecycleLabel of images built with buildpacks, whose metadata references
+// the layer holding the SBOMs recorded by the buildpacks that built it.
+const lifecycleLabel = "io.buildpacks.lifecycle.metadata"
+
+// lifecycleMetadata is the subset of that of the lifecycleLabel required to
+// find the SBOM layer.
+type lifecycleMetadata struct {
+	SBOM *struct {
+		SHA string `json:"sha"`
+	} `json:"sbom"`
+}
+
+// WriteBuildpackSBOMs of an image built with buildpacks to dir, returning the
+// paths of the files written relative to it.  These are the SBOMs recorded by
+// each buildpack for the layers it contributed, such as
+// launch/<buildpack>/<layer>/sbom.cdx.json.  Images built otherwise, or by
+// buildpacks recording none, have none.
+func WriteBuildpackSBOMs(img v1.Image, dir string) (files []string, err error) {
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return
+	}
+	label := cfg.Config.Labels[lifecycleLabel]
+	if label == "" {
+		return
+	}
+	var md lifecycleMetadata
+	if err = json.Unmarshal([]byte(label), &md); err != nil {
+		return nil, fmt.Errorf("cannot read the %v label: %w", lifecycleLabel, err)
+	}
+	if md.SBOM == nil || md.SBOM.SHA == "" {
+		return
+	}
+
+	hash, err := v1.NewHash(md.SBOM.SHA)
+	if err != nil {
+		return
+	}
+	layer, err := img.LayerByDiffID(hash)
+	if err != nil {
+		if layer, err = img.LayerByDigest(hash); err != nil {
+			return nil, fmt.Errorf("cannot find the SBOM layer %v: %w", hash, err)
+		}
+	}
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return
+	}
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		// Files are those within the layers/sbom directory of the image.
+		_, name, ok := strings.Cut(filepath.ToSlash(hdr.Name), "sbom/")
+		if !ok || name == "" {
+			continue
+		}
+		name = filepath.Clean(filepath.FromSlash(name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(os.PathSeparator)) {
+			return nil, fmt.Errorf("path %q points outside the SBOM layer", hdr.Name)
+		}
+		path := filepath.Join(dir, name)
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		w, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.Copy(w, tr)
+		w.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, name)
+	}
+}
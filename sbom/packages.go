@@ -0,0 +1,275 @@
+package sbom
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"debug/buildinfo"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+)
+
+// maxBinarySize of the executables read for the Go modules they were built
+// from.  Larger executables are skipped.
+const maxBinarySize = 256 << 20
+
+// Package of software found in an image.
+type Package struct {
+	// Type of the package, that of its package URL: deb, apk, golang, npm or
+	// pypi.
+	Type string
+
+	// Namespace of the package, such as the distribution of an OS package.
+	// Optional.
+	Namespace string
+
+	Name    string
+	Version string
+
+	// Arch of an OS package.  Optional.
+	Arch string
+
+	// Location within the image at which the package was found.
+	Location string
+}
+
+// PURL returns the package URL identifying the package.
+// See https://github.com/package-url/purl-spec
+func (p Package) PURL() string {
+	var b strings.Builder
+	b.WriteString("pkg:" + p.Type + "/")
+	if p.Namespace != "" {
+		b.WriteString(escapePath(p.Namespace) + "/")
+	}
+	b.WriteString(escapePath(p.Name))
+	if p.Version != "" {
+		b.WriteString("@" + url.QueryEscape(p.Version))
+	}
+	if p.Arch != "" {
+		b.WriteString("?arch=" + url.QueryEscape(p.Arch))
+	}
+	return b.String()
+}
+
+// escapePath escapes each segment of a slash-separated path of a package
+// URL, including any '@' of an npm scope.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(s), "@", "%40")
+	}
+	return strings.Join(segments, "/")
+}
+
+// Packages found in the filesystem of the image: those installed with dpkg
+// or apk, the Go modules from which its executables were built, and the npm
+// and Python packages installed.  Each package is listed once, ordered by
+// package URL.
+func Packages(img v1.Image) ([]Package, error) {
+	rc := mutate.Extract(img)
+	defer rc.Close()
+
+	var (
+		pkgs   []Package
+		distro string
+		tr     = tar.NewReader(rc)
+	)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		location := "/" + name
+
+		var found []Package
+		switch {
+		case name == "etc/os-release" || (name == "usr/lib/os-release" && distro == ""):
+			distro = osReleaseID(tr)
+		case name == "var/lib/dpkg/status" || path.Dir(name) == "var/lib/dpkg/status.d":
+			found = dpkgPackages(tr)
+		case name == "lib/apk/db/installed":
+			found = apkPackages(tr)
+		case path.Base(name) == "package.json" && isNodeModule(name):
+			found = npmPackages(tr)
+		case path.Base(name) == "METADATA" && strings.HasSuffix(path.Dir(name), ".dist-info"):
+			found = pythonPackages(tr)
+		case hdr.Mode&0111 != 0 && hdr.Size > 4 && hdr.Size <= maxBinarySize:
+			if found, err = goPackages(tr); err != nil {
+				return nil, err
+			}
+		}
+		for i := range found {
+			found[i].Location = location
+		}
+		pkgs = append(pkgs, found...)
+	}
+
+	// OS packages are namespaced by distribution, which is known once the
+	// filesystem has been read.
+	for i, p := range pkgs {
+		if p.Type == "deb" || p.Type == "apk" {
+			pkgs[i].Namespace = distro
+			if distro == "" && p.Type == "deb" {
+				pkgs[i].Namespace = "debian"
+			} else if distro == "" {
+				pkgs[i].Namespace = "alpine"
+			}
+		}
+	}
+	return unique(pkgs), nil
+}
+
+// unique packages, ordered by package URL.  The first location at which a
+// package is found is kept.
+func unique(pkgs []Package) []Package {
+	seen := map[string]bool{}
+	out := []Package{}
+	for _, p := range pkgs {
+		if purl := p.PURL(); !seen[purl] {
+			seen[purl] = true
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].PURL() < out[j].PURL() })
+	return out
+}
+
+// osReleaseID of the distribution, from an os-release file.
+func osReleaseID(r io.Reader) string {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if strings.HasPrefix(s.Text(), "ID=") {
+			return strings.Trim(strings.TrimPrefix(s.Text(), "ID="), `"'`)
+		}
+	}
+	return ""
+}
+
+// paragraphs of "Key: Value" fields, separated by blank lines, as in the
+// dpkg status and apk installed databases and Python package metadata.
+// Continuation lines are ignored.
+func paragraphs(r io.Reader, sep string) (pp []map[string]string) {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	p := map[string]string{}
+	for s.Scan() {
+		line := s.Text()
+		if strings.TrimSpace(line) == "" {
+			if len(p) > 0 {
+				pp = append(pp, p)
+				p = map[string]string{}
+			}
+			continue
+		}
+		if k, v, ok := strings.Cut(line, sep); ok && !strings.HasPrefix(line, " ") {
+			if _, exists := p[k]; !exists {
+				p[k] = strings.TrimSpace(v)
+			}
+		}
+	}
+	if len(p) > 0 {
+		pp = append(pp, p)
+	}
+	return
+}
+
+// dpkgPackages installed, from a dpkg status file.
+func dpkgPackages(r io.Reader) (pkgs []Package) {
+	for _, p := range paragraphs(r, ":") {
+		if p["Package"] == "" || (p["Status"] != "" && !strings.HasSuffix(p["Status"], " installed")) {
+			continue
+		}
+		pkgs = append(pkgs, Package{Type: "deb", Name: p["Package"], Version: p["Version"], Arch: p["Architecture"]})
+	}
+	return
+}
+
+// apkPackages installed, from the apk installed database.
+func apkPackages(r io.Reader) (pkgs []Package) {
+	for _, p := range paragraphs(r, ":") {
+		if p["P"] == "" {
+			continue
+		}
+		pkgs = append(pkgs, Package{Type: "apk", Name: p["P"], Version: p["V"], Arch: p["A"]})
+	}
+	return
+}
+
+// isNodeModule returns true if the package.json at path is that of a package
+// installed in a node_modules directory, scoped or not.
+func isNodeModule(name string) bool {
+	dir := path.Dir(path.Dir(name))
+	if strings.HasPrefix(path.Base(dir), "@") {
+		dir = path.Dir(dir)
+	}
+	return path.Base(dir) == "node_modules"
+}
+
+// npmPackages of a package.json.
+func npmPackages(r io.Reader) []Package {
+	var p struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(r).Decode(&p); err != nil || p.Name == "" {
+		return nil
+	}
+	// The scope of a scoped package is that package URL's namespace.
+	return []Package{{Type: "npm", Name: p.Name, Version: p.Version}}
+}
+
+// pythonPackages of the METADATA of an installed distribution.
+func pythonPackages(r io.Reader) []Package {
+	pp := paragraphs(r, ":")
+	if len(pp) == 0 || pp[0]["Name"] == "" {
+		return nil
+	}
+	return []Package{{Type: "pypi", Name: strings.ToLower(pp[0]["Name"]), Version: pp[0]["Version"]}}
+}
+
+// goPackages of an executable built with Go: its main module, the modules it
+// depends upon and the standard library.  Other executables have none.
+func goPackages(r io.Reader) ([]Package, error) {
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return nil, nil
+	}
+	// ELF, Mach-O and PE executables respectively.
+	if !bytes.Equal(magic, []byte("\x7fELF")) && !bytes.Equal(magic[:3], []byte("\xcf\xfa\xed")) && !bytes.Equal(magic[:2], []byte("MZ")) {
+		return nil, nil
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	bi, err := buildinfo.Read(bytes.NewReader(append(magic, rest...)))
+	if err != nil {
+		return nil, nil // not built with Go, or without module support
+	}
+
+	pkgs := []Package{{Type: "golang", Name: "stdlib", Version: bi.GoVersion}}
+	if bi.Main.Path != "" && bi.Main.Version != "(devel)" {
+		pkgs = append(pkgs, Package{Type: "golang", Name: bi.Main.Path, Version: bi.Main.Version})
+	}
+	for _, dep := range bi.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		pkgs = append(pkgs, Package{Type: "golang", Name: dep.Path, Version: dep.Version})
+	}
+	return pkgs, nil
+}
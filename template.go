@@ -52,6 +52,18 @@ func (t template) Write(ctx context.Context, f *Function) error {
 	if f.Deploy.HealthEndpoints.Readiness == "" {
 		f.Deploy.HealthEndpoints.Readiness = t.config.HealthEndpoints.Readiness
 	}
+	if f.Deploy.HealthEndpoints.Startup == "" {
+		f.Deploy.HealthEndpoints.Startup = t.config.HealthEndpoints.Startup
+	}
+	if f.Deploy.HealthEndpoints.Probes.Liveness == (ProbeOptions{}) {
+		f.Deploy.HealthEndpoints.Probes.Liveness = t.config.HealthEndpoints.Probes.Liveness
+	}
+	if f.Deploy.HealthEndpoints.Probes.Readiness == (ProbeOptions{}) {
+		f.Deploy.HealthEndpoints.Probes.Readiness = t.config.HealthEndpoints.Probes.Readiness
+	}
+	if f.Deploy.HealthEndpoints.Probes.Startup == (ProbeOptions{}) {
+		f.Deploy.HealthEndpoints.Probes.Startup = t.config.HealthEndpoints.Probes.Startup
+	}
 	if f.Invoke == "" && t.config.Invoke != "http" {
 		f.Invoke = t.config.Invoke
 	}
@@ -412,6 +412,37 @@ func TestTemplates_RuntimeManifestBuildEnvs(t *testing.T) {
 	}
 }
 
+// TestTemplates_ManifestStartupProbe ensures that the startup probe of the
+// embedded JVM runtimes, which are slow to start, is included in the final
+// function.
+func TestTemplates_ManifestStartupProbe(t *testing.T) {
+	root := "testdata/testTemplatesManifestStartupProbe"
+	defer Using(t, root)()
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+
+	err := client.Create(fn.Function{
+		Root:     root,
+		Runtime:  "springboot",
+		Template: "http",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.HealthEndpoints.Startup != "/actuator/health" {
+		t.Fatalf("expected startup endpoint '/actuator/health', got %q", f.Deploy.HealthEndpoints.Startup)
+	}
+	want := fn.ProbeOptions{PeriodSeconds: 5, FailureThreshold: 60}
+	if f.Deploy.HealthEndpoints.Probes.Startup != want {
+		t.Fatalf("expected startup probe %+v, got %+v", want, f.Deploy.HealthEndpoints.Probes.Startup)
+	}
+}
+
 // TestTemplates_ManifestBuildEnvs ensures that BuildEnvs specified in a
 // template's manifest are included in the final function.
 func TestTemplates_ManifestBuildEnvs(t *testing.T) {
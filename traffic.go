@@ -0,0 +1,133 @@
+package function
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrTrafficTargetNotFound indicates no traffic target of a deployed function
+// has the requested tag or revision.
+var ErrTrafficTargetNotFound = errors.New("traffic target not found")
+
+// ScaleTraffic returns the targets with their percentages scaled in
+// proportion such that they total the given percentage.  Targets which
+// receive no traffic are omitted unless tagged.
+func ScaleTraffic(targets []TrafficTarget, total int64) []TrafficTarget {
+	sum := sumTraffic(targets)
+	scaled := make([]TrafficTarget, len(targets))
+	var assigned int64
+	for i, t := range targets {
+		if sum > 0 {
+			t.Percent = t.Percent * total / sum
+		} else {
+			t.Percent = 0
+		}
+		assigned += t.Percent
+		scaled[i] = t
+	}
+	// The remainder of rounding down is at most one percent per target which
+	// receives traffic, and is given to them in order.
+	for i := range scaled {
+		if assigned >= total || sum == 0 {
+			break
+		}
+		if targets[i].Percent > 0 {
+			scaled[i].Percent++
+			assigned++
+		}
+	}
+	return pruneTraffic(scaled)
+}
+
+// sumTraffic returns the total percentage of the targets.
+func sumTraffic(targets []TrafficTarget) (sum int64) {
+	for _, t := range targets {
+		sum += t.Percent
+	}
+	return
+}
+
+// pruneTraffic returns the targets without those untagged targets which
+// receive no traffic.
+func pruneTraffic(targets []TrafficTarget) []TrafficTarget {
+	pruned := make([]TrafficTarget, 0, len(targets))
+	for _, t := range targets {
+		if t.Percent > 0 || t.Tag != "" {
+			pruned = append(pruned, t)
+		}
+	}
+	return pruned
+}
+
+// findTraffic returns the index of the target with the given tag, or failing
+// that of the first target of the given revision.  Returns -1 if there is
+// no such target.
+func findTraffic(targets []TrafficTarget, key string) int {
+	for i, t := range targets {
+		if t.Tag == key {
+			return i
+		}
+	}
+	for i, t := range targets {
+		if t.Revision == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// setTraffic returns the current targets with their percentages set to
+// those given, keyed by tag or revision name.  Revisions which are not
+// current targets are added.  Current targets which are not given receive no
+// traffic.
+func setTraffic(current []TrafficTarget, percents map[string]int64) ([]TrafficTarget, error) {
+	var sum int64
+	keys := make([]string, 0, len(percents))
+	for k, p := range percents {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("traffic percent of %v must be between 0 and 100, got %d", k, p)
+		}
+		sum += p
+		keys = append(keys, k)
+	}
+	if sum != 100 {
+		return nil, fmt.Errorf("traffic percentages must total 100, got %d", sum)
+	}
+	sort.Strings(keys)
+
+	targets := make([]TrafficTarget, len(current))
+	for i, t := range current {
+		t.Percent = 0
+		targets[i] = t
+	}
+	for _, k := range keys {
+		if i := findTraffic(targets, k); i >= 0 {
+			targets[i].Percent = percents[k]
+		} else {
+			targets = append(targets, TrafficTarget{Revision: k, Percent: percents[k]})
+		}
+	}
+	return pruneTraffic(targets), nil
+}
+
+// promoteTraffic returns the current targets with the share of traffic of
+// the target with the given tag or revision increased by step percent, and
+// the shares of the others decreased in proportion.  A step of zero (or
+// which would exceed 100%) routes all traffic to the target.
+func promoteTraffic(current []TrafficTarget, key string, step int64) ([]TrafficTarget, error) {
+	i := findTraffic(current, key)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %v", ErrTrafficTargetNotFound, key)
+	}
+	promoted := current[i]
+	others := make([]TrafficTarget, 0, len(current)-1)
+	others = append(others, current[:i]...)
+	others = append(others, current[i+1:]...)
+
+	promoted.Percent += step
+	if step <= 0 || promoted.Percent > 100 || sumTraffic(others) == 0 {
+		promoted.Percent = 100
+	}
+	return append([]TrafficTarget{promoted}, ScaleTraffic(others, 100-promoted.Percent)...), nil
+}
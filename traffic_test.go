@@ -0,0 +1,100 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestScaleTraffic(t *testing.T) {
+	tests := []struct {
+		name     string
+		targets  []TrafficTarget
+		total    int64
+		expected []int64
+	}{
+		{"proportional", []TrafficTarget{{Percent: 60}, {Percent: 40}}, 50, []int64{30, 20}},
+		{"remainder given in order", []TrafficTarget{{Percent: 50}, {Percent: 25}, {Percent: 25}}, 90, []int64{46, 22, 22}},
+		{"rounding", []TrafficTarget{{Percent: 1}, {Percent: 1}, {Percent: 1}}, 100, []int64{34, 33, 33}},
+		{"untagged targets without traffic omitted", []TrafficTarget{{Percent: 100}, {Percent: 0}, {Tag: "a"}}, 80, []int64{80, 0}},
+		{"all traffic removed", []TrafficTarget{{Percent: 50}, {Percent: 50, Tag: "a"}}, 0, []int64{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int64
+			for _, target := range ScaleTraffic(tt.targets, tt.total) {
+				got = append(got, target.Percent)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func Test_promoteTraffic(t *testing.T) {
+	current := []TrafficTarget{
+		{Revision: "f-00003", Latest: true, Tag: "canary", Percent: 10},
+		{Revision: "f-00002", Percent: 60},
+		{Revision: "f-00001", Percent: 30},
+	}
+
+	// A step shifts traffic from the others in proportion
+	got, err := promoteTraffic(current, "canary", 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TrafficTarget{
+		{Revision: "f-00003", Latest: true, Tag: "canary", Percent: 40},
+		{Revision: "f-00002", Percent: 40},
+		{Revision: "f-00001", Percent: 20},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+
+	// Without a step, by revision, all traffic is routed to the target
+	if got, err = promoteTraffic(current, "f-00001", 0); err != nil {
+		t.Fatal(err)
+	}
+	expected = []TrafficTarget{
+		{Revision: "f-00001", Percent: 100},
+		{Revision: "f-00003", Latest: true, Tag: "canary", Percent: 0},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+
+	if _, err = promoteTraffic(current, "other", 10); !errors.Is(err, ErrTrafficTargetNotFound) {
+		t.Fatalf("expected ErrTrafficTargetNotFound, got %v", err)
+	}
+}
+
+func Test_setTraffic(t *testing.T) {
+	current := []TrafficTarget{
+		{Revision: "f-00003", Latest: true, Tag: "canary", Percent: 10},
+		{Revision: "f-00002", Percent: 90},
+	}
+
+	got, err := setTraffic(current, map[string]int64{"canary": 50, "f-00001": 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TrafficTarget{
+		{Revision: "f-00003", Latest: true, Tag: "canary", Percent: 50},
+		{Revision: "f-00001", Percent: 50},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+
+	if _, err = setTraffic(current, map[string]int64{"canary": 50}); err == nil {
+		t.Fatal("expected an error for percentages not totaling 100")
+	}
+	if _, err = setTraffic(current, map[string]int64{"canary": 150, "f-00002": -50}); err == nil {
+		t.Fatal("expected an error for percentages out of range")
+	}
+}
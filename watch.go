@@ -0,0 +1,122 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is the interval for which the filesystem must be
+// quiet before a change is reported by Watch, such that a burst of writes (for
+// example saving several files or a git checkout) results in a single change.
+const DefaultWatchDebounce = 500 * time.Millisecond
+
+// ErrWatchStopped is reported by Watch should watching stop before its
+// context is canceled, after which no further changes are reported.  Other
+// errors reported are transient.
+var ErrWatchStopped = errors.New("watching for changes stopped")
+
+// Watch the function's source for changes until the context is canceled.
+// A value is sent on the returned changes channel once changes have ceased
+// for the debounce interval.  Paths which are not considered by the function's
+// fingerprint (.func, .git and those listed in .funcignore) are not watched.
+// Errors encountered while watching are sent on the returned errors channel;
+// of these only ErrWatchStopped ends the watch.
+func (f Function) Watch(ctx context.Context, debounce time.Duration) (<-chan struct{}, <-chan error, error) {
+	ignored, err := f.Ignorer()
+	if err != nil {
+		return nil, nil, err
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// add a watch to the given directory and its subdirectories
+	add := func(dir string) error {
+		return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(f.Root, path)
+			if err != nil {
+				return err
+			}
+			if rel != "." && ignored(rel, true) {
+				return filepath.SkipDir
+			}
+			return w.Add(path)
+		})
+	}
+	if err = add(f.Root); err != nil {
+		w.Close()
+		return nil, nil, err
+	}
+
+	changes := make(chan struct{}, 1)
+	errs := make(chan error, 1)
+	report := func(err error) {
+		select {
+		case errs <- err:
+		default: // an error is already pending
+		}
+	}
+
+	go func() {
+		defer w.Close()
+		var settled <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-w.Events:
+				if !ok {
+					report(ErrWatchStopped)
+					return
+				}
+				rel, err := filepath.Rel(f.Root, event.Name)
+				if err != nil {
+					report(err)
+					continue
+				}
+				info, err := os.Lstat(event.Name)
+				isDir := err == nil && info.IsDir()
+				if ignored(rel, isDir) {
+					continue
+				}
+				// New directories are watched along with their contents
+				if isDir && event.Op&fsnotify.Create != 0 {
+					if err = add(event.Name); err != nil {
+						report(err)
+					}
+				}
+				settled = time.After(debounce)
+			case <-settled:
+				settled = nil
+				select {
+				case changes <- struct{}{}:
+				default: // a change is already pending
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					report(ErrWatchStopped)
+					return
+				}
+				// Events may have been dropped, so a change is presumed.
+				if errors.Is(err, fsnotify.ErrEventOverflow) {
+					settled = time.After(debounce)
+				}
+				report(err)
+			}
+		}
+	}()
+	return changes, errs, nil
+}
@@ -0,0 +1,76 @@
+//go:build !integration
+// +build !integration
+
+package function
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWatch ensures that changes to a function's source are reported once
+// settled, including those within new directories, and that changes to
+// ignored paths are not.
+func TestWatch(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, IgnoreFile), []byte("ignored/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "ignored"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	debounce := 100 * time.Millisecond
+	changes, errs, err := Function{Root: root}.Watch(ctx, debounce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectChange := func(expected bool) {
+		t.Helper()
+		select {
+		case <-changes:
+			if !expected {
+				t.Fatal("unexpected change reported")
+			}
+		case err := <-errs:
+			t.Fatal(err)
+		case <-time.After(5 * debounce):
+			if expected {
+				t.Fatal("expected change not reported")
+			}
+		}
+	}
+
+	// Changes to ignored paths are not reported
+	if err := os.WriteFile(filepath.Join(root, "ignored", "file"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, RunDataDir), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(false)
+
+	// A burst of changes is reported once
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("a"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectChange(true)
+	expectChange(false)
+
+	// Changes within new directories are reported
+	if err := os.Mkdir(filepath.Join(root, "pkg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(true)
+	if err := os.WriteFile(filepath.Join(root, "pkg", "d.go"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(true)
+}